@@ -3,6 +3,9 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/justtrackio/gosoline/pkg/exec"
@@ -25,9 +28,12 @@ type sqlxQuerier interface {
 // baseQuerier implements the common Querier interface methods using an underlying sqlxQuerier.
 // This eliminates code duplication between client and tx implementations.
 type baseQuerier struct {
-	logger   log.Logger
-	executor exec.Executor
-	db       sqlxQuerier
+	logger       log.Logger
+	executor     exec.Executor
+	db           sqlxQuerier
+	interceptors []Interceptor
+	tracer       *queryTracer // starts a span for every call, nil if tracing is disabled
+	stmts        *stmtCache   // prepared statements of db reused by the calls, nil if the statement cache is disabled
 }
 
 // newBaseQuerier creates a new baseQuerier with the given dependencies.
@@ -39,33 +45,58 @@ func newBaseQuerier(logger log.Logger, executor exec.Executor, db sqlxQuerier) *
 	}
 }
 
+// withInterceptors returns a copy of the baseQuerier which passes every call through the given interceptors.
+func (b *baseQuerier) withInterceptors(interceptors []Interceptor) *baseQuerier {
+	querier := *b
+	querier.interceptors = interceptors
+
+	return &querier
+}
+
+// withDb returns a copy of the baseQuerier which executes the calls on db with the given executor,
+// e.g. for a transaction or a replica of the client. The copy caches the statements prepared on db separately.
+func (b *baseQuerier) withDb(executor exec.Executor, db sqlxQuerier) *baseQuerier {
+	querier := *b
+	querier.executor = executor
+	querier.db = db
+	querier.stmts = b.stmts.fresh()
+
+	return &querier
+}
+
 // Get executes a query that is expected to return at most one row and scans it into dest.
 // If the query returns no rows, it returns sql.ErrNoRows.
 // The query is logged and executed through the configured executor (which may include retry logic).
 func (b *baseQuerier) Get(ctx context.Context, dest any, query string, args ...any) error {
-	b.logger.Debug(ctx, "> %s %q", query, args)
+	result := b.intercept(ctx, OperationGet, query, args, func(ctx context.Context, call *Call) *CallResult {
+		b.logger.Debug(ctx, "> %s %q", call.Query, call.Args)
 
-	_, err := b.executor.Execute(ctx, func(ctx context.Context) (any, error) {
-		return nil, b.db.GetContext(ctx, dest, query, args...)
+		result := b.execute(ctx, func(ctx context.Context) (any, error) {
+			return nil, b.querier(ctx).GetContext(ctx, dest, call.Query, call.Args...)
+		})
+		if result.Err == nil {
+			result.RowsReturned = 1
+		}
+
+		return result
 	})
 
-	return err
+	return callErr(result, OperationGet)
 }
 
 // Exec executes a query without returning any rows (e.g., INSERT, UPDATE, DELETE).
 // It returns a Result containing the number of rows affected and the last insert ID (if applicable).
 // The query is logged and executed through the configured executor (which may include retry logic).
 func (b *baseQuerier) Exec(ctx context.Context, query string, args ...any) (Result, error) {
-	b.logger.Debug(ctx, "> %s %q", query, args)
+	result := b.intercept(ctx, OperationExec, query, args, func(ctx context.Context, call *Call) *CallResult {
+		b.logger.Debug(ctx, "> %s %q", call.Query, call.Args)
 
-	res, err := b.executor.Execute(ctx, func(ctx context.Context) (any, error) {
-		return b.db.ExecContext(ctx, query, args...)
+		return b.execute(ctx, func(ctx context.Context) (any, error) {
+			return b.querier(ctx).ExecContext(ctx, call.Query, call.Args...)
+		})
 	})
-	if err != nil {
-		return nil, err
-	}
 
-	return res.(Result), err
+	return callValue[Result](result, OperationExec)
 }
 
 // NamedExec executes a named query without returning rows using named parameters from a struct or map.
@@ -88,71 +119,159 @@ func (b *baseQuerier) Exec(ctx context.Context, query string, args ...any) (Resu
 //	params := map[string]any{"id": 1, "name": "John"}
 //	result, err := client.NamedExec(ctx, "INSERT INTO users (id, name) VALUES (:id, :name)", params)
 func (b *baseQuerier) NamedExec(ctx context.Context, query string, arg any) (Result, error) {
-	b.logger.Debug(ctx, "> %s %q", query, arg)
+	result := b.intercept(ctx, OperationNamedExec, query, []any{arg}, func(ctx context.Context, call *Call) *CallResult {
+		if len(call.Args) != 1 {
+			return &CallResult{RowsAffected: -1, RowsReturned: -1, Err: fmt.Errorf("%s requires exactly one argument, got %d", OperationNamedExec, len(call.Args))}
+		}
 
-	res, err := b.executor.Execute(ctx, func(ctx context.Context) (any, error) {
-		return b.db.NamedExecContext(ctx, query, arg)
+		b.logger.Debug(ctx, "> %s %q", call.Query, call.Args[0])
+
+		return b.execute(ctx, func(ctx context.Context) (any, error) {
+			return b.db.NamedExecContext(ctx, call.Query, call.Args[0])
+		})
 	})
-	if err != nil {
-		return nil, err
-	}
 
-	return res.(Result), err
+	return callValue[Result](result, OperationNamedExec)
 }
 
 // Prepare creates a prepared statement for later queries or executions.
 // The query is executed through the configured executor (which may include retry logic).
 func (b *baseQuerier) Prepare(ctx context.Context, query string) (*Stmt, error) {
-	res, err := b.executor.Execute(ctx, func(ctx context.Context) (any, error) {
-		return b.db.PreparexContext(ctx, query)
+	result := b.intercept(ctx, OperationPrepare, query, nil, func(ctx context.Context, call *Call) *CallResult {
+		return b.execute(ctx, func(ctx context.Context) (any, error) {
+			return b.db.PreparexContext(ctx, call.Query)
+		})
 	})
-	if err != nil {
-		return nil, err
-	}
 
-	return res.(*Stmt), nil
+	return callValue[*Stmt](result, OperationPrepare)
 }
 
 // Query executes a query that returns rows, returning a Rows object for iteration.
 // The caller is responsible for calling Close on the returned Rows.
 // The query is logged and executed through the configured executor (which may include retry logic).
 func (b *baseQuerier) Query(ctx context.Context, query string, args ...any) (*Rows, error) {
-	b.logger.Debug(ctx, "> %s %q", query, args)
+	result := b.intercept(ctx, OperationQuery, query, args, func(ctx context.Context, call *Call) *CallResult {
+		b.logger.Debug(ctx, "> %s %q", call.Query, call.Args)
 
-	res, err := b.executor.Execute(ctx, func(ctx context.Context) (any, error) {
-		return b.db.QueryxContext(ctx, query, args...)
+		return b.execute(ctx, func(ctx context.Context) (any, error) {
+			return b.querier(ctx).QueryxContext(ctx, call.Query, call.Args...)
+		})
 	})
-	if err != nil {
-		return nil, err
-	}
 
-	return res.(*Rows), err
+	return callValue[*Rows](result, OperationQuery)
 }
 
 // QueryRow executes a query that is expected to return at most one row.
 // The query is logged and executed through the configured executor (which may include retry logic).
+// If an interceptor vetoes the call, the returned row reports the error of the interceptor when it is scanned.
 func (b *baseQuerier) QueryRow(ctx context.Context, query string, args ...any) *sql.Row {
-	b.logger.Debug(ctx, "> %s %q", query, args)
+	result := b.intercept(ctx, OperationQueryRow, query, args, func(ctx context.Context, call *Call) *CallResult {
+		b.logger.Debug(ctx, "> %s %q", call.Query, call.Args)
 
-	res, err := b.executor.Execute(ctx, func(ctx context.Context) (any, error) {
-		return b.db.QueryRowContext(ctx, query, args...), nil
+		return b.execute(ctx, func(ctx context.Context) (any, error) {
+			return b.querier(ctx).QueryRowContext(ctx, call.Query, call.Args...), nil
+		})
 	})
+
+	row, err := callValue[*sql.Row](result, OperationQueryRow)
+	if err == nil && row == nil {
+		err = fmt.Errorf("interceptor returned no row for %s", OperationQueryRow)
+	}
+
 	if err != nil {
-		return nil
+		return errorRow(err)
 	}
 
-	return res.(*sql.Row)
+	return row
 }
 
 // Select executes a query and scans all returned rows into dest (typically a slice).
 // The dest parameter should be a pointer to a slice of structs.
 // The query is logged and executed through the configured executor (which may include retry logic).
 func (b *baseQuerier) Select(ctx context.Context, dest any, query string, args ...any) error {
-	b.logger.Debug(ctx, "> %s %q", query, args)
+	result := b.intercept(ctx, OperationSelect, query, args, func(ctx context.Context, call *Call) *CallResult {
+		b.logger.Debug(ctx, "> %s %q", call.Query, call.Args)
+
+		result := b.execute(ctx, func(ctx context.Context) (any, error) {
+			return nil, b.querier(ctx).SelectContext(ctx, dest, call.Query, call.Args...)
+		})
+		if result.Err == nil {
+			result.RowsReturned = countRows(dest)
+		}
+
+		return result
+	})
 
-	_, err := b.executor.Execute(ctx, func(ctx context.Context) (any, error) {
-		return nil, b.db.SelectContext(ctx, dest, query, args...)
+	return callErr(result, OperationSelect)
+}
+
+// querier returns the querier the statements of a call are executed with, which reuses
+// the prepared statements of the cache unless it is disabled for ctx.
+func (b *baseQuerier) querier(ctx context.Context) sqlxQuerier {
+	if b.stmts == nil || !usesStmtCache(ctx) {
+		return b.db
+	}
+
+	return &cachedQuerier{sqlxQuerier: b.db, cache: b.stmts}
+}
+
+// intercept passes the call through the interceptors, the last one calling invoke, within a span of the call.
+func (b *baseQuerier) intercept(ctx context.Context, operation Operation, query string, args []any, invoke Invoker) *CallResult {
+	builder := builderFromContext(ctx)
+	call := &Call{
+		Operation: operation,
+		Query:     query,
+		Args:      args,
+		Table:     builder.table,
+		statement: builder.statement,
+		querier:   b,
+	}
+
+	ctx, span := b.tracer.startStatementSpan(ctx, call)
+	result := chainInterceptors(b.interceptors, invoke)(ctx, call)
+
+	// the statement is added after the interceptors, which might have rewritten it
+	span.AddMetadata("db.statement", normalizeStatement(call.Query))
+	finishSpan(span, callErr(result, operation))
+
+	return result
+}
+
+// execute runs f with the executor and records its duration, retries and the number of affected rows.
+func (b *baseQuerier) execute(ctx context.Context, f exec.Executable) *CallResult {
+	attempts := 0
+	start := time.Now()
+
+	value, err := b.executor.Execute(ctx, func(ctx context.Context) (any, error) {
+		attempts++
+
+		return f(ctx)
 	})
 
-	return err
+	result := &CallResult{
+		Value:        value,
+		Duration:     time.Since(start),
+		RowsAffected: -1,
+		RowsReturned: -1,
+		Retries:      max(attempts-1, 0),
+		Err:          err,
+	}
+
+	if res, ok := value.(Result); ok && err == nil {
+		if rowsAffected, err := res.RowsAffected(); err == nil {
+			result.RowsAffected = rowsAffected
+		}
+	}
+
+	return result
+}
+
+// countRows returns the number of rows scanned into the destination of Select, which is a pointer to a slice.
+func countRows(dest any) int64 {
+	value := reflect.Indirect(reflect.ValueOf(dest))
+	if value.Kind() != reflect.Slice {
+		return -1
+	}
+
+	return int64(value.Len())
 }
@@ -0,0 +1,340 @@
+package sqlc
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/justtrackio/gosoline/pkg/refl"
+	"github.com/lib/pq"
+)
+
+// loadDataReaderId numbers the reader handlers registered for LOAD DATA LOCAL INFILE.
+var loadDataReaderId atomic.Int64
+
+// mysqlLoadDataEscaper escapes the values of a LOAD DATA file with the default ESCAPED BY '\\'.
+var mysqlLoadDataEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`, "\x00", `\0`)
+
+// bulkLoader loads the records of a single BulkLoad call with the fastest way the database supports.
+type bulkLoader[T any] struct {
+	querier Querier
+	config  *QueryBuilderConfig
+	table   string
+	columns []string
+}
+
+// BulkLoad streams the records into the table and returns the number of loaded rows. The columns
+// are taken from the struct tags of T like Records does. Instead of INSERT statements, BulkLoad uses
+// COPY FROM STDIN on PostgreSQL and LOAD DATA LOCAL INFILE on MySQL, which is considerably faster for
+// hundreds of thousands of rows. Other databases fall back to chunked multi-row INSERTs.
+//
+// All records are loaded inside a single transaction, or inside a savepoint if the client is a Tx already,
+// so either all records are loaded or none of them. The records are read while the statement is running,
+// so the iterator must not use the client itself. On MySQL, the iterator is run in a separate goroutine
+// and the server has to allow local_infile. LOAD DATA LOCAL skips rows with duplicate keys and only warns
+// about values it has to convert, e.g. truncated strings. BulkLoad fails if any row has been skipped,
+// but converted values are loaded as MySQL stored them.
+//
+// Example:
+//
+//	loaded, err := sqlc.BulkLoad(ctx, client, "events", slices.Values(events))
+//	// PostgreSQL: COPY "events" ("id", "name") FROM STDIN
+//	// MySQL:      LOAD DATA LOCAL INFILE 'Reader::sqlc-bulk-load-1' INTO TABLE `events` ... (`id`, `name`)
+//	// otherwise:  INSERT INTO `events` (`id`, `name`) VALUES (?, ?), (?, ?), ...
+func BulkLoad[T any](ctx context.Context, client Querier, table string, records iter.Seq[T]) (int64, error) {
+	if client == nil {
+		return 0, errors.New("no client set for query execution")
+	}
+
+	if table == "" {
+		return 0, errors.New("table name is required")
+	}
+
+	config := querierConfig(client)
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	recordType := reflect.TypeFor[T]()
+	for recordType.Kind() == reflect.Pointer {
+		recordType = recordType.Elem()
+	}
+
+	columns := refl.GetTags(reflect.New(recordType).Interface(), config.StructTag)
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("records of type %s have no %s tags", recordType, config.StructTag)
+	}
+
+	var loaded int64
+	load := func(querier Querier) (err error) {
+		loader := &bulkLoader[T]{
+			querier: querier,
+			config:  config,
+			table:   table,
+			columns: columns,
+		}
+
+		loaded, err = loader.load(ctx, records)
+
+		return err
+	}
+
+	var err error
+	switch querier := client.(type) {
+	case Client:
+		err = querier.WithTx(ctx, func(cttx Tx) error {
+			return load(cttx)
+		})
+	case Tx:
+		err = querier.WithTx(func(cttx Tx) error {
+			return load(cttx)
+		})
+	default:
+		// custom queriers can not start a transaction, so the records are loaded directly
+		err = load(client)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("could not bulk load into table %s: %w", table, err)
+	}
+
+	return loaded, nil
+}
+
+func (l *bulkLoader[T]) load(ctx context.Context, records iter.Seq[T]) (int64, error) {
+	name := ""
+	if dialect := dialectOf(l.config); dialect != nil {
+		name = dialect.Name()
+	}
+
+	switch name {
+	case DialectPostgres:
+		return l.copyFrom(ctx, records)
+	case DialectMysql:
+		return l.loadData(ctx, records)
+	default:
+		return l.insert(ctx, records)
+	}
+}
+
+// copyFrom loads the records with COPY FROM STDIN. lib/pq runs COPY as a prepared statement,
+// which receives a row per Exec and completes the COPY with a final Exec without arguments.
+func (l *bulkLoader[T]) copyFrom(ctx context.Context, records iter.Seq[T]) (loaded int64, err error) {
+	statement := pq.CopyIn(l.table, l.columns...)
+	if schema, table, ok := strings.Cut(l.table, "."); ok {
+		statement = pq.CopyInSchema(schema, table, l.columns...)
+	}
+
+	stmt, err := l.querier.Prepare(ctx, statement)
+	if err != nil {
+		return 0, fmt.Errorf("could not start COPY: %w", err)
+	}
+
+	defer func() {
+		if closeErr := stmt.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("could not close COPY: %w", closeErr)
+		}
+	}()
+
+	for record := range records {
+		var values []any
+		if values, err = l.values(record, loaded); err != nil {
+			return 0, err
+		}
+
+		if _, err = stmt.ExecContext(ctx, values...); err != nil {
+			return 0, fmt.Errorf("could not copy record %d: %w", loaded, err)
+		}
+
+		loaded++
+	}
+
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		return 0, fmt.Errorf("could not complete COPY: %w", err)
+	}
+
+	return loaded, nil
+}
+
+// loadData loads the records with LOAD DATA LOCAL INFILE. The records are written as tab separated
+// rows into a pipe, which the driver reads from the registered reader handler while executing the statement.
+// The loaded rows are the affected rows reported by the server, which skips rows with duplicate keys.
+func (l *bulkLoader[T]) loadData(ctx context.Context, records iter.Seq[T]) (int64, error) {
+	reader, writer := io.Pipe()
+	handler := fmt.Sprintf("sqlc-bulk-load-%d", loadDataReaderId.Add(1))
+
+	mysql.RegisterReaderHandler(handler, func() io.Reader {
+		return reader
+	})
+	defer mysql.DeregisterReaderHandler(handler)
+
+	var wg sync.WaitGroup
+	var loaded int64
+	var writeErr error
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		writeErr = l.writeRows(writer, records, &loaded)
+		_ = writer.CloseWithError(writeErr)
+	}()
+
+	quotedColumns := make([]string, 0, len(l.columns))
+	for _, column := range l.columns {
+		quotedColumns = append(quotedColumns, quoteIdentifier(column, l.config.IdentifierQuote))
+	}
+
+	statement := fmt.Sprintf("LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE %s CHARACTER SET utf8mb4 (%s)",
+		handler, quoteIdentifier(l.table, l.config.IdentifierQuote), strings.Join(quotedColumns, ", "))
+
+	// LOAD DATA can not be prepared and the name of its reader is unique, so it must not be cached
+	res, err := l.querier.Exec(ContextWithoutStmtCache(ctx), statement)
+
+	// stops the writer if the statement failed before reading all rows
+	_ = reader.Close()
+	wg.Wait()
+
+	// an error of the records is the cause of the failed statement, unless the writer was stopped by it
+	if writeErr != nil && !errors.Is(writeErr, io.ErrClosedPipe) {
+		return 0, writeErr
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get the number of loaded rows: %w", err)
+	}
+
+	// the writer has only been stopped early if the server did not read all rows, so their number is unknown then
+	if writeErr == nil && affected != loaded {
+		return 0, fmt.Errorf("only %d of %d records have been loaded, the others have been skipped as duplicates", affected, loaded)
+	}
+
+	return affected, nil
+}
+
+// writeRows writes the records as rows of a LOAD DATA file with the default field and line terminators.
+func (l *bulkLoader[T]) writeRows(w io.Writer, records iter.Seq[T], loaded *int64) error {
+	buffered := bufio.NewWriter(w)
+
+	for record := range records {
+		values, err := l.values(record, *loaded)
+		if err != nil {
+			return err
+		}
+
+		for i, value := range values {
+			if i > 0 {
+				_ = buffered.WriteByte('\t')
+			}
+
+			var field string
+			if field, err = loadDataField(value); err != nil {
+				return fmt.Errorf("could not encode column %s of record %d: %w", l.columns[i], *loaded, err)
+			}
+
+			_, _ = buffered.WriteString(field)
+		}
+
+		if err = buffered.WriteByte('\n'); err != nil {
+			return err
+		}
+
+		*loaded++
+	}
+
+	return buffered.Flush()
+}
+
+// insert loads the records with multi-row INSERTs of as many rows as the bind parameter limit allows.
+func (l *bulkLoader[T]) insert(ctx context.Context, records iter.Seq[T]) (int64, error) {
+	chunkSize := max(maxPlaceholders(l.config)/len(l.columns), 1)
+	chunk := make([]any, 0, min(chunkSize, 1024))
+
+	var loaded int64
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		if _, err := Into(l.table).WithConfig(l.config).WithClient(l.querier).Records(chunk).Exec(ctx); err != nil {
+			return fmt.Errorf("could not insert records %d to %d: %w", loaded, loaded+int64(len(chunk))-1, err)
+		}
+
+		loaded += int64(len(chunk))
+		chunk = chunk[:0]
+
+		return nil
+	}
+
+	for record := range records {
+		chunk = append(chunk, record)
+
+		if len(chunk) == chunkSize {
+			if err := flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return 0, err
+	}
+
+	return loaded, nil
+}
+
+func (l *bulkLoader[T]) values(record T, index int64) ([]any, error) {
+	values, err := extractValuesFromStruct(record, l.columns, l.config.StructTag)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract values from record %d: %w", index, err)
+	}
+
+	return values, nil
+}
+
+// loadDataField encodes a value as field of a LOAD DATA file, NULL is written as \N.
+func loadDataField(value any) (string, error) {
+	converted, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := converted.(type) {
+	case nil:
+		return `\N`, nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+
+		return "0", nil
+	case []byte:
+		return mysqlLoadDataEscaper.Replace(string(v)), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case string:
+		return mysqlLoadDataEscaper.Replace(v), nil
+	case time.Time:
+		return v.UTC().Format("2006-01-02 15:04:05.999999"), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", converted)
+	}
+}
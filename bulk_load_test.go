@@ -0,0 +1,104 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gosoline-project/sqlc"
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/exec"
+	logmocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBulkLoadClient(t *testing.T, dialect sqlc.Dialect, quote string) (sqlc.Client, sqlmock.Sqlmock) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	config := &sqlc.QueryBuilderConfig{StructTag: "db", Placeholder: "?", IdentifierQuote: quote, Dialect: dialect}
+	logger := logmocks.NewLoggerMock(logmocks.WithMockAll, logmocks.WithTestingT(t))
+	client := sqlc.NewClientWithInterfaces(logger, sqlx.NewDb(mockDB, "sqlmock"), exec.NewDefaultExecutor(), config)
+
+	t.Cleanup(func() {
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	return client, sqlMock
+}
+
+func TestBulkLoad_PostgresCopy(t *testing.T) {
+	client, sqlMock := newBulkLoadClient(t, sqlc.NewPostgresDialect(), `"`)
+	users := []*TestUser{
+		{ID: 1, Name: "John", Email: "john@example.com"},
+		{ID: 2, Name: "Jane", Email: "jane@example.com"},
+	}
+
+	sqlMock.ExpectBegin()
+	copyIn := sqlMock.ExpectPrepare(regexp.QuoteMeta(`COPY "users" ("id", "name", "email") FROM STDIN`)).WillBeClosed()
+	copyIn.ExpectExec().WithArgs(1, "John", "john@example.com").WillReturnResult(sqlmock.NewResult(0, 0))
+	copyIn.ExpectExec().WithArgs(2, "Jane", "jane@example.com").WillReturnResult(sqlmock.NewResult(0, 0))
+	copyIn.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 2))
+	sqlMock.ExpectCommit()
+
+	loaded, err := sqlc.BulkLoad(context.Background(), client, "users", slices.Values(users))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), loaded)
+}
+
+func TestBulkLoad_PostgresCopySchema(t *testing.T) {
+	client, sqlMock := newBulkLoadClient(t, sqlc.NewPostgresDialect(), `"`)
+	copyErr := errors.New("permission denied")
+
+	sqlMock.ExpectBegin()
+	copyIn := sqlMock.ExpectPrepare(regexp.QuoteMeta(`COPY "audit"."users" ("id", "name", "email") FROM STDIN`)).WillBeClosed()
+	copyIn.ExpectExec().WithArgs(1, "John", "").WillReturnError(copyErr)
+	sqlMock.ExpectRollback()
+
+	_, err := sqlc.BulkLoad(context.Background(), client, "audit.users", slices.Values([]TestUser{{ID: 1, Name: "John"}}))
+	assert.ErrorIs(t, err, copyErr)
+	assert.EqualError(t, err, "could not bulk load into table audit.users: could not copy record 0: permission denied")
+}
+
+func TestBulkLoad_MysqlLoadData(t *testing.T) {
+	client, sqlMock := newBulkLoadClient(t, sqlc.NewMysqlDialect(), "`")
+	loadErr := errors.New("Loading local data is disabled")
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("LOAD DATA LOCAL INFILE 'Reader::sqlc-bulk-load-[0-9]+' INTO TABLE `users` CHARACTER SET utf8mb4 \\(`id`, `name`, `email`\\)").
+		WillReturnError(loadErr)
+	sqlMock.ExpectRollback()
+
+	// the statement fails without reading the rows, the writer of the rows has to be stopped nonetheless
+	_, err := sqlc.BulkLoad(context.Background(), client, "users", slices.Values([]TestUser{{ID: 1, Name: "John"}}))
+	assert.ErrorIs(t, err, loadErr)
+}
+
+func TestBulkLoad_MysqlLoadDataRowsAffected(t *testing.T) {
+	client, sqlMock := newBulkLoadClient(t, sqlc.NewMysqlDialect(), "`")
+	users := []TestUser{
+		{ID: 1, Name: "John", Email: "john@example.com"},
+		{ID: 2, Name: "Jane", Email: "jane@example.com"},
+	}
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("LOAD DATA LOCAL INFILE 'Reader::sqlc-bulk-load-[0-9]+' INTO TABLE `users` CHARACTER SET utf8mb4 \\(`id`, `name`, `email`\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	sqlMock.ExpectCommit()
+
+	// the mock does not read the rows, so the number of loaded rows can only be the one reported by the statement
+	loaded, err := sqlc.BulkLoad(context.Background(), client, "users", slices.Values(users))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), loaded)
+}
+
+func TestBulkLoad_NoTags(t *testing.T) {
+	client, _ := newBulkLoadClient(t, sqlc.NewMysqlDialect(), "`")
+
+	_, err := sqlc.BulkLoad(context.Background(), client, "numbers", slices.Values([]struct{ Value int }{{Value: 1}}))
+	assert.EqualError(t, err, "records of type struct { Value int } have no db tags")
+}
@@ -3,13 +3,18 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/justtrackio/gosoline/pkg/appctx"
 	"github.com/justtrackio/gosoline/pkg/cfg"
 	"github.com/justtrackio/gosoline/pkg/exec"
 	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/justtrackio/gosoline/pkg/metric"
+	"github.com/justtrackio/gosoline/pkg/tracing"
 )
 
 type (
@@ -49,7 +54,11 @@ type (
 		// WithTx executes the given function within a transaction.
 		// If the function returns an error, the transaction is rolled back.
 		// If the function completes successfully, the transaction is committed.
+		// If ctx is a Tx or carries an ambient transaction of this client, the function is executed within a savepoint of it instead.
 		WithTx(ctx context.Context, fn func(cttx Tx) error, ops ...*sql.TxOptions) error
+		// WithInterceptors returns a client sharing the connection of this client, which passes every call
+		// of it and its transactions through the interceptors of this client followed by the given ones.
+		WithInterceptors(interceptors ...Interceptor) Client
 	}
 
 	// Result represents the result of an Exec operation (rows affected, last insert ID).
@@ -66,8 +75,12 @@ var _ Client = (*client)(nil)
 
 type client struct {
 	*baseQuerier
-	db       *sqlx.DB
-	qbConfig *QueryBuilderConfig
+	db           *sqlx.DB
+	qbConfig     *QueryBuilderConfig
+	txExecutor   exec.Executor // re-runs whole transactions in WithTx, nil if disabled
+	replicas     *replicaPool  // receives reads outside of transactions, nil without replicas
+	metricWriter metric.Writer
+	name         string // name of the client in the dimensions of its metrics
 }
 
 // ProvideClient provides a client from context.
@@ -125,26 +138,104 @@ func NewClientWithSettings(ctx context.Context, config cfg.Config, logger log.Lo
 		StructTag:       dbStructTag,
 		Placeholder:     driver.GetPlaceholder(),
 		IdentifierQuote: driver.GetQuote(),
+		Dialect:         driver.GetDialect(),
 	}
 
-	if !settings.Retry.Enabled {
-		return NewClientWithInterfaces(logger, connection, executor, qbConfig), nil
+	if settings.CursorKey != "" {
+		qbConfig.CursorKey = []byte(settings.CursorKey)
 	}
 
-	if executor, err = NewExecutor(config, logger, name, ExecutorBackoffType(name)); err != nil {
-		return nil, fmt.Errorf("can not create executor for sql client %s: %w", name, err)
+	if settings.Retry.Enabled {
+		if executor, err = NewExecutor(config, logger, name, ExecutorBackoffType(name)); err != nil {
+			return nil, fmt.Errorf("can not create executor for sql client %s: %w", name, err)
+		}
+	}
+
+	var txExecutor exec.Executor
+	if settings.Retry.Transactions {
+		if txExecutor, err = NewTxExecutor(config, logger, name, ExecutorBackoffType(name)); err != nil {
+			return nil, fmt.Errorf("can not create transaction executor for sql client %s: %w", name, err)
+		}
+	}
+
+	var tracer tracing.Tracer
+	if tracer, err = tracing.ProvideTracer(ctx, config, logger); err != nil {
+		return nil, fmt.Errorf("can not create tracer for sql client %s: %w", name, err)
+	}
+
+	client := NewClientWithTxExecutor(logger, connection, executor, txExecutor, qbConfig)
+	client.tracer = newQueryTracer(tracer, settings)
+	client.name = name
+
+	if settings.StatementCacheSize > 0 {
+		client.stmts = newStmtCache(settings.StatementCacheSize, client.metricWriter, name)
+	}
+
+	if len(settings.Replicas.Uris) > 0 {
+		var replicas []*sqlx.DB
+		if replicas, err = NewReplicaConnectionsFromSettings(logger, name, settings); err != nil {
+			return nil, fmt.Errorf("can not connect to sql replicas: %w", err)
+		}
+		client.replicas = newReplicaPool(client.baseQuerier, replicas, settings.Replicas.EvictionDuration)
+	}
+
+	interceptors := []Interceptor{NewQueryMetricsInterceptor(client.metricWriter, name)}
+	if settings.SlowQueryThreshold > 0 {
+		interceptors = append(interceptors, NewSlowQueryInterceptor(logger, settings))
 	}
 
-	return NewClientWithInterfaces(logger, connection, executor, qbConfig), nil
+	return client.withInterceptors(interceptors...), nil
 }
 
 // NewClientWithInterfaces creates a new SQL client with provided interfaces.
 // This is useful for testing or when you want to provide custom implementations.
 func NewClientWithInterfaces(logger log.Logger, connection *sqlx.DB, executor exec.Executor, qbConfig *QueryBuilderConfig) *client {
+	return NewClientWithTxExecutor(logger, connection, executor, nil, qbConfig)
+}
+
+// NewClientWithReplicas creates a new SQL client with provided interfaces, which sends reads outside
+// of transactions to the replicas. A replica failing with a connection error is evicted for the
+// eviction duration and the read is repeated on the primary.
+func NewClientWithReplicas(logger log.Logger, connection *sqlx.DB, replicas []*sqlx.DB, executor exec.Executor, evictionDuration time.Duration, qbConfig *QueryBuilderConfig) *client {
+	client := NewClientWithInterfaces(logger, connection, executor, qbConfig)
+	client.replicas = newReplicaPool(client.baseQuerier, replicas, evictionDuration)
+
+	return client
+}
+
+// NewClientWithTracer creates a new SQL client with provided interfaces, which starts a span for every call
+// of it and its transactions. The spans are annotated with the driver and database of the settings.
+// Statements run by WithTx are nested under a span of the transaction.
+func NewClientWithTracer(logger log.Logger, connection *sqlx.DB, executor exec.Executor, tracer tracing.Tracer, settings *Settings, qbConfig *QueryBuilderConfig) *client {
+	client := NewClientWithInterfaces(logger, connection, executor, qbConfig)
+	client.tracer = newQueryTracer(tracer, settings)
+
+	return client
+}
+
+// NewClientWithStatementCache creates a new SQL client with provided interfaces, which prepares the statements
+// of Get, Exec, Query, QueryRow and Select once and reuses them for later calls with the same SQL. Up to size
+// statements are kept per connection pool and transaction, the least recently used one is closed when the
+// cache is full. Hits, misses and evictions are written as DbStmtCacheCount metrics for the client name.
+func NewClientWithStatementCache(logger log.Logger, connection *sqlx.DB, executor exec.Executor, metricWriter metric.Writer, name string, size int, qbConfig *QueryBuilderConfig) *client {
+	client := NewClientWithInterfaces(logger, connection, executor, qbConfig)
+	client.metricWriter = metricWriter
+	client.name = name
+	client.stmts = newStmtCache(size, metricWriter, name)
+
+	return client
+}
+
+// NewClientWithTxExecutor creates a new SQL client with provided interfaces, which re-runs the
+// callbacks of WithTx with a fresh transaction if the txExecutor decides to retry the returned error.
+// Statements inside such a transaction are not retried individually. A nil txExecutor disables re-runs.
+func NewClientWithTxExecutor(logger log.Logger, connection *sqlx.DB, executor exec.Executor, txExecutor exec.Executor, qbConfig *QueryBuilderConfig) *client {
 	return &client{
-		baseQuerier: newBaseQuerier(logger, executor, connection),
-		db:          connection,
-		qbConfig:    qbConfig,
+		baseQuerier:  newBaseQuerier(logger, executor, connection),
+		db:           connection,
+		qbConfig:     qbConfig,
+		txExecutor:   txExecutor,
+		metricWriter: metric.NewWriter(),
 	}
 }
 
@@ -154,42 +245,270 @@ func (c *client) Q() *QueryBuilder {
 	return NewQueryBuilder(c, c.qbConfig)
 }
 
+// Get executes a query that is expected to return at most one row and scans it into dest.
+// The query runs inside the ambient transaction of ctx, if there is one (see ContextWithTx),
+// otherwise on a replica if the client has any and the query does not write rows.
+func (c *client) Get(ctx context.Context, dest any, query string, args ...any) error {
+	if tx, ok := c.txFromContext(ctx); ok {
+		return tx.Get(ctx, dest, query, args...)
+	}
+
+	return c.read(ctx, query, func(querier Querier) error {
+		return querier.Get(ctx, dest, query, args...)
+	})
+}
+
+// Exec executes a query without returning any rows.
+// The query runs inside the ambient transaction of ctx, if there is one (see ContextWithTx).
+func (c *client) Exec(ctx context.Context, query string, args ...any) (Result, error) {
+	if tx, ok := c.txFromContext(ctx); ok {
+		return tx.Exec(ctx, query, args...)
+	}
+
+	return c.baseQuerier.Exec(ctx, query, args...)
+}
+
+// NamedExec executes a named query without returning rows.
+// The query runs inside the ambient transaction of ctx, if there is one (see ContextWithTx).
+func (c *client) NamedExec(ctx context.Context, query string, arg any) (Result, error) {
+	if tx, ok := c.txFromContext(ctx); ok {
+		return tx.NamedExec(ctx, query, arg)
+	}
+
+	return c.baseQuerier.NamedExec(ctx, query, arg)
+}
+
+// Prepare creates a prepared statement for later queries or executions.
+// The statement is bound to the ambient transaction of ctx, if there is one (see ContextWithTx).
+func (c *client) Prepare(ctx context.Context, query string) (*Stmt, error) {
+	if tx, ok := c.txFromContext(ctx); ok {
+		return tx.Prepare(ctx, query)
+	}
+
+	return c.baseQuerier.Prepare(ctx, query)
+}
+
+// Query executes a query that returns rows.
+// The query runs inside the ambient transaction of ctx, if there is one (see ContextWithTx),
+// otherwise on a replica if the client has any and the query does not write rows.
+func (c *client) Query(ctx context.Context, query string, args ...any) (*Rows, error) {
+	if tx, ok := c.txFromContext(ctx); ok {
+		return tx.Query(ctx, query, args...)
+	}
+
+	var rows *Rows
+	err := c.read(ctx, query, func(querier Querier) error {
+		var err error
+		rows, err = querier.Query(ctx, query, args...)
+
+		return err
+	})
+
+	return rows, err
+}
+
+// QueryRow executes a query that is expected to return at most one row.
+// The query runs inside the ambient transaction of ctx, if there is one (see ContextWithTx),
+// otherwise on a replica if the client has any and the query does not write rows. As the error
+// of the row is only reported when scanning it, a failing replica is not evicted by QueryRow.
+func (c *client) QueryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	if tx, ok := c.txFromContext(ctx); ok {
+		return tx.QueryRow(ctx, query, args...)
+	}
+
+	if replica := c.pickReplica(ctx, query); replica != nil {
+		return replica.QueryRow(ctx, query, args...)
+	}
+
+	return c.baseQuerier.QueryRow(ctx, query, args...)
+}
+
+// Select executes a query and scans all returned rows into dest.
+// The query runs inside the ambient transaction of ctx, if there is one (see ContextWithTx),
+// otherwise on a replica if the client has any and the query does not write rows.
+func (c *client) Select(ctx context.Context, dest any, query string, args ...any) error {
+	if tx, ok := c.txFromContext(ctx); ok {
+		return tx.Select(ctx, dest, query, args...)
+	}
+
+	return c.read(ctx, query, func(querier Querier) error {
+		return querier.Select(ctx, dest, query, args...)
+	})
+}
+
+// read runs the read on a replica, or on the primary if there is no healthy replica, reads
+// from the primary were requested with ContextWithPrimary or the query writes rows (see pickReplica).
+// If the replica fails with a connection error, it is evicted and the read is repeated on the primary.
+func (c *client) read(ctx context.Context, query string, read func(querier Querier) error) error {
+	replica := c.pickReplica(ctx, query)
+	if replica == nil {
+		return read(c.baseQuerier)
+	}
+
+	err := read(replica)
+	if !c.replicas.evictOnError(ctx, replica, err) {
+		return err
+	}
+
+	return read(c.baseQuerier)
+}
+
+// pickReplica returns the replica to run the query on, or nil if it has to run on the primary.
+// Statements starting with INSERT, REPLACE, UPDATE or DELETE write rows even if they return some, e.g.
+// with a RETURNING clause, so they always run on the primary. Other writes, e.g. a WITH clause followed
+// by an UPDATE, have to be sent to the primary with ContextWithPrimary, as ExecReturning of the builders does.
+func (c *client) pickReplica(ctx context.Context, query string) *replica {
+	switch statementKind(query) {
+	case statementInsert, statementUpdate, statementDelete:
+		return nil
+	}
+
+	return c.replicas.pick(ctx)
+}
+
+// txFromContext returns the ambient transaction of ctx if it has been started on the connection of this client.
+// Transactions of other clients are ignored, so the statements of this client never end up in a transaction
+// on another database. Tx implementations of other packages, e.g. mocks, are always used.
+func (c *client) txFromContext(ctx context.Context) (Tx, bool) {
+	ambient, ok := TxFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	if t, ok := ambient.(*tx); ok && t.db != c.db {
+		return nil, false
+	}
+
+	return ambient, true
+}
+
 func (c *client) BeginTx(ctx context.Context, ops ...*sql.TxOptions) (Tx, error) {
+	cttx, err := c.beginTx(ctx, c.executor, ops...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cttx, nil
+}
+
+// beginTx starts a new transaction whose statements are executed with the given executor.
+func (c *client) beginTx(ctx context.Context, executor exec.Executor, ops ...*sql.TxOptions) (*tx, error) {
 	c.logger.Debug(ctx, "start tx")
 
 	if len(ops) == 0 {
 		ops = append(ops, &sql.TxOptions{})
 	}
 
-	res, err := c.executor.Execute(ctx, func(ctx context.Context) (any, error) {
+	spanCtx, span := c.tracer.startSpan(ctx, spanNameBeginTx)
+	res, err := c.executor.Execute(spanCtx, func(ctx context.Context) (any, error) {
 		return c.db.BeginTxx(ctx, ops[0])
 	})
+	finishSpan(span, err)
+
 	if err != nil {
 		return nil, err
 	}
 
-	return newTx(ctx, c.logger, c.executor, res.(*sqlx.Tx)), err
+	txx := res.(*sqlx.Tx)
+
+	return newTx(ctx, c.baseQuerier.withDb(executor, txx), c.db, txx, c.qbConfig), nil
+}
+
+// WithInterceptors returns a client sharing the connections of this client, which passes every call
+// of it, its transactions and its replicas through the interceptors of this client followed by the given ones.
+// The first interceptor is the outermost one. Closing either client closes the shared connections.
+//
+// Example:
+//
+//	client = client.WithInterceptors(func(ctx context.Context, call *sqlc.Call, next sqlc.Invoker) *sqlc.CallResult {
+//		if call.Operation == sqlc.OperationExec && strings.HasPrefix(call.Query, "DROP") {
+//			return &sqlc.CallResult{Err: errors.New("dropping tables is not allowed")}
+//		}
+//
+//		return next(ctx, call)
+//	})
+func (c *client) WithInterceptors(interceptors ...Interceptor) Client {
+	return c.withInterceptors(interceptors...)
 }
 
-// Close closes the database connection and releases any associated resources.
+func (c *client) withInterceptors(interceptors ...Interceptor) *client {
+	chain := append(slices.Clone(c.interceptors), interceptors...)
+
+	return &client{
+		baseQuerier:  c.baseQuerier.withInterceptors(chain),
+		db:           c.db,
+		qbConfig:     c.qbConfig,
+		txExecutor:   c.txExecutor,
+		replicas:     c.replicas.withInterceptors(chain),
+		metricWriter: c.metricWriter,
+		name:         c.name,
+	}
+}
+
+// Close closes the database connections including the ones to the replicas and releases any associated resources.
 func (c *client) Close() error {
-	return c.db.Close()
+	c.stmts.close()
+
+	return errors.Join(c.db.Close(), c.replicas.close())
 }
 
 // WithTx executes the given function within a transaction.
 // If the function returns an error, the transaction is rolled back.
 // If the function completes successfully, the transaction is committed.
+//
+// Functions registered with Tx.OnCommit are called after the commit, functions registered with
+// Tx.OnRollback after the rollback and receive the error returned by the function.
+//
+// If ctx is a Tx or carries an ambient transaction of this client (see ContextWithTx), no new transaction is started.
+// Instead, the function is executed within a savepoint of that transaction (see Tx.WithTx) and the options are ignored.
+//
+// If transaction retries are enabled (retry.transactions in the settings), the function is run again
+// with a fresh transaction after a deadlock or serialization failure, following the configured backoff.
+// The function must therefore be safe to run more than once.
 func (c *client) WithTx(ctx context.Context, fn func(cttx Tx) error, ops ...*sql.TxOptions) error {
-	var err error
-	var cttx Tx
+	if outer, ok := c.txFromContext(ctx); ok {
+		return outer.WithTx(fn)
+	}
+
+	if c.txExecutor == nil {
+		return c.runTx(ctx, c.executor, fn, ops...)
+	}
+
+	attempts := 0
+	_, err := c.txExecutor.Execute(ctx, func(ctx context.Context) (any, error) {
+		attempts++
+
+		// the statements are not retried on their own, as an error aborts the whole transaction
+		return nil, c.runTx(ctx, exec.NewDefaultExecutor(), fn, ops...)
+	})
+
+	if attempts > 1 {
+		c.logger.WithFields(log.Fields{
+			"attempts": attempts,
+		}).Info(ctx, "finished transaction after %d attempts", attempts)
+	}
+	publishTxAttemptsMetric(ctx, c.metricWriter, c.name, attempts)
+
+	return err
+}
+
+// runTx runs the function within a single transaction, rolling it back if the function fails.
+// The statements of the transaction are traced as children of a transaction span.
+func (c *client) runTx(ctx context.Context, executor exec.Executor, fn func(cttx Tx) error, ops ...*sql.TxOptions) (err error) {
+	var cttx *tx
+
+	ctx, span := c.tracer.startSpan(ctx, spanNameTransaction)
+	defer func() {
+		finishSpan(span, err)
+	}()
 
-	if cttx, err = c.BeginTx(ctx, ops...); err != nil {
+	if cttx, err = c.beginTx(ctx, executor, ops...); err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	if err = fn(cttx); err != nil {
-		if rbErr := cttx.Rollback(); rbErr != nil {
-			return fmt.Errorf("transaction rollback failed: %w (original error: %v)", rbErr, err)
+		if rbErr := cttx.rollback(err); rbErr != nil {
+			return fmt.Errorf("transaction rollback failed: %w (original error: %w)", rbErr, err)
 		}
 
 		return err
@@ -4,14 +4,26 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/gosoline-project/sqlc"
 	"github.com/jmoiron/sqlx"
 	"github.com/justtrackio/gosoline/pkg/exec"
+	"github.com/justtrackio/gosoline/pkg/log"
 	logmocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/metric"
+	metricMocks "github.com/justtrackio/gosoline/pkg/metric/mocks"
+	"github.com/justtrackio/gosoline/pkg/tracing"
+	tracingMocks "github.com/justtrackio/gosoline/pkg/tracing/mocks"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -423,6 +435,7 @@ func (s *ClientTestSuite) TestWithTx_RollbackFailureIncludesOriginalError() {
 	s.Require().Error(err)
 	s.Assert().Contains(err.Error(), "transaction rollback failed")
 	s.Assert().Contains(err.Error(), "function error")
+	s.Assert().ErrorIs(err, fnErr)
 }
 
 func (s *ClientTestSuite) TestWithTx_MultipleOperations() {
@@ -488,10 +501,650 @@ func (s *ClientTestSuite) TestWithTx_UsesTransactionForQueries() {
 	s.Assert().Equal("John", user.Name)
 }
 
+func (s *ClientTestSuite) TestWithTx_LockingSelect() {
+	rows := sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com")
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT `id`, `name`, `email` FROM `users` WHERE id = \\? FOR UPDATE SKIP LOCKED").
+		WithArgs(1).
+		WillReturnRows(rows)
+	s.mock.ExpectCommit()
+
+	var users []User
+	err := s.client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		return tx.Q().From("users").Where("id = ?", 1).ForUpdate().SkipLocked().Select(s.ctx, &users)
+	})
+
+	s.Require().NoError(err)
+	s.Assert().Equal([]User{{ID: 1, Name: "John", Email: "john@example.com"}}, users)
+}
+
+func (s *ClientTestSuite) TestWithTx_NestedCommitsSavepoint() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	err := s.client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		// a nested call of the client with the tx as context uses a savepoint
+		return s.client.WithTx(tx, func(inner sqlc.Tx) error {
+			_, err := inner.Exec(s.ctx, "INSERT INTO users (name) VALUES (?)", "John")
+
+			return err
+		})
+	})
+
+	s.Require().NoError(err)
+}
+
+func (s *ClientTestSuite) TestWithTx_NestedErrorRollsBackToSavepoint() {
+	fnErr := errors.New("inner function failed")
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO users").WithArgs("John").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec("INSERT INTO users").WithArgs("Jane").WillReturnResult(sqlmock.NewResult(2, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	err := s.client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		if _, err := tx.Exec(s.ctx, "INSERT INTO users (name) VALUES (?)", "John"); err != nil {
+			return err
+		}
+
+		innerErr := tx.WithTx(func(inner sqlc.Tx) error {
+			if _, err := inner.Exec(s.ctx, "INSERT INTO users (name) VALUES (?)", "Jane"); err != nil {
+				return err
+			}
+
+			return fnErr
+		})
+		s.Assert().ErrorIs(innerErr, fnErr)
+
+		return nil
+	})
+
+	s.Require().NoError(err)
+}
+
+func (s *ClientTestSuite) TestWithTx_CommitHooks() {
+	var calls []string
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	err := s.client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		tx.OnCommit(func(ctx context.Context) {
+			calls = append(calls, "commit 1")
+		})
+		tx.OnRollback(func(ctx context.Context, err error) {
+			calls = append(calls, "rollback")
+		})
+
+		if _, err := tx.Exec(tx, "INSERT INTO users (name) VALUES (?)", "John"); err != nil {
+			return err
+		}
+
+		tx.OnCommit(func(ctx context.Context) {
+			calls = append(calls, "commit 2")
+		})
+		s.Assert().Empty(calls, "hooks must not run before the commit")
+
+		return nil
+	})
+
+	s.Require().NoError(err)
+	s.Assert().Equal([]string{"commit 1", "commit 2"}, calls)
+}
+
+func (s *ClientTestSuite) TestWithTx_RollbackHooks() {
+	fnErr := errors.New("function failed")
+	var rollbackErr error
+	committed := false
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectRollback()
+
+	err := s.client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		tx.OnCommit(func(ctx context.Context) {
+			committed = true
+		})
+		tx.OnRollback(func(ctx context.Context, err error) {
+			rollbackErr = err
+		})
+
+		return fnErr
+	})
+
+	s.Require().ErrorIs(err, fnErr)
+	s.Assert().False(committed)
+	s.Assert().ErrorIs(rollbackErr, fnErr)
+}
+
+func (s *ClientTestSuite) TestWithTx_HooksOfRolledBackSavepoint() {
+	fnErr := errors.New("inner function failed")
+	var calls []string
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	err := s.client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		tx.OnCommit(func(ctx context.Context) {
+			calls = append(calls, "outer commit")
+		})
+
+		innerErr := tx.WithTx(func(inner sqlc.Tx) error {
+			inner.OnCommit(func(ctx context.Context) {
+				calls = append(calls, "inner commit")
+			})
+			inner.OnRollback(func(ctx context.Context, err error) {
+				calls = append(calls, "inner rollback: "+err.Error())
+			})
+
+			return fnErr
+		})
+		s.Assert().ErrorIs(innerErr, fnErr)
+
+		return nil
+	})
+
+	s.Require().NoError(err)
+	s.Assert().Equal([]string{"inner rollback: inner function failed", "outer commit"}, calls)
+}
+
+func (s *ClientTestSuite) TestTx_Savepoints() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT `before_import`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT `before_import`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT `before_import`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	tx, err := s.client.BeginTx(s.ctx)
+	s.Require().NoError(err)
+
+	s.Require().NoError(tx.Savepoint("before_import"))
+	s.Require().NoError(tx.RollbackTo("before_import"))
+	s.Require().NoError(tx.Release("before_import"))
+
+	err = tx.Savepoint("before import; DROP TABLE users")
+	s.Assert().Error(err)
+	s.Assert().Contains(err.Error(), "invalid savepoint name")
+
+	s.Require().NoError(tx.Rollback())
+}
+
+func (s *ClientTestSuite) TestContextWithTx_ClientUsesAmbientTx() {
+	rows := sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com")
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` WHERE id = ? FOR UPDATE")).
+		WithArgs(1).
+		WillReturnRows(rows)
+	s.mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec("UPDATE `users`").WithArgs("Jane", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	err := s.client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		ctx := sqlc.ContextWithTx(s.ctx, tx)
+
+		// the client finds the transaction in the context, so the locking read is allowed
+		var user User
+		if err := s.client.Q().From("users").Where("id = ?", 1).ForUpdate().Get(ctx, &user); err != nil {
+			return err
+		}
+
+		// a nested WithTx with the ambient transaction uses a savepoint instead of a new transaction
+		return s.client.WithTx(ctx, func(tx sqlc.Tx) error {
+			_, err := s.client.Q().Update("users").Set("name", "Jane").Where("id = ?", user.ID).Exec(ctx)
+
+			return err
+		})
+	})
+
+	s.Require().NoError(err)
+}
+
+func (s *ClientTestSuite) TestContextWithTx_IgnoresTxOfOtherClient() {
+	otherDB, otherMock, err := sqlmock.New()
+	s.Require().NoError(err)
+	defer func() {
+		s.Assert().NoError(otherMock.ExpectationsWereMet())
+	}()
+
+	logger := logmocks.NewLoggerMock(logmocks.WithTestingT(s.T()), logmocks.WithMockAll)
+	other := sqlc.NewClientWithInterfaces(logger, sqlx.NewDb(otherDB, "sqlmock"), exec.NewDefaultExecutor(), sqlc.DefaultConfig())
+
+	// the statements of the other client run on its own database, with a transaction of their own for WithTx
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("UPDATE orders").WillReturnResult(sqlmock.NewResult(0, 1))
+	otherMock.ExpectExec("INSERT INTO audit_logs").WillReturnResult(sqlmock.NewResult(1, 1))
+	otherMock.ExpectBegin()
+	otherMock.ExpectExec("INSERT INTO audit_logs").WillReturnResult(sqlmock.NewResult(2, 1))
+	otherMock.ExpectCommit()
+	s.mock.ExpectCommit()
+
+	err = s.client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		ctx := sqlc.ContextWithTx(s.ctx, tx)
+
+		if _, err := s.client.Exec(ctx, "UPDATE orders SET status = ?", "paid"); err != nil {
+			return err
+		}
+
+		if _, err := other.Exec(ctx, "INSERT INTO audit_logs (action) VALUES (?)", "paid"); err != nil {
+			return err
+		}
+
+		var users []User
+		s.Assert().ErrorIs(other.Q().From("users").ForUpdate().Select(ctx, &users), sqlc.ErrLockingRequiresTx)
+
+		return other.WithTx(ctx, func(cttx sqlc.Tx) error {
+			_, err := cttx.Exec(cttx, "INSERT INTO audit_logs (action) VALUES (?)", "committed")
+
+			return err
+		})
+	})
+
+	s.Require().NoError(err)
+}
+
+func (s *ClientTestSuite) TestContextWithoutTx() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectRollback()
+
+	tx, err := s.client.BeginTx(s.ctx)
+	s.Require().NoError(err)
+
+	ambient, ok := sqlc.TxFromContext(sqlc.ContextWithTx(s.ctx, tx))
+	s.Assert().True(ok)
+	s.Assert().Same(tx, ambient)
+
+	ambient, ok = sqlc.TxFromContext(tx)
+	s.Assert().True(ok)
+	s.Assert().Same(tx, ambient)
+
+	_, ok = sqlc.TxFromContext(sqlc.ContextWithoutTx(sqlc.ContextWithTx(s.ctx, tx)))
+	s.Assert().False(ok)
+
+	_, ok = sqlc.TxFromContext(s.ctx)
+	s.Assert().False(ok)
+
+	var users []User
+	err = s.client.Q().From("users").ForUpdate().Select(sqlc.ContextWithoutTx(tx), &users)
+	s.Assert().ErrorIs(err, sqlc.ErrLockingRequiresTx)
+
+	s.Require().NoError(tx.Rollback())
+}
+
+func (s *ClientTestSuite) TestLockingSelectRequiresTx() {
+	var users []User
+	err := s.client.Q().From("users").ForUpdate().Select(s.ctx, &users)
+	s.Assert().ErrorIs(err, sqlc.ErrLockingRequiresTx)
+
+	var user User
+	err = s.client.Q().From("users").Where("id = ?", 1).ForShare().Get(s.ctx, &user)
+	s.Assert().ErrorIs(err, sqlc.ErrLockingRequiresTx)
+}
+
 // -----------------------------------------------------------------------------
 // Close Test (standalone - needs separate setup)
 // -----------------------------------------------------------------------------
 
+func (s *ClientTestSuite) TestInterceptors_ObserveCalls() {
+	var calls []sqlc.Call
+	var results []*sqlc.CallResult
+
+	client := s.client.WithInterceptors(func(ctx context.Context, call *sqlc.Call, next sqlc.Invoker) *sqlc.CallResult {
+		result := next(ctx, call)
+		calls = append(calls, sqlc.Call{Operation: call.Operation, Query: call.Query, Args: call.Args, Table: call.Table})
+		results = append(results, result)
+
+		return result
+	})
+
+	queryErr := errors.New("query failed")
+	s.mock.ExpectExec("UPDATE users").WithArgs("John", 1).WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectQuery("SELECT id FROM users").WillReturnError(queryErr)
+
+	_, err := client.Exec(s.ctx, "UPDATE users SET name = ? WHERE id = ?", "John", 1)
+	s.NoError(err)
+
+	var ids []int
+	err = client.Select(s.ctx, &ids, "SELECT id FROM users")
+	s.ErrorIs(err, queryErr)
+
+	s.Equal([]sqlc.Call{
+		{Operation: sqlc.OperationExec, Query: "UPDATE users SET name = ? WHERE id = ?", Args: []any{"John", 1}},
+		{Operation: sqlc.OperationSelect, Query: "SELECT id FROM users"},
+	}, calls)
+	s.Equal(int64(3), results[0].RowsAffected)
+	s.NoError(results[0].Err)
+	s.Equal(int64(-1), results[1].RowsAffected)
+	s.ErrorIs(results[1].Err, queryErr)
+}
+
+func (s *ClientTestSuite) TestInterceptors_Order() {
+	var order []string
+	record := func(name string) sqlc.Interceptor {
+		return func(ctx context.Context, call *sqlc.Call, next sqlc.Invoker) *sqlc.CallResult {
+			order = append(order, name+" before")
+			result := next(ctx, call)
+			order = append(order, name+" after")
+
+			return result
+		}
+	}
+
+	client := s.client.WithInterceptors(record("outer")).WithInterceptors(record("inner"))
+
+	s.mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := client.Exec(s.ctx, "DELETE FROM users")
+	s.NoError(err)
+	s.Equal([]string{"outer before", "inner before", "inner after", "outer after"}, order)
+}
+
+func (s *ClientTestSuite) TestInterceptors_VetoAndRewrite() {
+	vetoErr := errors.New("dropping tables is not allowed")
+
+	client := s.client.WithInterceptors(func(ctx context.Context, call *sqlc.Call, next sqlc.Invoker) *sqlc.CallResult {
+		if strings.HasPrefix(call.Query, "DROP") {
+			return &sqlc.CallResult{Err: vetoErr}
+		}
+
+		call.Query = "/* app=test */ " + call.Query
+
+		return next(ctx, call)
+	})
+
+	s.mock.ExpectExec(regexp.QuoteMeta("/* app=test */ DELETE FROM users")).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := client.Exec(s.ctx, "DROP TABLE users")
+	s.ErrorIs(err, vetoErr)
+
+	_, err = client.Exec(s.ctx, "DELETE FROM users")
+	s.NoError(err)
+}
+
+func (s *ClientTestSuite) TestInterceptors_VetoQueryRow() {
+	vetoErr := errors.New("reading secrets is not allowed")
+
+	client := s.client.WithInterceptors(func(ctx context.Context, call *sqlc.Call, next sqlc.Invoker) *sqlc.CallResult {
+		switch {
+		case strings.Contains(call.Query, "secrets"):
+			return &sqlc.CallResult{Err: vetoErr}
+		case strings.Contains(call.Query, "tokens"):
+			return &sqlc.CallResult{Value: "not a row"}
+		}
+
+		return next(ctx, call)
+	})
+
+	var value string
+	row := client.QueryRow(s.ctx, "SELECT value FROM secrets WHERE id = ?", 1)
+	s.Require().NotNil(row)
+	s.ErrorIs(row.Err(), vetoErr)
+	s.ErrorIs(row.Scan(&value), vetoErr)
+
+	row = client.QueryRow(s.ctx, "SELECT value FROM tokens WHERE id = ?", 1)
+	s.Require().NotNil(row)
+	s.EqualError(row.Scan(&value), "interceptor returned a value of type string instead of *sql.Row for QueryRow")
+}
+
+func (s *ClientTestSuite) TestInterceptors_InheritedByTx() {
+	var operations []sqlc.Operation
+
+	client := s.client.WithInterceptors(func(ctx context.Context, call *sqlc.Call, next sqlc.Invoker) *sqlc.CallResult {
+		operations = append(operations, call.Operation)
+
+		return next(ctx, call)
+	})
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	err := client.WithTx(s.ctx, func(tx sqlc.Tx) error {
+		if _, err := tx.NamedExec(tx, "INSERT INTO users (name) VALUES (:name)", User{Name: "John"}); err != nil {
+			return err
+		}
+
+		return tx.WithTx(func(tx sqlc.Tx) error {
+			return nil
+		})
+	})
+	s.NoError(err)
+	s.Equal([]sqlc.Operation{sqlc.OperationNamedExec, sqlc.OperationExec, sqlc.OperationExec}, operations)
+}
+
+func (s *ClientTestSuite) TestInterceptors_QueryMetrics() {
+	var batches []metric.Data
+
+	writer := metricMocks.NewWriter(s.T())
+	writer.EXPECT().Write(mock.Anything, mock.Anything).Run(func(_ context.Context, batch metric.Data) {
+		batches = append(batches, batch)
+	}).Times(2)
+
+	client := s.client.WithInterceptors(sqlc.NewQueryMetricsInterceptor(writer, "main"))
+
+	s.mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com").AddRow(2, "Jane", "jane@example.com"))
+	s.mock.ExpectExec("UPDATE users").WillReturnError(errors.New("connection refused"))
+
+	var users []User
+	err := client.Q().From("users").As("u").Select(s.ctx, &users)
+	s.NoError(err)
+
+	_, err = client.Exec(s.ctx, "UPDATE users SET name = ?", "John")
+	s.Error(err)
+
+	values := func(batch metric.Data) map[string]float64 {
+		result := map[string]float64{}
+		for _, datum := range batch {
+			key := datum.MetricName
+			if typ, ok := datum.Dimensions["Type"]; ok {
+				key += "/" + typ
+			}
+			result[key] = datum.Value
+		}
+
+		return result
+	}
+
+	s.Require().Len(batches, 2)
+	s.Equal(metric.Dimensions{"Client": "main", "Operation": "select", "Table": "users"}, batches[0][0].Dimensions)
+	s.Equal(float64(1), values(batches[0])["DbQueryCount"])
+	s.Equal(float64(0), values(batches[0])["DbQueryErrorCount"])
+	s.Equal(float64(2), values(batches[0])["DbQueryRows/returned"])
+	s.NotContains(values(batches[0]), "DbQueryRows/affected")
+
+	s.Equal(metric.Dimensions{"Client": "main", "Operation": "update"}, batches[1][0].Dimensions)
+	s.Equal(float64(1), values(batches[1])["DbQueryErrorCount"])
+	s.Equal(float64(0), values(batches[1])["DbQueryRetryCount"])
+}
+
+func (s *ClientTestSuite) TestInterceptors_QueryMetricsWithCte() {
+	var batches []metric.Data
+
+	writer := metricMocks.NewWriter(s.T())
+	writer.EXPECT().Write(mock.Anything, mock.Anything).Run(func(_ context.Context, batch metric.Data) {
+		batches = append(batches, batch)
+	}).Times(2)
+
+	client := s.client.WithInterceptors(sqlc.NewQueryMetricsInterceptor(writer, "main"))
+	inactive := client.Q().From("users").Columns("id").Where("last_login < ?", "2020-01-01")
+
+	s.mock.ExpectExec("WITH `inactive` AS (.+) UPDATE `users`").WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectQuery("WITH `inactive` AS (.+) SELECT (.+) FROM `inactive`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	_, err := client.Q().With("inactive", inactive).Update("users").Set("status", "inactive").Where("id IN (SELECT id FROM inactive)").Exec(s.ctx)
+	s.NoError(err)
+
+	var ids []int
+	err = client.Q().With("inactive", inactive).From("inactive").Columns("id").Select(s.ctx, &ids)
+	s.NoError(err)
+
+	// the statements start with WITH, so their kind is only known from the builder
+	s.Require().Len(batches, 2)
+	s.Equal(metric.Dimensions{"Client": "main", "Operation": "update", "Table": "users"}, batches[0][0].Dimensions)
+	s.Equal(metric.Dimensions{"Client": "main", "Operation": "select", "Table": "inactive"}, batches[1][0].Dimensions)
+}
+
+func TestSlowQueryInterceptor(t *testing.T) {
+	newClient := func(t *testing.T, explain bool) (sqlc.Client, sqlmock.Sqlmock, *[]log.Fields) {
+		mockDB, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		var logged []log.Fields
+
+		slowLogger := logmocks.NewLogger(t)
+		slowLogger.EXPECT().WithChannel("sqlc-slow-queries").Return(slowLogger).Once()
+		slowLogger.EXPECT().WithFields(mock.Anything).RunAndReturn(func(fields log.Fields) log.Logger {
+			logged = append(logged, fields)
+
+			return slowLogger
+		}).Maybe()
+		slowLogger.EXPECT().Warn(mock.Anything, "slow %s took %s: %s", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+		logger := logmocks.NewLoggerMock(logmocks.WithMockAll, logmocks.WithTestingT(t))
+		settings := &sqlc.Settings{Driver: sqlc.DriverMysql, SlowQueryThreshold: time.Nanosecond, SlowQueryExplain: explain}
+
+		client := sqlc.NewClientWithInterfaces(logger, sqlx.NewDb(mockDB, "sqlmock"), exec.NewDefaultExecutor(), sqlc.DefaultConfig()).
+			WithInterceptors(sqlc.NewSlowQueryInterceptor(slowLogger, settings))
+
+		return client, sqlMock, &logged
+	}
+
+	t.Run("logs slow statements with the caller", func(t *testing.T) {
+		client, sqlMock, logged := newClient(t, false)
+
+		sqlMock.ExpectExec("UPDATE users").WithArgs("John", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := client.Exec(context.Background(), "UPDATE users SET name = ? WHERE id = ?", "John", 1)
+		require.NoError(t, err)
+
+		require.Len(t, *logged, 1)
+		fields := (*logged)[0]
+		assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", fields["sql"])
+		assert.Equal(t, 2, fields["args_count"])
+		assert.Equal(t, sqlc.OperationExec, fields["operation"])
+		assert.Contains(t, fields["caller"], "client_test.go:")
+		assert.NotContains(t, fields, "plan")
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("attaches the plan of slow selects", func(t *testing.T) {
+		client, sqlMock, logged := newClient(t, true)
+
+		sqlMock.ExpectQuery("SELECT (.+) FROM `users` WHERE id = ?").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com"))
+		sqlMock.ExpectQuery(regexp.QuoteMeta("EXPLAIN SELECT `id`, `name`, `email` FROM `users` WHERE id = ?")).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "select_type", "table", "type"}).AddRow(1, "SIMPLE", []byte("users"), "const"))
+
+		var user User
+		err := client.Q().From("users").Where("id = ?", 1).Get(context.Background(), &user)
+		require.NoError(t, err)
+
+		require.Len(t, *logged, 1)
+		assert.Equal(t, []string{"1 | SIMPLE | users | const"}, (*logged)[0]["plan"])
+		assert.Contains(t, (*logged)[0]["caller"], "client_test.go:")
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+}
+
+type tracedSpan struct {
+	name        string
+	parent      string
+	annotations map[string]string
+	metadata    map[string]any
+	err         error
+}
+
+type parentSpanCtxKey struct{}
+
+func TestClientTracing(t *testing.T) {
+	var spans []*tracedSpan
+
+	tracer := tracingMocks.NewTracer(t)
+	tracer.EXPECT().StartSubSpan(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, name string) (context.Context, tracing.Span) {
+		parent, _ := ctx.Value(parentSpanCtxKey{}).(string)
+		traced := &tracedSpan{name: name, parent: parent, annotations: map[string]string{}, metadata: map[string]any{}}
+		spans = append(spans, traced)
+
+		span := tracingMocks.NewSpan(t)
+		span.EXPECT().AddAnnotation(mock.Anything, mock.Anything).Run(func(key string, value string) {
+			traced.annotations[key] = value
+		}).Maybe()
+		span.EXPECT().AddMetadata(mock.Anything, mock.Anything).Run(func(key string, value any) {
+			traced.metadata[key] = value
+		}).Maybe()
+		span.EXPECT().AddError(mock.Anything).Run(func(err error) {
+			traced.err = err
+		}).Maybe()
+		span.EXPECT().Finish().Once()
+
+		return context.WithValue(ctx, parentSpanCtxKey{}, name), span
+	})
+
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	logger := logmocks.NewLoggerMock(logmocks.WithMockAll, logmocks.WithTestingT(t))
+	settings := &sqlc.Settings{Driver: sqlc.DriverMysql, Uri: sqlc.SettingsUri{Database: "shop"}}
+	client := sqlc.NewClientWithTracer(logger, sqlx.NewDb(mockDB, "sqlmock"), exec.NewDefaultExecutor(), tracer, settings, sqlc.DefaultConfig())
+
+	queryErr := errors.New("table does not exist")
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectQuery("SELECT (.+) FROM `orders`").WillReturnError(queryErr)
+
+	err = client.WithTx(context.Background(), func(tx sqlc.Tx) error {
+		_, err := tx.Exec(tx, "INSERT INTO users (id, name) VALUES (1,   'John')")
+
+		return err
+	})
+	require.NoError(t, err)
+
+	var orders []User
+	err = client.Q().From("orders").Select(context.Background(), &orders)
+	assert.ErrorIs(t, err, queryErr)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+
+	require.Len(t, spans, 5)
+
+	assert.Equal(t, "sqlc.Transaction", spans[0].name)
+	assert.Equal(t, "", spans[0].parent)
+	assert.Equal(t, map[string]string{"db.system": "mysql", "db.name": "shop"}, spans[0].annotations)
+
+	assert.Equal(t, "sqlc.BeginTx", spans[1].name)
+	assert.Equal(t, "sqlc.Transaction", spans[1].parent)
+
+	assert.Equal(t, "sqlc.Exec", spans[2].name)
+	assert.Equal(t, "sqlc.Transaction", spans[2].parent)
+	assert.Equal(t, "insert", spans[2].annotations["db.operation"])
+	assert.Equal(t, "INSERT INTO users (id, name) VALUES (?, ?)", spans[2].metadata["db.statement"])
+	assert.NoError(t, spans[2].err)
+
+	assert.Equal(t, "sqlc.Commit", spans[3].name)
+	assert.Equal(t, "sqlc.Transaction", spans[3].parent)
+
+	assert.Equal(t, "sqlc.Select", spans[4].name)
+	assert.Equal(t, "", spans[4].parent)
+	assert.Equal(t, "orders", spans[4].annotations["db.sql.table"])
+	assert.ErrorIs(t, spans[4].err, queryErr)
+}
+
 func TestClientClose(t *testing.T) {
 	logger := logmocks.NewLoggerMock(logmocks.WithTestingT(t), logmocks.WithMockAll)
 
@@ -573,3 +1226,276 @@ func TestClientWithExecutorRetry(t *testing.T) {
 		assert.ErrorIs(t, err, retryErr)
 	})
 }
+
+func TestClientWithTxRerun(t *testing.T) {
+	logger := logmocks.NewLoggerMock(logmocks.WithTestingT(t), logmocks.WithMockAll)
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+	newClient := func(t *testing.T) (sqlc.Client, sqlmock.Sqlmock) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		txExecutor := exec.NewExecutor(logger, &exec.ExecutableResource{Type: "db-tx", Name: "test"}, &exec.BackoffSettings{
+			InitialInterval: time.Millisecond,
+			MaxAttempts:     3,
+			MaxElapsedTime:  time.Second,
+			MaxInterval:     time.Millisecond,
+		}, []exec.ErrorChecker{sqlc.CheckSerializationFailure})
+
+		client := sqlc.NewClientWithTxExecutor(logger, sqlx.NewDb(mockDB, "sqlmock"), exec.NewDefaultExecutor(), txExecutor, sqlc.DefaultConfig())
+
+		return client, mock
+	}
+
+	t.Run("deadlock re-runs the whole transaction", func(t *testing.T) {
+		client, mock := newClient(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE accounts").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE accounts").WillReturnError(deadlock)
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE accounts").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE accounts").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		runs := 0
+		err := client.WithTx(context.Background(), func(tx sqlc.Tx) error {
+			runs++
+
+			if _, err := tx.Exec(tx, "UPDATE accounts SET balance = balance - 10 WHERE id = 1"); err != nil {
+				return err
+			}
+
+			_, err := tx.Exec(tx, "UPDATE accounts SET balance = balance + 10 WHERE id = 2")
+
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, runs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("deadlock in a savepoint re-runs the whole transaction if its rollback fails", func(t *testing.T) {
+		client, mock := newClient(t)
+		noSavepoint := &mysql.MySQLError{Number: 1305, Message: "SAVEPOINT sqlc_savepoint_1 does not exist"}
+
+		// the server has rolled back the whole transaction after the deadlock, including the savepoint
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT `sqlc_savepoint_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE accounts").WillReturnError(deadlock)
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT `sqlc_savepoint_1`").WillReturnError(noSavepoint)
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT `sqlc_savepoint_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE accounts").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("RELEASE SAVEPOINT `sqlc_savepoint_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		runs := 0
+		err := client.WithTx(context.Background(), func(tx sqlc.Tx) error {
+			runs++
+
+			return tx.WithTx(func(cttx sqlc.Tx) error {
+				_, err := cttx.Exec(cttx, "UPDATE accounts SET balance = balance - 10 WHERE id = 1")
+
+				return err
+			})
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, runs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("other errors are not re-run", func(t *testing.T) {
+		client, mock := newClient(t)
+		fnErr := errors.New("insufficient balance")
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		runs := 0
+		err := client.WithTx(context.Background(), func(tx sqlc.Tx) error {
+			runs++
+
+			return fnErr
+		})
+
+		assert.ErrorIs(t, err, fnErr)
+		assert.Equal(t, 1, runs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCheckSerializationFailure(t *testing.T) {
+	assert.Equal(t, exec.ErrorTypeRetryable, sqlc.CheckSerializationFailure(nil, &mysql.MySQLError{Number: 1213}))
+	assert.Equal(t, exec.ErrorTypeRetryable, sqlc.CheckSerializationFailure(nil, fmt.Errorf("commit: %w", &pq.Error{Code: "40001"})))
+	assert.Equal(t, exec.ErrorTypeRetryable, sqlc.CheckSerializationFailure(nil, &pq.Error{Code: "40P01"}))
+	assert.Equal(t, exec.ErrorTypeRetryable, sqlc.CheckSerializationFailure(nil, errors.Join(&mysql.MySQLError{Number: 1305}, &mysql.MySQLError{Number: 1213})))
+	assert.Equal(t, exec.ErrorTypeUnknown, sqlc.CheckSerializationFailure(nil, &mysql.MySQLError{Number: 1062}))
+	assert.Equal(t, exec.ErrorTypeUnknown, sqlc.CheckSerializationFailure(nil, &pq.Error{Code: "23505"}))
+	assert.Equal(t, exec.ErrorTypeUnknown, sqlc.CheckSerializationFailure(nil, errors.New("boom")))
+}
+
+func TestClientWithReplicas(t *testing.T) {
+	logger := logmocks.NewLoggerMock(logmocks.WithTestingT(t), logmocks.WithMockAll)
+
+	newClient := func(t *testing.T) (sqlc.Client, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+		primaryDB, primary, err := sqlmock.New()
+		require.NoError(t, err)
+
+		replicaDB, replica, err := sqlmock.New()
+		require.NoError(t, err)
+
+		client := sqlc.NewClientWithReplicas(
+			logger,
+			sqlx.NewDb(primaryDB, "sqlmock"),
+			[]*sqlx.DB{sqlx.NewDb(replicaDB, "sqlmock")},
+			exec.NewDefaultExecutor(),
+			time.Minute,
+			sqlc.DefaultConfig(),
+		)
+
+		return client, primary, replica
+	}
+
+	t.Run("reads go to the replica and writes to the primary", func(t *testing.T) {
+		client, primary, replica := newClient(t)
+
+		replica.ExpectQuery("SELECT (.+) FROM `users`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com"))
+		primary.ExpectExec("UPDATE `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		var users []User
+		err := client.Q().From("users").Select(context.Background(), &users)
+		require.NoError(t, err)
+		assert.Len(t, users, 1)
+
+		_, err = client.Q().Update("users").Set("name", "Jane").Where("id = ?", 1).Exec(context.Background())
+		require.NoError(t, err)
+
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+
+	t.Run("writes returning rows go to the primary", func(t *testing.T) {
+		client, primary, replica := newClient(t)
+
+		primary.ExpectQuery("INSERT INTO `users` (.+) RETURNING").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com"))
+		primary.ExpectQuery("UPDATE `users` (.+) RETURNING").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "Jane", "john@example.com"))
+		primary.ExpectQuery("DELETE FROM `users` (.+) RETURNING").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "Jane", "john@example.com"))
+
+		var inserted, updated, deleted []User
+		err := client.Q().Into("users").Records(User{ID: 1, Name: "John", Email: "john@example.com"}).ExecReturning(context.Background(), &inserted)
+		require.NoError(t, err)
+
+		err = client.Q().Update("users").Set("name", "Jane").Where("id = ?", 1).ExecReturning(context.Background(), &updated)
+		require.NoError(t, err)
+
+		err = client.Q().Delete("users").Where("id = ?", 1).ExecReturning(context.Background(), &deleted)
+		require.NoError(t, err)
+		assert.Equal(t, []User{{ID: 1, Name: "Jane", Email: "john@example.com"}}, deleted)
+
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+
+	t.Run("raw writes returning rows go to the primary", func(t *testing.T) {
+		client, primary, replica := newClient(t)
+
+		primary.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (name) VALUES (?) RETURNING id")).
+			WithArgs("John").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		primary.ExpectQuery(regexp.QuoteMeta("UPDATE users SET name = ? RETURNING id")).
+			WithArgs("Jane").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		primary.ExpectQuery(regexp.QuoteMeta("DELETE FROM users RETURNING id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		var inserted []int
+		err := client.Select(context.Background(), &inserted, "INSERT INTO users (name) VALUES (?) RETURNING id", "John")
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, inserted)
+
+		var updated int
+		err = client.Get(context.Background(), &updated, "UPDATE users SET name = ? RETURNING id", "Jane")
+		require.NoError(t, err)
+		assert.Equal(t, 1, updated)
+
+		var deleted int
+		err = client.QueryRow(context.Background(), "DELETE FROM users RETURNING id").Scan(&deleted)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+
+	t.Run("read your writes and transactions use the primary", func(t *testing.T) {
+		client, primary, replica := newClient(t)
+
+		primary.ExpectQuery("SELECT (.+) FROM `users`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "Jane", "john@example.com"))
+		primary.ExpectBegin()
+		primary.ExpectQuery("SELECT (.+) FROM `users`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "Jane", "john@example.com"))
+		primary.ExpectCommit()
+
+		var user User
+		err := client.Q().From("users").Where("id = ?", 1).Get(sqlc.ContextWithPrimary(context.Background()), &user)
+		require.NoError(t, err)
+		assert.Equal(t, "Jane", user.Name)
+
+		err = client.WithTx(context.Background(), func(tx sqlc.Tx) error {
+			return client.Q().From("users").Where("id = ?", 1).Get(tx, &user)
+		})
+		require.NoError(t, err)
+
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+
+	t.Run("failing replica is evicted", func(t *testing.T) {
+		client, primary, replica := newClient(t)
+
+		replica.ExpectQuery("SELECT (.+) FROM `users`").WillReturnError(mysql.ErrInvalidConn)
+		primary.ExpectQuery("SELECT (.+) FROM `users`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com"))
+		primary.ExpectQuery("SELECT (.+) FROM `users`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com"))
+
+		for range 2 {
+			var users []User
+			err := client.Q().From("users").Select(context.Background(), &users)
+			require.NoError(t, err)
+			assert.Len(t, users, 1)
+		}
+
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+
+	t.Run("query errors do not evict the replica", func(t *testing.T) {
+		client, primary, replica := newClient(t)
+		queryErr := &mysql.MySQLError{Number: 1054, Message: "Unknown column"}
+
+		replica.ExpectQuery("SELECT (.+) FROM `users`").WillReturnError(queryErr)
+		replica.ExpectQuery("SELECT (.+) FROM `users`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}))
+
+		var users []User
+		err := client.Q().From("users").Select(context.Background(), &users)
+		assert.ErrorIs(t, err, queryErr)
+
+		err = client.Q().From("users").Select(context.Background(), &users)
+		require.NoError(t, err)
+
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+}
@@ -58,7 +58,7 @@ func NewConnectionFromSettings(ctx context.Context, logger log.Logger, name stri
 		connection *sqlx.DB
 	)
 
-	if connection, err = NewConnectionWithInterfaces(logger, settings); err != nil {
+	if connection, err = newConnection(logger, name, settings, connectionRolePrimary); err != nil {
 		return nil, fmt.Errorf("can not create connection: %w", err)
 	}
 
@@ -70,12 +70,42 @@ func NewConnectionFromSettings(ctx context.Context, logger log.Logger, name stri
 		return nil, fmt.Errorf("can not run migrations: %w", err)
 	}
 
-	publishConnectionMetrics(connection)
+	publishConnectionMetrics(connection, name, connectionRolePrimary)
 
 	return connection, nil
 }
 
+// NewReplicaConnectionsFromSettings connects to all read replicas of the settings.
+// Migrations are only run on the primary, the replicas receive them through replication.
+func NewReplicaConnectionsFromSettings(logger log.Logger, name string, settings *Settings) ([]*sqlx.DB, error) {
+	connections := make([]*sqlx.DB, 0, len(settings.Replicas.Uris))
+
+	for i := range settings.Replicas.Uris {
+		replicaSettings := settings.ReplicaSettings(i)
+
+		connection, err := newConnection(logger, name, replicaSettings, connectionRoleReplica)
+		if err != nil {
+			return nil, fmt.Errorf("can not create connection to replica %s: %w", replicaSettings.Uri.Host, err)
+		}
+
+		publishConnectionMetrics(connection, name, connectionRoleReplica)
+		connections = append(connections, connection)
+	}
+
+	return connections, nil
+}
+
+// poolConfigurer is implemented by drivers which require a specific configuration of the connection pool,
+// overriding the one of the settings.
+type poolConfigurer interface {
+	configurePool(db *sqlx.DB, settings *Settings)
+}
+
 func NewConnectionWithInterfaces(logger log.Logger, settings *Settings) (*sqlx.DB, error) {
+	return newConnection(logger, "", settings, connectionRolePrimary)
+}
+
+func newConnection(logger log.Logger, name string, settings *Settings, role string) (*sqlx.DB, error) {
 	drv, err := GetDriver(logger, settings.Driver)
 	if err != nil {
 		return nil, fmt.Errorf("could not get dsn provider for driver %s", settings.Driver)
@@ -88,7 +118,7 @@ func NewConnectionWithInterfaces(logger log.Logger, settings *Settings) (*sqlx.D
 		return nil, fmt.Errorf("could not get driver from %s connection factory: %w", settings.Driver, err)
 	}
 
-	metricDriverId := newMetricDriver(genDriver)
+	metricDriverId := newMetricDriver(genDriver, name, role)
 
 	db, err := sqlx.Connect(metricDriverId, dsn)
 	if err != nil {
@@ -100,6 +130,10 @@ func NewConnectionWithInterfaces(logger log.Logger, settings *Settings) (*sqlx.D
 	db.SetMaxIdleConns(settings.MaxIdleConnections)
 	db.SetMaxOpenConns(settings.MaxOpenConnections)
 
+	if configurer, ok := drv.(poolConfigurer); ok {
+		configurer.configurePool(db, settings)
+	}
+
 	return db, nil
 }
 
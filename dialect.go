@@ -0,0 +1,74 @@
+package sqlc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotSupportedByDialect is returned when a query uses a construct which the SQL dialect
+// of the target database does not support and which can not be translated to an equivalent.
+var ErrNotSupportedByDialect = errors.New("not supported by the SQL dialect")
+
+// Dialect describes the SQL dialect of a database. The query builders consult the dialect
+// of their config while rendering: constructs the database does not support are either
+// translated to an equivalent or rejected with a descriptive error wrapping ErrNotSupportedByDialect.
+type Dialect interface {
+	// Name returns the name of the dialect, e.g. "mysql" or "postgres".
+	Name() string
+	// SupportsReturning reports whether INSERT, UPDATE and DELETE statements support a RETURNING clause.
+	SupportsReturning() bool
+	// SupportsOnConflict reports whether INSERT statements support an ON CONFLICT clause.
+	SupportsOnConflict() bool
+	// SupportsOnDuplicateKeyUpdate reports whether INSERT statements support an ON DUPLICATE KEY UPDATE clause.
+	SupportsOnDuplicateKeyUpdate() bool
+	// SupportsReplace reports whether REPLACE statements are supported.
+	SupportsReplace() bool
+	// SupportsInsertIgnore reports whether INSERT statements support the IGNORE modifier.
+	SupportsInsertIgnore() bool
+	// SupportsInsertPriority reports whether INSERT statements support the LOW_PRIORITY, HIGH_PRIORITY and DELAYED modifiers.
+	SupportsInsertPriority() bool
+	// SupportsUpdateOrderByLimit reports whether UPDATE and DELETE statements support ORDER BY and LIMIT clauses.
+	SupportsUpdateOrderByLimit() bool
+	// SupportsRowLocking reports whether SELECT statements support the FOR UPDATE and FOR SHARE
+	// locking clauses including the OF, SKIP LOCKED and NOWAIT modifiers.
+	SupportsRowLocking() bool
+	// Function renders a call of the SQL function name with the already rendered arguments.
+	// Functions without a direct counterpart in the dialect are translated to an equivalent,
+	// forms which can not be translated are rejected with an error wrapping ErrNotSupportedByDialect.
+	Function(name string, args []string) (string, error)
+}
+
+// dialectOf returns the dialect of the config, or nil if the config has none.
+// Without a dialect, queries are rendered as written and no dialect checks are performed.
+func dialectOf(config *QueryBuilderConfig) Dialect {
+	if config == nil {
+		return nil
+	}
+
+	return config.Dialect
+}
+
+// checkDialectSupport returns an error wrapping ErrNotSupportedByDialect if the config has a dialect
+// and supported is false for it. The hint describes how to express the construct instead.
+func checkDialectSupport(config *QueryBuilderConfig, construct string, supported func(dialect Dialect) bool, hint string) error {
+	dialect := dialectOf(config)
+	if dialect == nil || supported(dialect) {
+		return nil
+	}
+
+	if hint == "" {
+		return fmt.Errorf("%s is %w %s", construct, ErrNotSupportedByDialect, dialect.Name())
+	}
+
+	return fmt.Errorf("%s is %w %s, %s", construct, ErrNotSupportedByDialect, dialect.Name(), hint)
+}
+
+// renderFunction renders a call of the SQL function name using the dialect of the config.
+func renderFunction(config *QueryBuilderConfig, name string, args []string) (string, error) {
+	if dialect := dialectOf(config); dialect != nil {
+		return dialect.Function(name, args)
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", ")), nil
+}
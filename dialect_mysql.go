@@ -0,0 +1,56 @@
+package sqlc
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DialectMysql = "mysql"
+
+// NewMysqlDialect returns the Dialect of MySQL and MariaDB.
+// All constructs of the query builders are supported, except for RETURNING and ON CONFLICT.
+func NewMysqlDialect() Dialect {
+	return &mysqlDialect{}
+}
+
+type mysqlDialect struct{}
+
+func (d *mysqlDialect) Name() string {
+	return DialectMysql
+}
+
+func (d *mysqlDialect) SupportsReturning() bool {
+	return false
+}
+
+func (d *mysqlDialect) SupportsOnConflict() bool {
+	return false
+}
+
+func (d *mysqlDialect) SupportsOnDuplicateKeyUpdate() bool {
+	return true
+}
+
+func (d *mysqlDialect) SupportsReplace() bool {
+	return true
+}
+
+func (d *mysqlDialect) SupportsInsertIgnore() bool {
+	return true
+}
+
+func (d *mysqlDialect) SupportsInsertPriority() bool {
+	return true
+}
+
+func (d *mysqlDialect) SupportsUpdateOrderByLimit() bool {
+	return true
+}
+
+func (d *mysqlDialect) SupportsRowLocking() bool {
+	return true
+}
+
+func (d *mysqlDialect) Function(name string, args []string) (string, error) {
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", ")), nil
+}
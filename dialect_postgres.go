@@ -0,0 +1,79 @@
+package sqlc
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DialectPostgres = "postgres"
+
+// NewPostgresDialect returns the Dialect of PostgreSQL.
+// MySQL specific constructs are translated where PostgreSQL has an equivalent:
+// INSERT IGNORE becomes ON CONFLICT DO NOTHING, LOCATE becomes STRPOS, GROUP_CONCAT becomes STRING_AGG
+// and IFNULL becomes COALESCE. REPLACE, ON DUPLICATE KEY UPDATE, the insert priority modifiers,
+// ORDER BY / LIMIT on UPDATE and DELETE, LOCATE with a start position and GROUP_CONCAT with several
+// values or a separator are rejected.
+func NewPostgresDialect() Dialect {
+	return &postgresDialect{}
+}
+
+type postgresDialect struct{}
+
+func (d *postgresDialect) Name() string {
+	return DialectPostgres
+}
+
+func (d *postgresDialect) SupportsReturning() bool {
+	return true
+}
+
+func (d *postgresDialect) SupportsOnConflict() bool {
+	return true
+}
+
+func (d *postgresDialect) SupportsOnDuplicateKeyUpdate() bool {
+	return false
+}
+
+func (d *postgresDialect) SupportsReplace() bool {
+	return false
+}
+
+func (d *postgresDialect) SupportsInsertIgnore() bool {
+	return false
+}
+
+func (d *postgresDialect) SupportsInsertPriority() bool {
+	return false
+}
+
+func (d *postgresDialect) SupportsUpdateOrderByLimit() bool {
+	return false
+}
+
+func (d *postgresDialect) SupportsRowLocking() bool {
+	return true
+}
+
+func (d *postgresDialect) Function(name string, args []string) (string, error) {
+	switch name {
+	case "LOCATE":
+		if len(args) != 2 {
+			return "", fmt.Errorf("LOCATE with a start position is %w %s, use STRPOS on a SUBSTRING instead", ErrNotSupportedByDialect, d.Name())
+		}
+
+		// LOCATE(substr, str) has the arguments in reversed order compared to STRPOS(str, substr)
+		return fmt.Sprintf("STRPOS(%s, %s)", args[1], args[0]), nil
+	case "GROUP_CONCAT":
+		if len(args) != 1 {
+			return "", fmt.Errorf("GROUP_CONCAT of several values or with a separator is %w %s, use STRING_AGG instead", ErrNotSupportedByDialect, d.Name())
+		}
+
+		// GROUP_CONCAT separates the values with a comma by default
+		return fmt.Sprintf("STRING_AGG(CAST(%s AS TEXT), ',')", args[0]), nil
+	case "IFNULL":
+		name = "COALESCE"
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", ")), nil
+}
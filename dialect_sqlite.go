@@ -0,0 +1,69 @@
+package sqlc
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DialectSqlite = "sqlite"
+
+// NewSqliteDialect returns the Dialect of SQLite.
+// MySQL specific constructs are translated where SQLite has an equivalent:
+// INSERT IGNORE becomes ON CONFLICT DO NOTHING and LOCATE becomes INSTR.
+// ON DUPLICATE KEY UPDATE, the insert priority modifiers, ORDER BY / LIMIT on UPDATE and DELETE and
+// LOCATE with a start position are rejected, as are row locking clauses, since SQLite locks the whole
+// database for write transactions.
+func NewSqliteDialect() Dialect {
+	return &sqliteDialect{}
+}
+
+type sqliteDialect struct{}
+
+func (d *sqliteDialect) Name() string {
+	return DialectSqlite
+}
+
+func (d *sqliteDialect) SupportsReturning() bool {
+	return true
+}
+
+func (d *sqliteDialect) SupportsOnConflict() bool {
+	return true
+}
+
+func (d *sqliteDialect) SupportsOnDuplicateKeyUpdate() bool {
+	return false
+}
+
+func (d *sqliteDialect) SupportsReplace() bool {
+	return true
+}
+
+func (d *sqliteDialect) SupportsInsertIgnore() bool {
+	return false
+}
+
+func (d *sqliteDialect) SupportsInsertPriority() bool {
+	return false
+}
+
+func (d *sqliteDialect) SupportsUpdateOrderByLimit() bool {
+	return false
+}
+
+func (d *sqliteDialect) SupportsRowLocking() bool {
+	return false
+}
+
+func (d *sqliteDialect) Function(name string, args []string) (string, error) {
+	if name == "LOCATE" {
+		if len(args) != 2 {
+			return "", fmt.Errorf("LOCATE with a start position is %w %s, use INSTR on a SUBSTR instead", ErrNotSupportedByDialect, d.Name())
+		}
+
+		// LOCATE(substr, str) has the arguments in reversed order compared to INSTR(str, substr)
+		return fmt.Sprintf("INSTR(%s, %s)", args[1], args[0]), nil
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", ")), nil
+}
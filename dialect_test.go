@@ -0,0 +1,191 @@
+package sqlc_test
+
+import (
+	"testing"
+
+	"github.com/gosoline-project/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mysqlConfig() *sqlc.QueryBuilderConfig {
+	config := sqlc.DefaultConfig()
+	config.Dialect = sqlc.NewMysqlDialect()
+
+	return config
+}
+
+func TestDialectMysqlRendersAsWritten(t *testing.T) {
+	sql, _, err := sqlc.Into("users").
+		WithConfig(mysqlConfig()).
+		LowPriority().
+		Ignore().
+		Columns("id", "name").
+		Values(1, "John").
+		OnDuplicateKeyUpdate(sqlc.AssignExcluded("name")).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT LOW_PRIORITY IGNORE INTO `users` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", sql)
+
+	sql, _, err = sqlc.Into("users").WithConfig(mysqlConfig()).Replace().Columns("id").Values(1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "REPLACE INTO `users` (`id`) VALUES (?)", sql)
+
+	sql, _, err = sqlc.Update("users").WithConfig(mysqlConfig()).Set("status", "inactive").OrderBy("id").Limit(10).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE `users` SET `status` = ? ORDER BY `id` LIMIT ?", sql)
+
+	sql, _, err = sqlc.From("users").
+		WithConfig(mysqlConfig()).
+		Columns(sqlc.Col("email").Locate("@"), sqlc.Col("tag").GroupConcat()).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT LOCATE('@', `email`), GROUP_CONCAT(`tag`) FROM `users`", sql)
+}
+
+func TestDialectPostgresInsertIgnore(t *testing.T) {
+	sql, params, err := sqlc.Into("users").
+		WithConfig(postgresConfig()).
+		Ignore().
+		Columns("id", "name").
+		Values(1, "John").
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT DO NOTHING`, sql)
+	assert.Equal(t, []any{1, "John"}, params)
+
+	sql, _, err = sqlc.Into("users").
+		WithConfig(postgresConfig()).
+		Ignore().
+		Records(TestUser{ID: 1, Name: "John"}).
+		ToNamedSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name", "email") VALUES (:id, :name, :email) ON CONFLICT DO NOTHING`, sql)
+
+	_, _, err = sqlc.Into("users").
+		WithConfig(postgresConfig()).
+		Ignore().
+		Columns("id", "name").
+		Values(1, "John").
+		OnConflict("id").
+		DoUpdate(sqlc.AssignExcluded("name")).
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "IGNORE can not be combined with a conflict clause for dialect postgres")
+}
+
+func TestDialectPostgresUnsupportedInsert(t *testing.T) {
+	base := sqlc.Into("users").WithConfig(postgresConfig()).Columns("id", "name").Values(1, "John")
+
+	_, _, err := base.Replace().ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.EqualError(t, err, "REPLACE is not supported by the SQL dialect postgres, use OnConflict().DoUpdate() or Upsert() instead")
+
+	_, _, err = base.LowPriority().ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.EqualError(t, err, "LOW_PRIORITY is not supported by the SQL dialect postgres")
+
+	_, _, err = base.Delayed().ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+
+	_, _, err = base.OnDuplicateKeyUpdate(sqlc.Assign("name", "Jane")).ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.EqualError(t, err, "ON DUPLICATE KEY UPDATE is not supported by the SQL dialect postgres, use OnConflict() or Upsert() instead")
+}
+
+func TestDialectPostgresUpdateDeleteOrderByLimit(t *testing.T) {
+	_, _, err := sqlc.Update("users").WithConfig(postgresConfig()).Set("status", "inactive").OrderBy("id").ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.Contains(t, err.Error(), "ORDER BY on UPDATE is not supported by the SQL dialect postgres")
+
+	_, _, err = sqlc.Update("users").WithConfig(postgresConfig()).Set("status", "inactive").Limit(10).ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.Contains(t, err.Error(), "LIMIT on UPDATE is not supported by the SQL dialect postgres")
+
+	_, _, err = sqlc.Delete("sessions").WithConfig(postgresConfig()).OrderBy("created_at").ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+
+	_, _, err = sqlc.Delete("sessions").WithConfig(postgresConfig()).Limit(100).ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.Contains(t, err.Error(), "LIMIT on DELETE is not supported by the SQL dialect postgres")
+
+	sql, params, err := sqlc.Delete("sessions").WithConfig(postgresConfig()).Where("expired = ?", true).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "sessions" WHERE expired = $1`, sql)
+	assert.Equal(t, []any{true}, params)
+}
+
+func TestDialectPostgresFunctions(t *testing.T) {
+	sql, params, err := sqlc.From("users").
+		WithConfig(postgresConfig()).
+		Columns(
+			sqlc.Col("email").Locate("@").As("at_position"),
+			sqlc.Col("tag").GroupConcat().As("tags"),
+			sqlc.IfNull(sqlc.Col("nickname"), "Anonymous"),
+		).
+		Where(sqlc.Col("email").Locate("@").Gt(1)).
+		GroupBy("email", "nickname").
+		ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `SELECT STRPOS("email", '@') AS at_position, STRING_AGG(CAST("tag" AS TEXT), ',') AS tags, COALESCE("nickname", $1) `+
+		`FROM "users" WHERE STRPOS("email", '@') > $2 GROUP BY "email", "nickname"`, sql)
+	assert.Equal(t, []any{"Anonymous", 1}, params)
+}
+
+func TestDialectPostgresNestedFunctions(t *testing.T) {
+	sql, _, err := sqlc.From("users").
+		WithConfig(postgresConfig()).
+		Columns(
+			sqlc.Col("name").Lower().Locate("a"),
+			sqlc.Col("name").Lower().GroupConcat(),
+		).
+		Where(sqlc.Col("email").Lower().Locate("@").Gt(1)).
+		ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `SELECT STRPOS(LOWER("name"), 'a'), STRING_AGG(CAST(LOWER("name") AS TEXT), ',') `+
+		`FROM "users" WHERE STRPOS(LOWER("email"), '@') > $1`, sql)
+}
+
+func TestDialectPostgresRejectsFunctions(t *testing.T) {
+	dialect := sqlc.NewPostgresDialect()
+
+	_, err := dialect.Function("LOCATE", []string{"'a'", `"name"`, "3"})
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.EqualError(t, err, "LOCATE with a start position is not supported by the SQL dialect postgres, use STRPOS on a SUBSTRING instead")
+
+	_, err = dialect.Function("GROUP_CONCAT", []string{`"tag"`, "';'"})
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.EqualError(t, err, "GROUP_CONCAT of several values or with a separator is not supported by the SQL dialect postgres, use STRING_AGG instead")
+
+	sql, err := dialect.Function("IFNULL", []string{`"nickname"`, "$1"})
+	require.NoError(t, err)
+	assert.Equal(t, `COALESCE("nickname", $1)`, sql)
+}
+
+func TestDialectReturningErrors(t *testing.T) {
+	_, _, err := sqlc.Delete("sessions").WithConfig(mysqlConfig()).Returning("id").ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrReturningNotSupported)
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+}
+
+func TestDialectSqlite(t *testing.T) {
+	config := &sqlc.QueryBuilderConfig{StructTag: "db", Placeholder: "?", IdentifierQuote: `"`, Dialect: sqlc.NewSqliteDialect()}
+
+	sql, params, err := sqlc.Into("users").WithConfig(config).Ignore().Columns("id", "name").Values(1, "John").Returning("id").ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name") VALUES (?, ?) ON CONFLICT DO NOTHING RETURNING "id"`, sql)
+	assert.Equal(t, []any{1, "John"}, params)
+
+	sql, _, err = sqlc.Into("users").WithConfig(config).Replace().Columns("id").Values(1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `REPLACE INTO "users" ("id") VALUES (?)`, sql)
+
+	sql, _, err = sqlc.From("users").WithConfig(config).Columns(sqlc.Col("email").Locate("@")).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT INSTR("email", '@') FROM "users"`, sql)
+
+	_, _, err = sqlc.Delete("sessions").WithConfig(config).Limit(100).ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+}
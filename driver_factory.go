@@ -12,6 +12,8 @@ type Driver interface {
 	GetDSN(settings *Settings) string
 	GetPlaceholder() string
 	GetQuote() string
+	// GetDialect returns the SQL dialect the query builders render queries for.
+	GetDialect() Dialect
 }
 
 var driverFactories = map[string]DriverFactory{}
@@ -65,6 +65,10 @@ func (m *mysqlDriver) GetQuote() string {
 	return "`"
 }
 
+func (m *mysqlDriver) GetDialect() Dialect {
+	return NewMysqlDialect()
+}
+
 type mysqlLogger struct {
 	logger log.Logger
 }
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	sqlc "github.com/gosoline-project/sqlc"
+
 	"github.com/justtrackio/gosoline/pkg/cfg"
 	"github.com/justtrackio/gosoline/pkg/db"
 	"github.com/justtrackio/gosoline/pkg/log"
@@ -53,3 +55,10 @@ func (s *MysqlDriverTestSuite) TestDsn() {
 	dsn = driver.GetDSN(s.settings)
 	s.Equal("tcp(localhost:3306)/?collation=utf8mb4_general_ci&multiStatements=true&parseTime=true&charset=utf8mb4&param1=value1&readTimeout=50ms&writeTimeout=50ms", dsn)
 }
+
+func (s *MysqlDriverTestSuite) TestGetDialect() {
+	driver, err := sqlc.NewMysqlDriver(s.logger)
+	s.NoError(err)
+
+	s.Equal(sqlc.DialectMysql, driver.GetDialect().Name())
+}
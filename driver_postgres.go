@@ -44,3 +44,7 @@ func (m *postgresDriver) GetPlaceholder() string {
 func (m *postgresDriver) GetQuote() string {
 	return `"`
 }
+
+func (m *postgresDriver) GetDialect() Dialect {
+	return NewPostgresDialect()
+}
@@ -59,3 +59,10 @@ func (s *PostgresDriverTestSuite) TestDsnWithParameters() {
 	s.Contains(dsn, "sslmode=disable")
 	s.Contains(dsn, "connect_timeout=10")
 }
+
+func (s *PostgresDriverTestSuite) TestGetDialect() {
+	driver, err := sqlc.NewPostgresDriver(s.logger)
+	s.NoError(err)
+
+	s.Equal(sqlc.DialectPostgres, driver.GetDialect().Name())
+}
@@ -0,0 +1,97 @@
+package sqlc
+
+import (
+	"fmt"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/log"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	DriverSqlite = "sqlite"
+
+	// sqliteMemoryDatabase is the database name which opens an in-memory database.
+	sqliteMemoryDatabase = ":memory:"
+)
+
+// sqliteMemoryDatabaseId numbers the in-memory databases, so every connection pool gets a database of its own.
+var sqliteMemoryDatabaseId atomic.Int64
+
+func init() {
+	AddDriverFactory(DriverSqlite, NewSqliteDriver)
+}
+
+// NewSqliteDriver returns the driver for SQLite, which uses a pure Go SQLite implementation
+// and therefore does not require cgo. It is intended for fast, hermetic tests of repository code.
+func NewSqliteDriver(logger log.Logger) (Driver, error) {
+	return &sqliteDriver{}, nil
+}
+
+type sqliteDriver struct{}
+
+// GetDSN returns a file DSN for the database file set as uri.database.
+// An empty database or ":memory:" opens a new in-memory database with a unique name on every call,
+// so the connection pools of different clients do not share their data. The connection pool of an
+// in-memory database is limited to a single connection, see configurePool.
+// The parameters are passed on as query parameters, e.g. "_pragma: foreign_keys(1)".
+// A connection timeout is applied as busy timeout.
+func (m *sqliteDriver) GetDSN(settings *Settings) string {
+	database := settings.Uri.Database
+	parameters := url.Values{}
+
+	for k, v := range settings.Parameters {
+		parameters.Set(k, v)
+	}
+
+	if isSqliteMemoryDatabase(database) {
+		database = fmt.Sprintf("sqlc-memory-%d", sqliteMemoryDatabaseId.Add(1))
+		parameters.Set("mode", "memory")
+		parameters.Set("cache", "shared")
+	}
+
+	if settings.Timeouts.Timeout > 0 {
+		parameters.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", settings.Timeouts.Timeout.Milliseconds()))
+	}
+
+	dsn := "file:" + database
+	if len(parameters) > 0 {
+		dsn += "?" + parameters.Encode()
+	}
+
+	return dsn
+}
+
+// configurePool keeps the single connection of an in-memory database open for the lifetime of the pool.
+// SQLite drops an in-memory database as soon as its last connection is closed, which the pool would
+// otherwise do after the idle time or whenever it holds more open connections than idle ones.
+// As a consequence, the statements on an in-memory database are executed one after another, and no
+// other statement can be executed while the rows of a query are iterated outside of a transaction.
+func (m *sqliteDriver) configurePool(db *sqlx.DB, settings *Settings) {
+	if !isSqliteMemoryDatabase(settings.Uri.Database) {
+		return
+	}
+
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxIdleTime(0)
+	db.SetConnMaxLifetime(0)
+}
+
+func (m *sqliteDriver) GetPlaceholder() string {
+	return "?"
+}
+
+func (m *sqliteDriver) GetQuote() string {
+	return `"`
+}
+
+func (m *sqliteDriver) GetDialect() Dialect {
+	return NewSqliteDialect()
+}
+
+func isSqliteMemoryDatabase(database string) bool {
+	return database == "" || database == sqliteMemoryDatabase
+}
@@ -0,0 +1,225 @@
+package sqlc_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	sqlc "github.com/gosoline-project/sqlc"
+	"github.com/justtrackio/gosoline/pkg/cfg"
+	"github.com/justtrackio/gosoline/pkg/exec"
+	"github.com/justtrackio/gosoline/pkg/log"
+	logMocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSqliteDriver(t *testing.T) {
+	suite.Run(t, new(SqliteDriverTestSuite))
+}
+
+type SqliteDriverTestSuite struct {
+	suite.Suite
+
+	config   cfg.GosoConf
+	logger   log.Logger
+	settings *sqlc.Settings
+}
+
+func (s *SqliteDriverTestSuite) SetupTest() {
+	s.config = cfg.New()
+	err := s.config.Option(cfg.WithConfigMap(map[string]any{
+		"app_name": "test",
+	}))
+	s.NoError(err)
+
+	s.settings = &sqlc.Settings{}
+	err = s.config.UnmarshalDefaults(s.settings)
+	s.NoError(err)
+
+	s.settings.Driver = sqlc.DriverSqlite
+	s.logger = logMocks.NewLoggerMock(logMocks.WithMockAll, logMocks.WithTestingT(s.T()))
+}
+
+func (s *SqliteDriverTestSuite) TestDsn() {
+	driver, err := sqlc.NewSqliteDriver(s.logger)
+	s.NoError(err)
+
+	dsn := driver.GetDSN(s.settings)
+	s.Regexp(`^file:sqlc-memory-\d+\?cache=shared&mode=memory$`, dsn)
+	s.NotEqual(dsn, driver.GetDSN(s.settings), "every in-memory database should have a unique name")
+
+	s.settings.Uri.Database = "/tmp/test.db"
+	dsn = driver.GetDSN(s.settings)
+	s.Equal("file:/tmp/test.db", dsn)
+
+	s.settings.Parameters = map[string]string{"_pragma": "foreign_keys(1)"}
+	s.settings.Timeouts.Timeout = time.Second * 5
+	dsn = driver.GetDSN(s.settings)
+	s.Equal("file:/tmp/test.db?_pragma=foreign_keys%281%29&_pragma=busy_timeout%285000%29", dsn)
+}
+
+func (s *SqliteDriverTestSuite) TestGetDialect() {
+	driver, err := sqlc.NewSqliteDriver(s.logger)
+	s.NoError(err)
+
+	s.Equal(sqlc.DialectSqlite, driver.GetDialect().Name())
+	s.Equal("?", driver.GetPlaceholder())
+	s.Equal(`"`, driver.GetQuote())
+}
+
+func (s *SqliteDriverTestSuite) TestClient() {
+	ctx := context.Background()
+	client, config := s.newClient()
+	defer func() {
+		s.NoError(client.Close())
+	}()
+
+	_, err := client.Exec(ctx, `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL)`)
+	s.Require().NoError(err)
+
+	_, err = sqlc.IntoG[TestUser]("users").
+		WithClient(client).
+		WithConfig(config).
+		Records(TestUser{ID: 1, Name: "John", Email: "john@example.com"}, TestUser{ID: 2, Name: "Jane", Email: "jane@example.com"}).
+		Exec(ctx)
+	s.Require().NoError(err)
+
+	_, err = client.Q().Into("users").
+		Columns("id", "name", "email").
+		Values(1, "Johnny", "johnny@example.com").
+		Upsert([]string{"id"}, []string{"name"}).
+		Exec(ctx)
+	s.Require().NoError(err)
+
+	var users []TestUser
+	err = client.Q().From("users").OrderBy("id").Select(ctx, &users)
+	s.Require().NoError(err)
+	s.Equal([]TestUser{
+		{ID: 1, Name: "Johnny", Email: "john@example.com"},
+		{ID: 2, Name: "Jane", Email: "jane@example.com"},
+	}, users)
+
+	var deleted []TestUser
+	err = client.Q().Delete("users").Where("id = ?", 2).Returning("id", "name", "email").ExecReturning(ctx, &deleted)
+	s.Require().NoError(err)
+	s.Equal([]TestUser{{ID: 2, Name: "Jane", Email: "jane@example.com"}}, deleted)
+}
+
+func (s *SqliteDriverTestSuite) TestInMemory() {
+	ctx := context.Background()
+
+	// the pool would close idle connections right away, dropping the database with the last one
+	s.settings.Uri.Database = ""
+	s.settings.MaxIdleConnections = 0
+	s.settings.ConnectionMaxIdleTime = time.Millisecond
+
+	first, err := sqlc.NewConnectionWithInterfaces(s.logger, s.settings)
+	s.Require().NoError(err)
+	defer func() {
+		s.NoError(first.Close())
+	}()
+
+	second, err := sqlc.NewConnectionWithInterfaces(s.logger, s.settings)
+	s.Require().NoError(err)
+	defer func() {
+		s.NoError(second.Close())
+	}()
+
+	_, err = first.ExecContext(ctx, `CREATE TABLE teams (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`)
+	s.Require().NoError(err)
+	_, err = first.ExecContext(ctx, `INSERT INTO teams (name) VALUES ('a')`)
+	s.Require().NoError(err)
+
+	time.Sleep(50 * time.Millisecond)
+
+	var count int
+	s.Require().NoError(first.GetContext(ctx, &count, `SELECT COUNT(*) FROM teams`))
+	s.Equal(1, count)
+
+	err = second.GetContext(ctx, &count, `SELECT COUNT(*) FROM teams`)
+	s.ErrorContains(err, "no such table: teams", "the connection pools should not share their in-memory databases")
+}
+
+func (s *SqliteDriverTestSuite) TestBulkLoad() {
+	ctx := context.Background()
+	client, _ := s.newClient()
+	defer func() {
+		s.NoError(client.Close())
+	}()
+
+	_, err := client.Exec(ctx, `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL)`)
+	s.Require().NoError(err)
+
+	// more rows than fit into a single statement, so they are inserted in chunks
+	users := make([]TestUser, 0, 40000)
+	for id := 1; id <= 40000; id++ {
+		users = append(users, TestUser{ID: id, Name: fmt.Sprintf("user %d", id), Email: fmt.Sprintf("user%d@example.com", id)})
+	}
+
+	loaded, err := sqlc.BulkLoad(ctx, client, "users", slices.Values(users[:25000]))
+	s.Require().NoError(err)
+	s.Equal(int64(25000), loaded)
+
+	count, err := client.Q().From("users").Count(ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(25000), count)
+
+	// the duplicate in the last chunk rolls back the chunks loaded before it
+	duplicate := append(users[25000:], users[0])
+	_, err = sqlc.BulkLoad(ctx, client, "users", slices.Values(duplicate))
+	s.ErrorContains(err, "UNIQUE constraint failed")
+
+	count, err = client.Q().From("users").Count(ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(25000), count)
+}
+
+func (s *SqliteDriverTestSuite) TestPurge() {
+	ctx := context.Background()
+	client, _ := s.newClient()
+	defer func() {
+		s.NoError(client.Close())
+	}()
+
+	_, err := client.Exec(ctx, `CREATE TABLE teams (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT);
+		CREATE TABLE members (id INTEGER PRIMARY KEY, team_id INTEGER REFERENCES teams (id));
+		INSERT INTO teams (name) VALUES ('a'), ('b');
+		INSERT INTO members (id, team_id) VALUES (1, 1), (2, 2);`)
+	s.Require().NoError(err)
+
+	purger, err := sqlc.NewLifeCyclePurgerWithSettings(s.logger, s.settings)
+	s.Require().NoError(err)
+	s.Require().NoError(purger.Purge(ctx))
+
+	var count int
+	s.Require().NoError(client.Get(ctx, &count, `SELECT (SELECT COUNT(*) FROM teams) + (SELECT COUNT(*) FROM members)`))
+	s.Equal(0, count)
+
+	_, err = client.Exec(ctx, `INSERT INTO teams (name) VALUES ('c')`)
+	s.Require().NoError(err)
+	s.Require().NoError(client.Get(ctx, &count, `SELECT id FROM teams`))
+	s.Equal(1, count, "the autoincrement counter should be reset")
+}
+
+func (s *SqliteDriverTestSuite) newClient() (sqlc.Client, *sqlc.QueryBuilderConfig) {
+	s.settings.Uri.Database = filepath.Join(s.T().TempDir(), "test.db")
+	s.settings.Parameters = map[string]string{"_pragma": "foreign_keys(1)"}
+
+	connection, err := sqlc.NewConnectionWithInterfaces(s.logger, s.settings)
+	s.Require().NoError(err)
+
+	driver, err := sqlc.NewSqliteDriver(s.logger)
+	s.Require().NoError(err)
+
+	config := &sqlc.QueryBuilderConfig{
+		StructTag:       "db",
+		Placeholder:     driver.GetPlaceholder(),
+		IdentifierQuote: driver.GetQuote(),
+		Dialect:         driver.GetDialect(),
+	}
+
+	return sqlc.NewClientWithInterfaces(s.logger, connection, exec.NewDefaultExecutor(), config), config
+}
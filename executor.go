@@ -9,6 +9,7 @@ import (
 	"github.com/justtrackio/gosoline/pkg/cfg"
 	"github.com/justtrackio/gosoline/pkg/exec"
 	"github.com/justtrackio/gosoline/pkg/log"
+	"github.com/lib/pq"
 )
 
 func NewExecutor(config cfg.Config, logger log.Logger, name string, backoffType string, notifier ...exec.Notify) (exec.Executor, error) {
@@ -42,6 +43,31 @@ func NewExecutorWithChecker(config cfg.Config, logger log.Logger, name string, b
 	), nil
 }
 
+// NewTxExecutor creates the executor which re-runs whole transactions in Client.WithTx.
+// It uses the same backoff settings as the statement executor, but only retries errors
+// reported by CheckSerializationFailure, as the transaction is aborted by the database in these cases.
+func NewTxExecutor(config cfg.Config, logger log.Logger, name string, backoffType string, notifier ...exec.Notify) (exec.Executor, error) {
+	res := &exec.ExecutableResource{
+		Type: "db-tx",
+		Name: name,
+	}
+
+	executorSettings, err := exec.ReadBackoffSettings(config, backoffType)
+	if err != nil {
+		return nil, fmt.Errorf("can not read backoff settings: %w", err)
+	}
+
+	return exec.NewExecutor(
+		logger,
+		res,
+		&executorSettings,
+		[]exec.ErrorChecker{
+			CheckSerializationFailure,
+		},
+		notifier...,
+	), nil
+}
+
 func ExecutorBackoffType(name string) string {
 	return fmt.Sprintf("db.%s.retry", name)
 }
@@ -55,6 +81,54 @@ func CheckDeadlock(result any, err error) exec.ErrorType {
 	return exec.ErrorTypeUnknown
 }
 
+// CheckSerializationFailure marks deadlocks (MySQL 1213, Postgres 40P01) and serialization failures
+// (Postgres 40001) as retryable. The database aborts the whole transaction for these errors,
+// so they can only be resolved by running the transaction again. All errors joined into err are checked,
+// as a failed rollback is reported together with the error which caused it.
+func CheckSerializationFailure(result any, err error) exec.ErrorType {
+	if anyError(err, isSerializationFailure) {
+		return exec.ErrorTypeRetryable
+	}
+
+	return exec.ErrorTypeUnknown
+}
+
+func isSerializationFailure(err error) bool {
+	switch e := err.(type) {
+	case *mysql.MySQLError:
+		return e.Number == 1213
+	case *pq.Error:
+		return e.Code == "40001" || e.Code == "40P01"
+	}
+
+	return false
+}
+
+// anyError reports whether any error in the tree of err matches, unlike errors.As,
+// which only looks at the first error of its type.
+func anyError(err error, match func(err error) bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if match(err) {
+		return true
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return anyError(e.Unwrap(), match)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			if anyError(inner, match) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func CheckInvalidConnection(result any, err error) exec.ErrorType {
 	if errors.Is(err, mysql.ErrInvalidConn) {
 		return exec.ErrorTypeRetryable
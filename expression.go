@@ -1,6 +1,7 @@
 package sqlc
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -16,6 +17,8 @@ import (
 //   - ORDER BY directions (Asc, Desc)
 //   - Aliases (As)
 //   - Bind parameters (via Param)
+//   - Subqueries (via SubQuery, Exists, NotExists)
+//   - Window functions (via Over, OverWindow)
 //
 // Expressions are immutable - each method returns a new Expression instance.
 type Expression struct {
@@ -31,8 +34,12 @@ type Expression struct {
 	isParam      bool // if true, this is a bind parameter (renders as "?")
 	paramValue   any  // the value for bind parameter
 	// For composite expressions (AND, OR, NOT)
-	operator       string // "AND", "OR", "NOT"
+	operator       string // "AND", "OR", "NOT", "EXISTS", "NOT EXISTS"
 	subExpressions []*Expression
+	subquery       *SelectQueryBuilder // renders as "(SELECT ...)"
+	// For window functions
+	over     *WindowSpec // renders as "OVER (...)"
+	overName string      // renders as "OVER name", referencing a named window
 }
 
 // copy creates a shallow copy of the Expression.
@@ -53,6 +60,9 @@ func (e *Expression) copy() *Expression {
 		paramValue:     e.paramValue,
 		operator:       e.operator,
 		subExpressions: e.subExpressions,
+		subquery:       e.subquery,
+		over:           e.over,
+		overName:       e.overName,
 	}
 }
 
@@ -62,7 +72,7 @@ func (e *Expression) copy() *Expression {
 // For complex expressions, it wraps the entire expression as a subexpression.
 func (e *Expression) wrapWithFunction(functionName string) *Expression {
 	// If the expression already has a function or is not a simple column, wrap it as a subexpression
-	if e.function != "" || len(e.subExpressions) > 0 {
+	if e.function != "" || len(e.subExpressions) > 0 || e.subquery != nil {
 		return &Expression{
 			function:       functionName,
 			subExpressions: []*Expression{e},
@@ -83,7 +93,7 @@ func (e *Expression) wrapWithFunction(functionName string) *Expression {
 // For complex expressions, it wraps the entire expression as a subexpression.
 func (e *Expression) wrapWithFunctionArgs(functionName string, args ...any) *Expression {
 	// If the expression already has a function or is not a simple column, wrap it as a subexpression
-	if e.function != "" || len(e.subExpressions) > 0 {
+	if e.function != "" || len(e.subExpressions) > 0 || e.subquery != nil {
 		return &Expression{
 			function:       functionName,
 			subExpressions: []*Expression{e},
@@ -173,6 +183,50 @@ func Param(value any) *Expression {
 	}
 }
 
+// SubQuery creates an expression from a SELECT query builder.
+// The subquery is rendered in parentheses and its parameters are merged into the
+// outer query in the order they appear. It can be used as a scalar subquery in
+// projections or conditions.
+// A *SelectQueryBuilder can also be passed directly as a condition value, e.g. to In().
+//
+// Example:
+//
+//	SubQuery(From("orders").Columns(Col("*").Count()).Where("orders.user_id = users.id")).As("order_count")
+//	// (SELECT COUNT(*) FROM `orders` WHERE orders.user_id = users.id) AS order_count
+//	Col("id").In(From("orders").Columns("user_id").Where("total > ?", 100))
+//	// `id` IN (SELECT `user_id` FROM `orders` WHERE total > ?)
+func SubQuery(query *SelectQueryBuilder) *Expression {
+	return &Expression{subquery: query}
+}
+
+// Exists creates an EXISTS condition for the given subquery.
+// Returns a new Expression that is true if the subquery returns at least one row.
+//
+// Example:
+//
+//	Exists(From("orders").Where("orders.user_id = users.id"))
+//	// EXISTS (SELECT * FROM `orders` WHERE orders.user_id = users.id)
+func Exists(query *SelectQueryBuilder) *Expression {
+	return &Expression{
+		operator:       "EXISTS",
+		subExpressions: []*Expression{SubQuery(query)},
+	}
+}
+
+// NotExists creates a NOT EXISTS condition for the given subquery.
+// Returns a new Expression that is true if the subquery returns no rows.
+//
+// Example:
+//
+//	NotExists(From("orders").Where("orders.user_id = users.id"))
+//	// NOT EXISTS (SELECT * FROM `orders` WHERE orders.user_id = users.id)
+func NotExists(query *SelectQueryBuilder) *Expression {
+	return &Expression{
+		operator:       "NOT EXISTS",
+		subExpressions: []*Expression{SubQuery(query)},
+	}
+}
+
 // toArg converts any value to an Expression suitable for use as a function argument.
 // This is used internally by function helpers to handle mixed argument types:
 //   - *Expression: used as-is
@@ -345,6 +399,7 @@ func (e *Expression) Desc() *Expression {
 //
 //	Col("status").Eq("active")  // `status` = ?
 //	Col("age").Eq(21)           // `age` = ?
+//	Col("o.user_id").Eq(Col("u.id")) // `o`.`user_id` = `u`.`id`
 func (e *Expression) Eq(value any) *Expression {
 	return e.applyCondition("=", value)
 }
@@ -406,17 +461,20 @@ func (e *Expression) Lte(value any) *Expression {
 
 // In creates an IN condition (column IN (values...)).
 // Returns a new Expression that checks if the column value is in the provided list.
+// A single *SelectQueryBuilder value renders as an IN subquery.
 //
 // Example:
 //
 //	Col("status").In("active", "pending", "approved")  // `status` IN (?, ?, ?)
 //	Col("id").In(1, 2, 3, 4, 5)                        // `id` IN (?, ?, ?, ?, ?)
+//	Col("id").In(From("orders").Columns("user_id"))    // `id` IN (SELECT `user_id` FROM `orders`)
 func (e *Expression) In(values ...any) *Expression {
 	return e.applyCondition("IN", values...)
 }
 
 // NotIn creates a NOT IN condition (column NOT IN (values...)).
 // Returns a new Expression that checks if the column value is not in the provided list.
+// A single *SelectQueryBuilder value renders as a NOT IN subquery.
 //
 // Example:
 //
@@ -496,11 +554,11 @@ func (e *Expression) NotBetween(min any, max any) *Expression {
 // toSQL converts the expression to a SQL fragment for SELECT, GROUP BY, or ORDER BY clauses.
 // It handles column quoting, function wrapping, aliases, and direction modifiers.
 // For expressions with conditions (like Eq, Gt), it delegates to toConditionSQL.
-func (e *Expression) toSQL(quote string) string {
+func (e *Expression) toSQL(config *QueryBuilderConfig) string {
 	// If expression has a condition or operator, delegate to toConditionSQL to get the full condition
 	// This handles cases like IF(col = ?, ...) where the condition needs to be rendered
 	if e.condition != "" || e.operator != "" {
-		sql := e.toConditionSQL(quote)
+		sql := e.toConditionSQL(config)
 		// Add alias if present (toConditionSQL doesn't add aliases)
 		if e.alias != "" {
 			sql = fmt.Sprintf("%s AS %s", sql, e.alias)
@@ -509,55 +567,22 @@ func (e *Expression) toSQL(quote string) string {
 		return sql
 	}
 
-	return e.toBaseSQL(quote)
+	return e.toBaseSQL(config)
 }
 
 // toBaseSQL converts the expression to a SQL fragment without considering conditions.
 // This is used internally to avoid infinite recursion between toSQL and toConditionSQL.
-func (e *Expression) toBaseSQL(quote string) string {
-	var sql string
-
-	// Handle bind parameter expressions
-	if e.isParam {
-		sql = "?"
-	} else if e.isLiteral {
-		sql = e.raw // Don't quote literal values
-	} else if e.raw != "" {
-		sql = quoteIdentifier(e.raw, quote)
-	}
+func (e *Expression) toBaseSQL(config *QueryBuilderConfig) string {
+	sql := e.operandSQL(config)
 
 	if e.function != "" {
-		// Handle functions with funcArgs (new parameter-aware style)
-		if len(e.funcArgs) > 0 {
-			argStrs := funk.Map(e.funcArgs, func(arg *Expression) string {
-				return arg.toSQL(quote)
-			})
-			sql = fmt.Sprintf("%s(%s)", e.function, strings.Join(argStrs, ", "))
-		} else if len(e.subExpressions) > 0 {
-			// Handle functions with subExpressions (like CONCAT, CONCAT_WS, COALESCE, CAST)
-			argStrs := funk.Map(e.subExpressions, func(subExpr *Expression) string {
-				return subExpr.toSQL(quote)
-			})
-			// CAST uses space separator: CAST(expr AS type)
-			separator := ", "
-			if e.function == "CAST" {
-				separator = " "
-			}
-			sql = fmt.Sprintf("%s(%s)", e.function, strings.Join(argStrs, separator))
-		} else if e.function == "LOCATE" && len(e.functionArgs) > 0 {
-			// LOCATE has reversed argument order: LOCATE(substr, str) - legacy handling
-			sql = fmt.Sprintf("LOCATE(%v, %s)", e.functionArgs[0], sql)
-		} else {
-			// Build function arguments normally (legacy inline style)
-			args := sql
-			if len(e.functionArgs) > 0 {
-				argStrs := append([]string{sql}, funk.Map(e.functionArgs, func(arg any) string {
-					return fmt.Sprintf("%v", arg)
-				})...)
-				args = strings.Join(argStrs, ", ")
-			}
-			sql = fmt.Sprintf("%s(%s)", e.function, args)
-		}
+		// functions the dialect rejects are reported by checkDialect before the expression is rendered
+		sql, _ = e.functionSQL(config, sql)
+	}
+	if e.over != nil {
+		sql = fmt.Sprintf("%s OVER (%s)", sql, e.over.toSQL(config))
+	} else if e.overName != "" {
+		sql = fmt.Sprintf("%s OVER %s", sql, e.overName)
 	}
 	if e.alias != "" {
 		sql = fmt.Sprintf("%s AS %s", sql, e.alias)
@@ -569,49 +594,281 @@ func (e *Expression) toBaseSQL(quote string) string {
 	return sql
 }
 
+// operandSQL renders the parameter, subquery, literal or column the expression consists of.
+func (e *Expression) operandSQL(config *QueryBuilderConfig) string {
+	switch {
+	case e.isParam:
+		return "?"
+	case e.subquery != nil:
+		return e.subquerySQL(config)
+	case e.isLiteral:
+		return e.raw // Don't quote literal values
+	case e.raw != "":
+		return quoteIdentifier(e.raw, config.IdentifierQuote)
+	default:
+		return ""
+	}
+}
+
+// functionSQL renders the function call of the expression applied to the rendered operand.
+func (e *Expression) functionSQL(config *QueryBuilderConfig, operand string) (string, error) {
+	var args []string
+
+	switch {
+	case len(e.funcArgs) > 0:
+		// Handle functions with funcArgs (new parameter-aware style)
+		args = funk.Map(e.funcArgs, func(arg *Expression) string {
+			return arg.toSQL(config)
+		})
+	case len(e.subExpressions) > 0:
+		// Handle functions with subExpressions (like CONCAT, CONCAT_WS, COALESCE, CAST or nested functions)
+		args = funk.Map(e.subExpressions, func(subExpr *Expression) string {
+			return subExpr.toSQL(config)
+		})
+		// CAST uses space separator: CAST(expr AS type)
+		if e.function == "CAST" {
+			return fmt.Sprintf("%s(%s)", e.function, strings.Join(args, " ")), nil
+		}
+	case operand != "":
+		args = []string{operand}
+	}
+
+	functionArgs := funk.Map(e.functionArgs, func(arg any) string {
+		return fmt.Sprintf("%v", arg)
+	})
+
+	// LOCATE has reversed argument order: LOCATE(substr, str)
+	if e.function == "LOCATE" && len(functionArgs) > 0 {
+		return renderFunction(config, e.function, append(append(functionArgs[:1:1], args...), functionArgs[1:]...))
+	}
+
+	return renderFunction(config, e.function, append(args, functionArgs...))
+}
+
 // toConditionSQL converts the expression to a WHERE condition SQL fragment.
 // It handles simple conditions (=, !=, >, <, etc.), IN/NOT IN, NULL checks,
 // and composite expressions (AND, OR, NOT).
-func (e *Expression) toConditionSQL(quote string) string {
+func (e *Expression) toConditionSQL(config *QueryBuilderConfig) string {
 	// Handle composite expressions (AND, OR, NOT)
 	if e.operator != "" {
-		return e.toCompositeConditionSQL(quote)
+		return e.toCompositeConditionSQL(config)
 	}
 
 	// Handle simple conditions
 	if e.condition == "" {
-		return e.toBaseSQL(quote) // Use toBaseSQL() to handle functions without conditions
+		return e.toBaseSQL(config) // Use toBaseSQL() to handle functions without conditions
 	}
 
 	// Get the column expression (may include function) using toBaseSQL to avoid infinite recursion
-	colExpr := e.toBaseSQL(quote)
+	colExpr := e.toBaseSQL(config)
 
 	if e.condition == "IS NULL" || e.condition == "IS NOT NULL" {
 		return fmt.Sprintf("%s %s", colExpr, e.condition)
 	}
 
+	values := funk.Map(e.parameters, func(param any) string {
+		return conditionValueSQL(param, config)
+	})
+
 	if e.condition == "IN" || e.condition == "NOT IN" {
-		placeholders := make([]string, len(e.parameters))
-		for i := range placeholders {
-			placeholders[i] = "?"
+		// A single subquery already renders its own parentheses
+		if len(e.parameters) == 1 && isSubqueryValue(e.parameters[0]) {
+			return fmt.Sprintf("%s %s %s", colExpr, e.condition, values[0])
 		}
 
-		return fmt.Sprintf("%s %s (%s)", colExpr, e.condition, strings.Join(placeholders, ", "))
+		return fmt.Sprintf("%s %s (%s)", colExpr, e.condition, strings.Join(values, ", "))
 	}
 
 	if e.condition == "BETWEEN" || e.condition == "NOT BETWEEN" {
-		return fmt.Sprintf("%s %s ? AND ?", colExpr, e.condition)
+		if len(values) != 2 {
+			return fmt.Sprintf("%s %s ? AND ?", colExpr, e.condition)
+		}
+
+		return fmt.Sprintf("%s %s %s AND %s", colExpr, e.condition, values[0], values[1])
+	}
+
+	if len(values) == 0 {
+		return fmt.Sprintf("%s %s ?", colExpr, e.condition)
 	}
 
-	return fmt.Sprintf("%s %s ?", colExpr, e.condition)
+	return fmt.Sprintf("%s %s %s", colExpr, e.condition, values[0])
+}
+
+// conditionValueSQL renders a single value of a condition.
+// *Expression values (e.g. Col("o.user_id")) are rendered inline, which allows
+// comparing columns with each other. *SelectQueryBuilder values are rendered as subqueries.
+// All other values are rendered as "?" bind parameters.
+func conditionValueSQL(value any, config *QueryBuilderConfig) string {
+	switch v := value.(type) {
+	case *Expression:
+		if v != nil {
+			return v.toSQL(config)
+		}
+	case *SelectQueryBuilder:
+		if v != nil {
+			return SubQuery(v).toSQL(config)
+		}
+	}
+
+	return "?"
+}
+
+// conditionValueParameters returns the bind parameters of a single condition value.
+// This is the counterpart of conditionValueSQL.
+func conditionValueParameters(value any) []any {
+	switch v := value.(type) {
+	case *Expression:
+		if v != nil {
+			return v.collectParameters()
+		}
+	case *SelectQueryBuilder:
+		if v != nil {
+			return SubQuery(v).collectParameters()
+		}
+	}
+
+	return []any{value}
+}
+
+// isSubqueryValue reports whether a condition value renders as a subquery.
+func isSubqueryValue(value any) bool {
+	switch v := value.(type) {
+	case *SelectQueryBuilder:
+		return v != nil
+	case *Expression:
+		return v != nil && v.subquery != nil && v.function == "" && v.condition == "" && v.alias == ""
+	}
+
+	return false
+}
+
+// subqueryToSql renders the subquery of the expression with the config of the enclosing query,
+// so a subquery built with From() is quoted like the query it is part of. If outer is nil, e.g. when
+// only validating the subquery or collecting its parameters, the config of the subquery is used.
+// The subquery is always rendered with "?" placeholders, the enclosing clause takes care
+// of numbering them, so the parameter indexes stay continuous across the whole query.
+func (e *Expression) subqueryToSql(outer *QueryBuilderConfig) (string, []any, error) {
+	if e.subquery == nil {
+		return "", nil, errors.New("subquery must not be nil")
+	}
+
+	if outer == nil {
+		outer = e.subquery.config
+	}
+
+	config := *outer
+	config.Placeholder = "?"
+
+	sql, params, err := e.subquery.WithConfig(&config).ToSql()
+	if err != nil {
+		return "", nil, fmt.Errorf("could not build subquery: %w", err)
+	}
+
+	return sql, params, nil
+}
+
+// subquerySQL renders the subquery of the expression in parentheses.
+// Errors are reported by validate(), which is called by all clauses accepting expressions.
+func (e *Expression) subquerySQL(config *QueryBuilderConfig) string {
+	sql, _, err := e.subqueryToSql(config)
+	if err != nil {
+		return "()"
+	}
+
+	return fmt.Sprintf("(%s)", sql)
+}
+
+// validate checks the whole expression tree for errors which can not be reported while
+// rendering, e.g. invalid subqueries.
+func (e *Expression) validate() error {
+	if e == nil {
+		return nil
+	}
+
+	if e.subquery != nil {
+		if _, _, err := e.subqueryToSql(nil); err != nil {
+			return err
+		}
+	}
+
+	for _, subExpr := range append(append([]*Expression{}, e.subExpressions...), e.funcArgs...) {
+		if err := subExpr.validate(); err != nil {
+			return err
+		}
+	}
+
+	if e.over != nil {
+		if err := e.over.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, param := range e.parameters {
+		switch v := param.(type) {
+		case *Expression:
+			if err := v.validate(); err != nil {
+				return err
+			}
+		case *SelectQueryBuilder:
+			if err := SubQuery(v).validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkDialect returns an error wrapping ErrNotSupportedByDialect if the dialect of the config
+// rejects a function of the expression or of its sub-expressions.
+func (e *Expression) checkDialect(config *QueryBuilderConfig) error {
+	if e == nil || dialectOf(config) == nil {
+		return nil
+	}
+
+	for _, subExpr := range append(append([]*Expression{}, e.subExpressions...), e.funcArgs...) {
+		if err := subExpr.checkDialect(config); err != nil {
+			return err
+		}
+	}
+
+	if e.over != nil {
+		if err := e.over.checkDialect(config); err != nil {
+			return err
+		}
+	}
+
+	for _, param := range e.parameters {
+		if v, ok := param.(*Expression); ok {
+			if err := v.checkDialect(config); err != nil {
+				return err
+			}
+		}
+	}
+
+	if e.function != "" {
+		if _, err := e.functionSQL(config, e.operandSQL(config)); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // toCompositeConditionSQL handles composite expressions (AND, OR, NOT).
 // It recursively processes sub-expressions and combines them with the appropriate operator.
-func (e *Expression) toCompositeConditionSQL(quote string) string {
+func (e *Expression) toCompositeConditionSQL(config *QueryBuilderConfig) string {
+	if e.operator == "EXISTS" || e.operator == "NOT EXISTS" {
+		if len(e.subExpressions) > 0 {
+			return fmt.Sprintf("%s %s", e.operator, e.subExpressions[0].toBaseSQL(config))
+		}
+
+		return ""
+	}
+
 	if e.operator == "NOT" {
 		if len(e.subExpressions) > 0 {
-			return fmt.Sprintf("NOT (%s)", e.subExpressions[0].toConditionSQL(quote))
+			return fmt.Sprintf("NOT (%s)", e.subExpressions[0].toConditionSQL(config))
 		}
 
 		return ""
@@ -623,7 +880,7 @@ func (e *Expression) toCompositeConditionSQL(quote string) string {
 	}
 
 	parts := funk.Map(e.subExpressions, func(expr *Expression) string {
-		return expr.toConditionSQL(quote)
+		return expr.toConditionSQL(config)
 	})
 
 	return fmt.Sprintf("(%s)", strings.Join(parts, fmt.Sprintf(" %s ", e.operator)))
@@ -642,6 +899,13 @@ func (e *Expression) collectParameters() []any {
 		return []any{e.paramValue}
 	}
 
+	// Subquery parameters come first, they are rendered in place of the column
+	if e.subquery != nil {
+		if _, subqueryParams, err := e.subqueryToSql(nil); err == nil {
+			params = append(params, subqueryParams...)
+		}
+	}
+
 	// If this is a composite expression (AND, OR, NOT), recursively collect from sub-expressions
 	if e.operator != "" {
 		for _, subExpr := range e.subExpressions {
@@ -665,8 +929,16 @@ func (e *Expression) collectParameters() []any {
 		}
 	}
 
+	// Window parameters follow the function they belong to
+	if e.over != nil {
+		params = append(params, e.over.collectParameters()...)
+	}
+
 	// Add condition parameters (for Eq, Gt, In, etc.)
-	params = append(params, e.parameters...)
+	// Expression and subquery values are rendered inline, so their own parameters are collected instead
+	for _, param := range e.parameters {
+		params = append(params, conditionValueParameters(param)...)
+	}
 
 	return params
 }
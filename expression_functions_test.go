@@ -333,3 +333,14 @@ func TestCoalesce(t *testing.T) {
 		})
 	}
 }
+
+func TestExpression_ColumnComparison(t *testing.T) {
+	sql, params, err := sqlc.From("orders").
+		Where(sqlc.Col("shipped_at").Gt(sqlc.Col("created_at"))).
+		Where(sqlc.Col("total").Between(sqlc.Col("min_total"), 100)).
+		ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `orders` WHERE `shipped_at` > `created_at` AND `total` BETWEEN `min_total` AND ?", sql)
+	assert.Equal(t, []any{100}, params)
+}
@@ -117,6 +117,16 @@ func TestExpression_StringFunctionsWithArgs(t *testing.T) {
 			expr:     sqlc.Col("email").Locate("@"),
 			expected: "LOCATE('@', `email`)",
 		},
+		{
+			name:     "Locate function on nested function",
+			expr:     sqlc.Col("name").Lower().Locate("a"),
+			expected: "LOCATE('a', LOWER(`name`))",
+		},
+		{
+			name:     "Lpad function on nested function",
+			expr:     sqlc.Col("name").Trim().Lpad(5, "0"),
+			expected: "LPAD(TRIM(`name`), 5, '0')",
+		},
 		{
 			name:     "Lpad function",
 			expr:     sqlc.Col("id").Lpad(5, "0"),
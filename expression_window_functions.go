@@ -0,0 +1,441 @@
+package sqlc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// This file contains window function helpers (MySQL 8.0+, PostgreSQL) including:
+// - Ranking functions (ROW_NUMBER, RANK, DENSE_RANK, PERCENT_RANK, CUME_DIST, NTILE)
+// - Value functions (LAG, LEAD, FIRST_VALUE, LAST_VALUE, NTH_VALUE)
+// - Window specifications for the OVER clause and the named WINDOW clause
+//
+// Any expression, including aggregates like Sum() or Count(), becomes a window function
+// by calling Over() or OverWindow() on it.
+
+// FrameBound is a boundary of a window frame, used with WindowSpec.Rows and WindowSpec.Range.
+type FrameBound string
+
+const (
+	// UnboundedPreceding starts the frame at the first row of the partition.
+	UnboundedPreceding FrameBound = "UNBOUNDED PRECEDING"
+	// UnboundedFollowing ends the frame at the last row of the partition.
+	UnboundedFollowing FrameBound = "UNBOUNDED FOLLOWING"
+	// CurrentRow is the current row (or its peers for RANGE frames).
+	CurrentRow FrameBound = "CURRENT ROW"
+)
+
+// Preceding returns a frame bound n rows (or values for RANGE frames) before the current row.
+//
+// Example:
+//
+//	Window().OrderBy("day").Rows(Preceding(6), CurrentRow) // ROWS BETWEEN 6 PRECEDING AND CURRENT ROW
+func Preceding(n int) FrameBound {
+	return FrameBound(fmt.Sprintf("%d PRECEDING", n))
+}
+
+// Following returns a frame bound n rows (or values for RANGE frames) after the current row.
+//
+// Example:
+//
+//	Window().OrderBy("day").Rows(CurrentRow, Following(1)) // ROWS BETWEEN CURRENT ROW AND 1 FOLLOWING
+func Following(n int) FrameBound {
+	return FrameBound(fmt.Sprintf("%d FOLLOWING", n))
+}
+
+// WindowSpec describes the window of a window function, i.e. the content of an OVER (...) clause
+// or of a named window in the WINDOW clause of a SELECT query.
+// It implements an immutable builder pattern - each method returns a new instance.
+//
+// Example:
+//
+//	Window().PartitionBy("user_id").OrderBy(Col("created_at").Desc())
+//	// PARTITION BY `user_id` ORDER BY `created_at` DESC
+type WindowSpec struct {
+	base        string // name of a window defined in the WINDOW clause this spec extends
+	partitionBy []any
+	orderBy     []any
+	frame       string
+	err         error
+}
+
+// Window creates a new, empty window specification.
+// An empty specification renders as OVER () and spans all rows of the result.
+//
+// Example:
+//
+//	Col("total").Sum().Over(Window())                                // SUM(`total`) OVER ()
+//	RowNumber().Over(Window().PartitionBy("user_id").OrderBy("id"))  // ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `id`)
+func Window() *WindowSpec {
+	return &WindowSpec{}
+}
+
+// WindowFrom creates a new window specification extending a named window defined with
+// SelectQueryBuilder.Window(). The named window must not define a frame itself.
+//
+// Example:
+//
+//	Col("total").Sum().Over(WindowFrom("w").Rows(UnboundedPreceding, CurrentRow))
+//	// SUM(`total`) OVER (w ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)
+func WindowFrom(name string) *WindowSpec {
+	return &WindowSpec{base: name}
+}
+
+// copy creates a copy of the window specification.
+// This is used internally to implement the immutable builder pattern.
+func (w *WindowSpec) copy() *WindowSpec {
+	return &WindowSpec{
+		base:        w.base,
+		partitionBy: append([]any{}, w.partitionBy...),
+		orderBy:     append([]any{}, w.orderBy...),
+		frame:       w.frame,
+		err:         w.err,
+	}
+}
+
+// PartitionBy sets the PARTITION BY columns of the window.
+// Accepts strings (column names) or *Expression objects.
+// Replaces any previously set PARTITION BY columns.
+// Returns a new window specification.
+//
+// Example:
+//
+//	Window().PartitionBy("country", "city")  // PARTITION BY `country`, `city`
+//	Window().PartitionBy(Col("created_at").Date())  // PARTITION BY DATE(`created_at`)
+func (w *WindowSpec) PartitionBy(cols ...any) *WindowSpec {
+	newSpec := w.copy()
+	newSpec.partitionBy = cols
+
+	for i, col := range cols {
+		if !isWindowColumn(col) {
+			newSpec.err = fmt.Errorf("invalid type for PartitionBy argument %d: expected string or *Expression, got %T", i, col)
+		}
+	}
+
+	return newSpec
+}
+
+// OrderBy sets the ORDER BY clause of the window.
+// Accepts strings (column names with optional ASC/DESC) or *Expression objects.
+// Replaces any previously set ORDER BY clause.
+// Returns a new window specification.
+//
+// Example:
+//
+//	Window().OrderBy("score DESC", "id")        // ORDER BY `score` DESC, `id`
+//	Window().OrderBy(Col("created_at").Desc())  // ORDER BY `created_at` DESC
+func (w *WindowSpec) OrderBy(cols ...any) *WindowSpec {
+	newSpec := w.copy()
+	newSpec.orderBy = cols
+
+	for i, col := range cols {
+		if !isWindowColumn(col) {
+			newSpec.err = fmt.Errorf("invalid type for OrderBy argument %d: expected string or *Expression, got %T", i, col)
+		}
+	}
+
+	return newSpec
+}
+
+// Rows sets a ROWS frame for the window. Without an end bound, the frame ends at the current row.
+// Returns a new window specification.
+//
+// Example:
+//
+//	Window().OrderBy("day").Rows(UnboundedPreceding)             // ROWS UNBOUNDED PRECEDING
+//	Window().OrderBy("day").Rows(Preceding(6), CurrentRow)       // ROWS BETWEEN 6 PRECEDING AND CURRENT ROW
+func (w *WindowSpec) Rows(start FrameBound, end ...FrameBound) *WindowSpec {
+	return w.withFrame("ROWS", start, end...)
+}
+
+// Range sets a RANGE frame for the window. Without an end bound, the frame ends at the current row.
+// Returns a new window specification.
+//
+// Example:
+//
+//	Window().OrderBy("price").Range(UnboundedPreceding, CurrentRow) // RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+func (w *WindowSpec) Range(start FrameBound, end ...FrameBound) *WindowSpec {
+	return w.withFrame("RANGE", start, end...)
+}
+
+// withFrame sets the frame of the window using the given unit (ROWS or RANGE).
+func (w *WindowSpec) withFrame(unit string, start FrameBound, end ...FrameBound) *WindowSpec {
+	newSpec := w.copy()
+
+	if len(end) == 0 {
+		newSpec.frame = fmt.Sprintf("%s %s", unit, start)
+	} else {
+		newSpec.frame = fmt.Sprintf("%s BETWEEN %s AND %s", unit, start, end[0])
+	}
+
+	return newSpec
+}
+
+// isWindowColumn reports whether the value can be used as a PARTITION BY or ORDER BY column.
+func isWindowColumn(col any) bool {
+	switch v := col.(type) {
+	case string:
+		return true
+	case *Expression:
+		return v != nil
+	}
+
+	return false
+}
+
+// toSQL renders the window specification without the surrounding parentheses.
+func (w *WindowSpec) toSQL(config *QueryBuilderConfig) string {
+	var parts []string
+
+	if w.base != "" {
+		parts = append(parts, w.base)
+	}
+
+	if len(w.partitionBy) > 0 {
+		cols := make([]string, 0, len(w.partitionBy))
+		for _, col := range w.partitionBy {
+			switch v := col.(type) {
+			case string:
+				cols = append(cols, quoteIdentifier(v, config.IdentifierQuote))
+			case *Expression:
+				cols = append(cols, v.toSQL(config))
+			}
+		}
+		parts = append(parts, "PARTITION BY "+strings.Join(cols, ", "))
+	}
+
+	if len(w.orderBy) > 0 {
+		cols := make([]string, 0, len(w.orderBy))
+		for _, col := range w.orderBy {
+			switch v := col.(type) {
+			case string:
+				cols = append(cols, quoteOrderByClause(v, config.IdentifierQuote))
+			case *Expression:
+				cols = append(cols, v.toSQL(config))
+			}
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(cols, ", "))
+	}
+
+	if w.frame != "" {
+		parts = append(parts, w.frame)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// collectParameters collects the bind parameters of the PARTITION BY and ORDER BY expressions
+// in the order they are rendered.
+func (w *WindowSpec) collectParameters() []any {
+	var params []any
+
+	for _, col := range append(append([]any{}, w.partitionBy...), w.orderBy...) {
+		if expr, ok := col.(*Expression); ok {
+			params = append(params, expr.collectParameters()...)
+		}
+	}
+
+	return params
+}
+
+// validate checks the window specification and its expressions for errors.
+func (w *WindowSpec) validate() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	for _, col := range append(append([]any{}, w.partitionBy...), w.orderBy...) {
+		if expr, ok := col.(*Expression); ok {
+			if err := expr.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkDialect returns an error if the dialect of the config rejects a function of the expressions.
+func (w *WindowSpec) checkDialect(config *QueryBuilderConfig) error {
+	for _, col := range append(append([]any{}, w.partitionBy...), w.orderBy...) {
+		if expr, ok := col.(*Expression); ok {
+			if err := expr.checkDialect(config); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Over turns the expression into a window function evaluated over the given window.
+// Works with the window functions of this file as well as with aggregates like Sum() or Count().
+// Returns a new Expression representing expr OVER (spec).
+//
+// Example:
+//
+//	RowNumber().Over(Window().PartitionBy("user_id").OrderBy(Col("created_at").Desc())).As("rn")
+//	// ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `created_at` DESC) AS rn
+//	Col("amount").Sum().Over(Window().OrderBy("day").Rows(UnboundedPreceding, CurrentRow)).As("running_total")
+//	// SUM(`amount`) OVER (ORDER BY `day` ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS running_total
+func (e *Expression) Over(spec *WindowSpec) *Expression {
+	expr := e.copy()
+	expr.overName = ""
+	expr.over = spec
+
+	if spec == nil {
+		expr.over = Window()
+	}
+
+	return expr
+}
+
+// OverWindow turns the expression into a window function evaluated over a named window
+// defined with SelectQueryBuilder.Window().
+// Returns a new Expression representing expr OVER name.
+//
+// Example:
+//
+//	From("scores").
+//		Columns("player", Rank().OverWindow("w").As("pos")).
+//		Window("w", Window().OrderBy("points DESC"))
+//	// SELECT `player`, RANK() OVER w AS pos FROM `scores` WINDOW w AS (ORDER BY `points` DESC)
+func (e *Expression) OverWindow(name string) *Expression {
+	expr := e.copy()
+	expr.over = nil
+	expr.overName = name
+
+	return expr
+}
+
+// RowNumber returns the number of the current row within its partition, starting at 1.
+// Returns a new Expression representing ROW_NUMBER().
+//
+// Example:
+//
+//	RowNumber().Over(Window().PartitionBy("email").OrderBy("id")).As("rn")
+//	// ROW_NUMBER() OVER (PARTITION BY `email` ORDER BY `id`) AS rn
+func RowNumber() *Expression {
+	return &Expression{function: "ROW_NUMBER", funcArgs: []*Expression{}}
+}
+
+// Rank returns the rank of the current row within its partition, with gaps for ties.
+// Returns a new Expression representing RANK().
+//
+// Example:
+//
+//	Rank().Over(Window().OrderBy("score DESC")).As("position")
+//	// RANK() OVER (ORDER BY `score` DESC) AS position
+func Rank() *Expression {
+	return &Expression{function: "RANK", funcArgs: []*Expression{}}
+}
+
+// DenseRank returns the rank of the current row within its partition, without gaps for ties.
+// Returns a new Expression representing DENSE_RANK().
+//
+// Example:
+//
+//	DenseRank().Over(Window().OrderBy("score DESC"))  // DENSE_RANK() OVER (ORDER BY `score` DESC)
+func DenseRank() *Expression {
+	return &Expression{function: "DENSE_RANK", funcArgs: []*Expression{}}
+}
+
+// PercentRank returns the relative rank of the current row as a value between 0 and 1.
+// Returns a new Expression representing PERCENT_RANK().
+//
+// Example:
+//
+//	PercentRank().Over(Window().OrderBy("score"))  // PERCENT_RANK() OVER (ORDER BY `score`)
+func PercentRank() *Expression {
+	return &Expression{function: "PERCENT_RANK", funcArgs: []*Expression{}}
+}
+
+// CumeDist returns the cumulative distribution of the current row as a value between 0 and 1.
+// Returns a new Expression representing CUME_DIST().
+//
+// Example:
+//
+//	CumeDist().Over(Window().OrderBy("score"))  // CUME_DIST() OVER (ORDER BY `score`)
+func CumeDist() *Expression {
+	return &Expression{function: "CUME_DIST", funcArgs: []*Expression{}}
+}
+
+// Ntile divides the rows of the partition into the given number of buckets and returns the bucket
+// of the current row. The number of buckets is rendered inline.
+// Returns a new Expression representing NTILE(buckets).
+//
+// Example:
+//
+//	Ntile(4).Over(Window().OrderBy("score"))  // NTILE(4) OVER (ORDER BY `score`)
+func Ntile(buckets int) *Expression {
+	return buildFunc("NTILE", Lit(buckets))
+}
+
+// Lag returns the value of expr from the row offset rows before the current row within the partition.
+// The offset is rendered inline, the optional default value (returned if there is no such row)
+// is treated as a bind parameter.
+// Returns a new Expression representing LAG(expr, offset[, default]).
+//
+// Example:
+//
+//	Lag(Col("price"), 1).Over(Window().OrderBy("day"))     // LAG(`price`, 1) OVER (ORDER BY `day`)
+//	Lag(Col("price"), 1, 0).Over(Window().OrderBy("day"))  // LAG(`price`, 1, ?) OVER (ORDER BY `day`)
+func Lag(expr *Expression, offset int, defaultValue ...any) *Expression {
+	return buildFunc("LAG", offsetFuncArgs(expr, offset, defaultValue)...)
+}
+
+// Lead returns the value of expr from the row offset rows after the current row within the partition.
+// The offset is rendered inline, the optional default value (returned if there is no such row)
+// is treated as a bind parameter.
+// Returns a new Expression representing LEAD(expr, offset[, default]).
+//
+// Example:
+//
+//	Lead(Col("price"), 1).Over(Window().OrderBy("day"))  // LEAD(`price`, 1) OVER (ORDER BY `day`)
+func Lead(expr *Expression, offset int, defaultValue ...any) *Expression {
+	return buildFunc("LEAD", offsetFuncArgs(expr, offset, defaultValue)...)
+}
+
+// offsetFuncArgs builds the arguments of LAG and LEAD.
+func offsetFuncArgs(expr *Expression, offset int, defaultValue []any) []any {
+	args := []any{expr, Lit(offset)}
+	if len(defaultValue) > 0 {
+		args = append(args, defaultValue[0])
+	}
+
+	return args
+}
+
+// FirstValue returns the value of expr from the first row of the window frame.
+// Returns a new Expression representing FIRST_VALUE(expr).
+//
+// Example:
+//
+//	FirstValue(Col("price")).Over(Window().PartitionBy("product_id").OrderBy("day"))
+//	// FIRST_VALUE(`price`) OVER (PARTITION BY `product_id` ORDER BY `day`)
+func FirstValue(expr *Expression) *Expression {
+	return buildFunc("FIRST_VALUE", expr)
+}
+
+// LastValue returns the value of expr from the last row of the window frame.
+// Note that the default frame ends at the current row, use Rows(UnboundedPreceding, UnboundedFollowing)
+// to get the last row of the partition.
+// Returns a new Expression representing LAST_VALUE(expr).
+//
+// Example:
+//
+//	LastValue(Col("price")).Over(Window().OrderBy("day").Rows(UnboundedPreceding, UnboundedFollowing))
+func LastValue(expr *Expression) *Expression {
+	return buildFunc("LAST_VALUE", expr)
+}
+
+// NthValue returns the value of expr from the n-th row of the window frame, starting at 1.
+// The row number is rendered inline.
+// Returns a new Expression representing NTH_VALUE(expr, n).
+//
+// Example:
+//
+//	NthValue(Col("name"), 2).Over(Window().OrderBy("score DESC"))  // NTH_VALUE(`name`, 2) OVER (ORDER BY `score` DESC)
+func NthValue(expr *Expression, n int) *Expression {
+	return buildFunc("NTH_VALUE", expr, Lit(n))
+}
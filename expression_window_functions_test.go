@@ -0,0 +1,173 @@
+package sqlc_test
+
+import (
+	"testing"
+
+	"github.com/gosoline-project/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowRankingFunctions(t *testing.T) {
+	window := sqlc.Window().PartitionBy("league").OrderBy("points DESC")
+
+	tests := []struct {
+		name     string
+		expr     *sqlc.Expression
+		expected string
+	}{
+		{name: "row number", expr: sqlc.RowNumber(), expected: "ROW_NUMBER()"},
+		{name: "rank", expr: sqlc.Rank(), expected: "RANK()"},
+		{name: "dense rank", expr: sqlc.DenseRank(), expected: "DENSE_RANK()"},
+		{name: "percent rank", expr: sqlc.PercentRank(), expected: "PERCENT_RANK()"},
+		{name: "cume dist", expr: sqlc.CumeDist(), expected: "CUME_DIST()"},
+		{name: "ntile", expr: sqlc.Ntile(4), expected: "NTILE(4)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, params, err := sqlc.From("scores").Columns("player", tt.expr.Over(window).As("pos")).ToSql()
+			require.NoError(t, err)
+
+			assert.Equal(t, "SELECT `player`, "+tt.expected+" OVER (PARTITION BY `league` ORDER BY `points` DESC) AS pos FROM `scores`", sql)
+			assert.Empty(t, params)
+		})
+	}
+}
+
+func TestWindowValueFunctions(t *testing.T) {
+	window := sqlc.Window().PartitionBy("product_id").OrderBy(sqlc.Col("day").Asc())
+
+	tests := []struct {
+		name           string
+		expr           *sqlc.Expression
+		expected       string
+		expectedParams []any
+	}{
+		{name: "lag", expr: sqlc.Lag(sqlc.Col("price"), 1), expected: "LAG(`price`, 1)"},
+		{name: "lag with default", expr: sqlc.Lag(sqlc.Col("price"), 2, 0), expected: "LAG(`price`, 2, ?)", expectedParams: []any{0}},
+		{name: "lead", expr: sqlc.Lead(sqlc.Col("price"), 1), expected: "LEAD(`price`, 1)"},
+		{name: "first value", expr: sqlc.FirstValue(sqlc.Col("price")), expected: "FIRST_VALUE(`price`)"},
+		{name: "last value", expr: sqlc.LastValue(sqlc.Col("price")), expected: "LAST_VALUE(`price`)"},
+		{name: "nth value", expr: sqlc.NthValue(sqlc.Col("price"), 3), expected: "NTH_VALUE(`price`, 3)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, params, err := sqlc.From("prices").Columns(tt.expr.Over(window)).ToSql()
+			require.NoError(t, err)
+
+			assert.Equal(t, "SELECT "+tt.expected+" OVER (PARTITION BY `product_id` ORDER BY `day` ASC) FROM `prices`", sql)
+			assert.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
+
+func TestWindowAggregatesAndFrames(t *testing.T) {
+	t.Run("empty window", func(t *testing.T) {
+		sql, _, err := sqlc.From("orders").Columns("id", sqlc.Col("total").Sum().Over(sqlc.Window()).As("grand_total")).ToSql()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT `id`, SUM(`total`) OVER () AS grand_total FROM `orders`", sql)
+	})
+
+	t.Run("rows between", func(t *testing.T) {
+		expr := sqlc.Col("amount").Sum().Over(sqlc.Window().OrderBy("day").Rows(sqlc.UnboundedPreceding, sqlc.CurrentRow))
+
+		sql, _, err := sqlc.From("payments").Columns(expr.As("running_total")).ToSql()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT SUM(`amount`) OVER (ORDER BY `day` ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS running_total FROM `payments`", sql)
+	})
+
+	t.Run("moving average", func(t *testing.T) {
+		expr := sqlc.Col("amount").Avg().Over(sqlc.Window().OrderBy("day").Rows(sqlc.Preceding(6), sqlc.Following(0)))
+
+		sql, _, err := sqlc.From("payments").Columns(expr).ToSql()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT AVG(`amount`) OVER (ORDER BY `day` ROWS BETWEEN 6 PRECEDING AND 0 FOLLOWING) FROM `payments`", sql)
+	})
+
+	t.Run("range with single bound", func(t *testing.T) {
+		expr := sqlc.Col("*").Count().Over(sqlc.Window().OrderBy("price").Range(sqlc.UnboundedPreceding))
+
+		sql, _, err := sqlc.From("products").Columns(expr).ToSql()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(*) OVER (ORDER BY `price` RANGE UNBOUNDED PRECEDING) FROM `products`", sql)
+	})
+
+	t.Run("window expressions with bind parameters", func(t *testing.T) {
+		window := sqlc.Window().PartitionBy(sqlc.DateFormat(sqlc.Col("created_at"), "%Y-%m")).OrderBy(sqlc.Col("total").Desc())
+
+		sql, params, err := sqlc.From("orders").
+			Columns(sqlc.IfNull(sqlc.Col("note"), "-"), sqlc.RowNumber().Over(window).As("rn")).
+			Where("status = ?", "paid").
+			ToSql()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT IFNULL(`note`, ?), ROW_NUMBER() OVER (PARTITION BY DATE_FORMAT(`created_at`, ?) ORDER BY `total` DESC) AS rn FROM `orders` WHERE status = ?", sql)
+		assert.Equal(t, []any{"-", "%Y-%m", "paid"}, params)
+	})
+
+	t.Run("window specs are immutable", func(t *testing.T) {
+		base := sqlc.Window().PartitionBy("user_id")
+		_ = base.OrderBy("id")
+
+		sql, _, err := sqlc.From("orders").Columns(sqlc.RowNumber().Over(base)).ToSql()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT ROW_NUMBER() OVER (PARTITION BY `user_id`) FROM `orders`", sql)
+	})
+}
+
+func TestSelectWithNamedWindow(t *testing.T) {
+	q := sqlc.From("orders").
+		Columns(
+			"user_id",
+			sqlc.Col("total").Sum().OverWindow("w").As("running_total"),
+			sqlc.Lag(sqlc.Col("total"), 1).Over(sqlc.WindowFrom("w").Rows(sqlc.UnboundedPreceding)).As("previous_total"),
+		).
+		Where("status = ?", "paid").
+		Window("w", sqlc.Window().PartitionBy("user_id").OrderBy("created_at")).
+		Window("w_all", sqlc.Window()).
+		OrderBy("user_id").
+		Limit(10)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `user_id`, SUM(`total`) OVER w AS running_total, LAG(`total`, 1) OVER (w ROWS UNBOUNDED PRECEDING) AS previous_total "+
+		"FROM `orders` WHERE status = ? WINDOW w AS (PARTITION BY `user_id` ORDER BY `created_at`), w_all AS () ORDER BY `user_id` LIMIT ?", sql)
+	assert.Equal(t, []any{"paid", 10}, params)
+}
+
+func TestWindowPostgreSQLPlaceholders(t *testing.T) {
+	config := &sqlc.QueryBuilderConfig{
+		StructTag:       "db",
+		Placeholder:     "$",
+		IdentifierQuote: `"`,
+	}
+
+	q := sqlc.From("orders").
+		WithConfig(config).
+		Columns("id", sqlc.Lag(sqlc.Col("total"), 1, 0).Over(sqlc.Window().PartitionBy("user_id").OrderBy("id"))).
+		Where("status = ?", "paid").
+		Window("w", sqlc.Window().PartitionBy(sqlc.IfNull(sqlc.Col("region"), "none"))).
+		Limit(5)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `SELECT "id", LAG("total", 1, $1) OVER (PARTITION BY "user_id" ORDER BY "id") FROM "orders" WHERE status = $2 WINDOW w AS (PARTITION BY IFNULL("region", $3)) LIMIT $4`, sql)
+	assert.Equal(t, []any{0, "paid", "none", 5}, params)
+}
+
+func TestWindowErrors(t *testing.T) {
+	_, _, err := sqlc.From("orders").Columns(sqlc.RowNumber().Over(sqlc.Window().OrderBy(1))).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid type for OrderBy argument 0")
+
+	_, _, err = sqlc.From("orders").Window("w", sqlc.Window().PartitionBy(1.5)).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid window w")
+
+	_, _, err = sqlc.From("orders").Window("", sqlc.Window()).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "window name must not be empty")
+}
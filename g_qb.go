@@ -45,7 +45,7 @@ type QueryBuilderG[T any] struct {
 //	users, err := qb.From("users").
 //		Where("status = ?", "active").
 //		Select(ctx)
-func (q *QueryBuilderG[T]) From(table string) *SelectQueryBuilderG[T] {
+func (q *QueryBuilderG[T]) From(table any) *SelectQueryBuilderG[T] {
 	return FromG[T](table).WithClient(q.client)
 }
 
@@ -73,6 +73,29 @@ func (q *DeleteQueryBuilderG[T]) WithConfig(config *QueryBuilderConfig) *DeleteQ
 	}
 }
 
+// With adds a common table expression which is rendered in a WITH clause.
+// The optional columns are rendered as the column list of the CTE.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	DeleteG[Session]("sessions").
+//		With("inactive", From("users").Columns("id").Where("last_login < ?", cutoff)).
+//		Where("user_id IN (SELECT id FROM inactive)")
+func (q *DeleteQueryBuilderG[T]) With(name string, query *SelectQueryBuilder, columns ...string) *DeleteQueryBuilderG[T] {
+	return &DeleteQueryBuilderG[T]{
+		qb: q.qb.With(name, query, columns...),
+	}
+}
+
+// WithRecursive adds a recursive common table expression which is rendered in a WITH RECURSIVE clause.
+// Returns a new query builder with the CTE added.
+func (q *DeleteQueryBuilderG[T]) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *DeleteQueryBuilderG[T] {
+	return &DeleteQueryBuilderG[T]{
+		qb: q.qb.WithRecursive(name, query, columns...),
+	}
+}
+
 // Where adds a WHERE condition to the query.
 // Multiple Where() calls are combined with AND.
 // Accepts either:
@@ -159,3 +182,33 @@ func (q *DeleteQueryBuilderG[T]) ToSql() (query string, params []any, err error)
 func (q *DeleteQueryBuilderG[T]) Exec(ctx context.Context) (Result, error) {
 	return q.qb.Exec(ctx)
 }
+
+// Returning adds a RETURNING clause, which makes the query return the given columns of the deleted rows.
+// Replaces any previously set RETURNING columns. Use ExecReturning() to execute the query and scan the rows.
+// Returns a new query builder with the RETURNING clause set.
+//
+// Example:
+//
+//	Returning("id", "created_at")   // RETURNING `id`, `created_at`
+func (q *DeleteQueryBuilderG[T]) Returning(cols ...string) *DeleteQueryBuilderG[T] {
+	return &DeleteQueryBuilderG[T]{
+		qb: q.qb.Returning(cols...),
+	}
+}
+
+// ExecReturning executes the query and returns the rows of the RETURNING clause as a slice of type T.
+// If no columns have been set via Returning(), the columns are derived from the struct tags of T.
+// Returns ErrReturningNotSupported if the dialect of the client does not support RETURNING (e.g. MySQL).
+//
+// Example:
+//
+//	sessions, err := DeleteG[Session]("sessions").
+//		WithClient(client).
+//		Where("expires_at < ?", now).
+//		ExecReturning(ctx)
+func (q *DeleteQueryBuilderG[T]) ExecReturning(ctx context.Context) ([]T, error) {
+	var result []T
+	err := q.qb.ExecReturning(ctx, &result)
+
+	return result, err
+}
@@ -2,6 +2,7 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
 // InsertQueryBuilderG is a generic wrapper around InsertQueryBuilder that provides
@@ -71,6 +72,43 @@ func (q *InsertQueryBuilderG[T]) WithConfig(config *QueryBuilderConfig) *InsertQ
 	}
 }
 
+// FromSelect uses the given query as the source of the inserted rows (INSERT ... SELECT).
+// Returns a new query builder with the source query set.
+//
+// Example:
+//
+//	IntoG[Order]("archived_orders").
+//		FromSelect(From("orders").Where("created_at < ?", cutoff))
+//	// INSERT INTO `archived_orders` SELECT * FROM `orders` WHERE created_at < ?
+func (q *InsertQueryBuilderG[T]) FromSelect(query *SelectQueryBuilder) *InsertQueryBuilderG[T] {
+	return &InsertQueryBuilderG[T]{
+		qb: q.qb.FromSelect(query),
+	}
+}
+
+// With adds a common table expression which is rendered in a WITH clause.
+// The optional columns are rendered as the column list of the CTE.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	IntoG[Order]("paid_orders").
+//		With("paid", From("orders").Where("status = ?", "paid")).
+//		FromSelect(From("paid"))
+func (q *InsertQueryBuilderG[T]) With(name string, query *SelectQueryBuilder, columns ...string) *InsertQueryBuilderG[T] {
+	return &InsertQueryBuilderG[T]{
+		qb: q.qb.With(name, query, columns...),
+	}
+}
+
+// WithRecursive adds a recursive common table expression which is rendered in a WITH RECURSIVE clause.
+// Returns a new query builder with the CTE added.
+func (q *InsertQueryBuilderG[T]) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *InsertQueryBuilderG[T] {
+	return &InsertQueryBuilderG[T]{
+		qb: q.qb.WithRecursive(name, query, columns...),
+	}
+}
+
 // Insert sets the query to use INSERT mode (default).
 // Returns a new query builder configured for INSERT operations.
 //
@@ -175,6 +213,67 @@ func (q *InsertQueryBuilderG[T]) OnDuplicateKeyUpdate(assignments ...Assignment)
 	}
 }
 
+// OnConflictBuilderG is a generic wrapper around OnConflictBuilder, which returns the
+// generic insert query builder once the ON CONFLICT clause is completed.
+type OnConflictBuilderG[T any] struct {
+	ob *OnConflictBuilder
+}
+
+// OnConflict starts an ON CONFLICT clause (PostgreSQL) for the given conflict target columns.
+// The clause has to be completed with DoUpdate() or DoNothing().
+//
+// Example:
+//
+//	IntoG[User]("users").
+//		Records(user).
+//		OnConflict("id").
+//		DoUpdate(sqlc.AssignExcluded("name"), sqlc.AssignExcluded("email"))
+func (q *InsertQueryBuilderG[T]) OnConflict(cols ...string) *OnConflictBuilderG[T] {
+	return &OnConflictBuilderG[T]{
+		ob: q.qb.OnConflict(cols...),
+	}
+}
+
+// Where adds a condition to the DO UPDATE action, only rows matching it are updated.
+// Returns a new OnConflictBuilderG with the condition added.
+func (b *OnConflictBuilderG[T]) Where(condition any, params ...any) *OnConflictBuilderG[T] {
+	return &OnConflictBuilderG[T]{
+		ob: b.ob.Where(condition, params...),
+	}
+}
+
+// DoUpdate completes the clause with a DO UPDATE SET action using the given assignments.
+// Returns a new query builder with the ON CONFLICT clause set.
+func (b *OnConflictBuilderG[T]) DoUpdate(assignments ...Assignment) *InsertQueryBuilderG[T] {
+	return &InsertQueryBuilderG[T]{
+		qb: b.ob.DoUpdate(assignments...),
+	}
+}
+
+// DoNothing completes the clause with a DO NOTHING action, conflicting rows are skipped.
+// Returns a new query builder with the ON CONFLICT clause set.
+func (b *OnConflictBuilderG[T]) DoNothing() *InsertQueryBuilderG[T] {
+	return &InsertQueryBuilderG[T]{
+		qb: b.ob.DoNothing(),
+	}
+}
+
+// Upsert makes the insert update the given columns of an existing row with the same key
+// instead of failing. The syntax is chosen based on the dialect of the config:
+// ON CONFLICT for dialects supporting it (PostgreSQL), ON DUPLICATE KEY UPDATE otherwise (MySQL).
+// Returns a new query builder with the upsert set.
+//
+// Example:
+//
+//	IntoG[User]("users").
+//		Records(user).
+//		Upsert([]string{"id"}, []string{"name", "email"})
+func (q *InsertQueryBuilderG[T]) Upsert(keyCols []string, updateCols []string) *InsertQueryBuilderG[T] {
+	return &InsertQueryBuilderG[T]{
+		qb: q.qb.Upsert(keyCols, updateCols),
+	}
+}
+
 // Columns sets the column list for the insert query.
 // This method replaces any previously set columns.
 // Column names will be automatically quoted in the generated SQL.
@@ -313,3 +412,60 @@ func (q *InsertQueryBuilderG[T]) ToNamedSql() (query string, params []any, err e
 func (q *InsertQueryBuilderG[T]) Exec(ctx context.Context) (Result, error) {
 	return q.qb.Exec(ctx)
 }
+
+// ExecChunked executes the insert query as a series of statements of at most chunkSize rows each.
+// A chunkSize of zero or less sizes the chunks to the bind parameter limit of the database.
+// The returned Result sums RowsAffected across all chunks. See InsertQueryBuilder.ExecChunked for details.
+//
+// Example:
+//
+//	result, err := IntoG[Event]("events").
+//		WithClient(client).
+//		Records(events).
+//		ExecChunked(ctx, 1000)
+func (q *InsertQueryBuilderG[T]) ExecChunked(ctx context.Context, chunkSize int) (Result, error) {
+	return q.qb.ExecChunked(ctx, chunkSize)
+}
+
+// ExecChunkedTx executes the insert query in chunks like ExecChunked, but inside a single transaction,
+// so either all chunks are inserted or none of them. See InsertQueryBuilder.ExecChunkedTx for details.
+//
+// Example:
+//
+//	result, err := IntoG[Event]("events").
+//		WithClient(client).
+//		Records(events).
+//		ExecChunkedTx(ctx, 1000)
+func (q *InsertQueryBuilderG[T]) ExecChunkedTx(ctx context.Context, chunkSize int, ops ...*sql.TxOptions) (Result, error) {
+	return q.qb.ExecChunkedTx(ctx, chunkSize, ops...)
+}
+
+// Returning adds a RETURNING clause, which makes the query return the given columns of the inserted rows.
+// Replaces any previously set RETURNING columns. Use ExecReturning() to execute the query and scan the rows.
+// Returns a new query builder with the RETURNING clause set.
+//
+// Example:
+//
+//	Returning("id", "created_at")   // RETURNING `id`, `created_at`
+func (q *InsertQueryBuilderG[T]) Returning(cols ...string) *InsertQueryBuilderG[T] {
+	return &InsertQueryBuilderG[T]{
+		qb: q.qb.Returning(cols...),
+	}
+}
+
+// ExecReturning executes the query and returns the rows of the RETURNING clause as a slice of type T.
+// If no columns have been set via Returning(), the columns are derived from the struct tags of T.
+// Returns ErrReturningNotSupported if the dialect of the client does not support RETURNING (e.g. MySQL).
+//
+// Example:
+//
+//	users, err := IntoG[User]("users").
+//		WithClient(client).
+//		Records(user1, user2).
+//		ExecReturning(ctx)
+func (q *InsertQueryBuilderG[T]) ExecReturning(ctx context.Context) ([]T, error) {
+	var result []T
+	err := q.qb.ExecReturning(ctx, &result)
+
+	return result, err
+}
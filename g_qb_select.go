@@ -2,6 +2,7 @@ package sqlc
 
 import (
 	"context"
+	"iter"
 )
 
 // SelectQueryBuilderG is a generic wrapper around SelectQueryBuilder that provides
@@ -33,12 +34,13 @@ type SelectQueryBuilderG[T any] struct {
 
 // FromG creates a new generic SelectQueryBuilder for the specified table.
 // This is the entry point for building type-safe SELECT queries.
+// Like From, the table may also be a *SelectQueryBuilder used as a derived table.
 //
 // Example:
 //
 //	FromG[User]("users")                   // SELECT * FROM `users`
 //	FromG[Order]("orders").As("o")         // SELECT * FROM `orders` AS o
-func FromG[T any](table string) *SelectQueryBuilderG[T] {
+func FromG[T any](table any) *SelectQueryBuilderG[T] {
 	return &SelectQueryBuilderG[T]{
 		qb: From(table),
 	}
@@ -114,6 +116,167 @@ func (q *SelectQueryBuilderG[T]) Distinct() *SelectQueryBuilderG[T] {
 	}
 }
 
+// Join adds an INNER JOIN to the query.
+// The table may contain an optional alias separated by whitespace ("orders o" or "orders AS o").
+// The condition accepts a raw SQL string with parameters or an *Expression.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	FromG[User]("users").As("u").
+//		Join("orders o", "o.user_id = u.id")  // INNER JOIN `orders` AS o ON o.user_id = u.id
+func (q *SelectQueryBuilderG[T]) Join(table string, condition any, params ...any) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.Join(table, condition, params...),
+	}
+}
+
+// LeftJoin adds a LEFT JOIN to the query.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	FromG[User]("users").As("u").
+//		LeftJoin("orders o", "o.user_id = u.id")  // LEFT JOIN `orders` AS o ON o.user_id = u.id
+func (q *SelectQueryBuilderG[T]) LeftJoin(table string, condition any, params ...any) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.LeftJoin(table, condition, params...),
+	}
+}
+
+// RightJoin adds a RIGHT JOIN to the query.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	FromG[Order]("orders").As("o").
+//		RightJoin("users u", "o.user_id = u.id")  // RIGHT JOIN `users` AS u ON o.user_id = u.id
+func (q *SelectQueryBuilderG[T]) RightJoin(table string, condition any, params ...any) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.RightJoin(table, condition, params...),
+	}
+}
+
+// FullJoin adds a FULL OUTER JOIN to the query.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	FromG[User]("users").As("u").
+//		FullJoin("orders o", "o.user_id = u.id")  // FULL OUTER JOIN `orders` AS o ON o.user_id = u.id
+func (q *SelectQueryBuilderG[T]) FullJoin(table string, condition any, params ...any) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.FullJoin(table, condition, params...),
+	}
+}
+
+// CrossJoin adds a CROSS JOIN to the query.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	FromG[Variant]("sizes").CrossJoin("colors")  // CROSS JOIN `colors`
+func (q *SelectQueryBuilderG[T]) CrossJoin(table string) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.CrossJoin(table),
+	}
+}
+
+// With adds a common table expression which is rendered in a WITH clause.
+// The optional columns are rendered as the column list of the CTE.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	FromG[User]("active_users").
+//		With("active_users", From("users").Where("status = ?", "active"))
+//	// WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?) SELECT * FROM `active_users`
+func (q *SelectQueryBuilderG[T]) With(name string, query *SelectQueryBuilder, columns ...string) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.With(name, query, columns...),
+	}
+}
+
+// WithRecursive adds a recursive common table expression which is rendered in a WITH RECURSIVE clause.
+// Returns a new query builder with the CTE added.
+func (q *SelectQueryBuilderG[T]) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.WithRecursive(name, query, columns...),
+	}
+}
+
+// Union combines the query with the given queries using UNION, removing duplicate rows.
+// OrderBy, Limit and Offset called on the result apply to the combined result.
+// Returns a new query builder for the compound query.
+//
+// Example:
+//
+//	FromG[User]("customers").Columns("id", "email").
+//		Union(FromG[User]("leads").Columns("id", "email"))
+//	// SELECT `id`, `email` FROM `customers` UNION SELECT `id`, `email` FROM `leads`
+func (q *SelectQueryBuilderG[T]) Union(queries ...*SelectQueryBuilderG[T]) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.Union(unwrapSelectQueries(queries)...),
+	}
+}
+
+// UnionAll combines the query with the given queries using UNION ALL, keeping duplicate rows.
+// OrderBy, Limit and Offset called on the result apply to the combined result.
+// Returns a new query builder for the compound query.
+//
+// Example:
+//
+//	FromG[User]("customers").Columns("id", "email").
+//		UnionAll(FromG[User]("leads").Columns("id", "email"))
+//	// SELECT `id`, `email` FROM `customers` UNION ALL SELECT `id`, `email` FROM `leads`
+func (q *SelectQueryBuilderG[T]) UnionAll(queries ...*SelectQueryBuilderG[T]) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.UnionAll(unwrapSelectQueries(queries)...),
+	}
+}
+
+// Intersect combines the query with the given queries using INTERSECT, returning only rows present in all results.
+// OrderBy, Limit and Offset called on the result apply to the combined result.
+// Returns a new query builder for the compound query.
+//
+// Example:
+//
+//	FromG[User]("customers").Columns("id", "email").
+//		Intersect(FromG[User]("leads").Columns("id", "email"))
+//	// SELECT `id`, `email` FROM `customers` INTERSECT SELECT `id`, `email` FROM `leads`
+func (q *SelectQueryBuilderG[T]) Intersect(queries ...*SelectQueryBuilderG[T]) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.Intersect(unwrapSelectQueries(queries)...),
+	}
+}
+
+// Except combines the query with the given queries using EXCEPT, returning rows of the first result which are not present in the others.
+// OrderBy, Limit and Offset called on the result apply to the combined result.
+// Returns a new query builder for the compound query.
+//
+// Example:
+//
+//	FromG[User]("customers").Columns("id", "email").
+//		Except(FromG[User]("leads").Columns("id", "email"))
+//	// SELECT `id`, `email` FROM `customers` EXCEPT SELECT `id`, `email` FROM `leads`
+func (q *SelectQueryBuilderG[T]) Except(queries ...*SelectQueryBuilderG[T]) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.Except(unwrapSelectQueries(queries)...),
+	}
+}
+
+// unwrapSelectQueries returns the underlying non-generic query builders.
+func unwrapSelectQueries[T any](queries []*SelectQueryBuilderG[T]) []*SelectQueryBuilder {
+	result := make([]*SelectQueryBuilder, len(queries))
+	for i, query := range queries {
+		if query != nil {
+			result[i] = query.qb
+		}
+	}
+
+	return result
+}
+
 // Where adds a WHERE condition to the query.
 // Multiple Where() calls are combined with AND.
 // Accepts either:
@@ -164,6 +327,20 @@ func (q *SelectQueryBuilderG[T]) Having(condition string, params ...any) *Select
 	}
 }
 
+// Window adds a named window to the WINDOW clause of the query.
+// Window functions reference it with OverWindow(name) or extend it with WindowFrom(name).
+// Returns a new query builder with the window added.
+//
+// Example:
+//
+//	Columns("id", RowNumber().OverWindow("w").As("rn")).
+//		Window("w", Window().PartitionBy("user_id").OrderBy("created_at"))
+func (q *SelectQueryBuilderG[T]) Window(name string, spec *WindowSpec) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.Window(name, spec),
+	}
+}
+
 // OrderBy sets the ORDER BY clause for the query.
 // Accepts strings (column names with optional ASC/DESC) or *Expression objects.
 // Replaces any previously set ORDER BY clause.
@@ -204,6 +381,66 @@ func (q *SelectQueryBuilderG[T]) Offset(offset int) *SelectQueryBuilderG[T] {
 	}
 }
 
+// ForUpdate locks the selected rows against concurrent updates, deletes and locking reads
+// until the end of the transaction. Queries with a locking clause can only be executed inside a transaction.
+//
+// Example:
+//
+//	FromG[Job]("jobs").WithClient(tx).Where("status = ?", "pending").Limit(10).ForUpdate()
+func (q *SelectQueryBuilderG[T]) ForUpdate() *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.ForUpdate(),
+	}
+}
+
+// ForShare locks the selected rows against concurrent updates and deletes until the end of
+// the transaction. Queries with a locking clause can only be executed inside a transaction.
+//
+// Example:
+//
+//	FromG[Account]("accounts").WithClient(tx).Where("id = ?", 1).ForShare()
+func (q *SelectQueryBuilderG[T]) ForShare() *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.ForShare(),
+	}
+}
+
+// SkipLocked skips rows which are locked by another transaction instead of waiting for them.
+// Requires ForUpdate() or ForShare().
+//
+// Example:
+//
+//	FromG[Job]("jobs").WithClient(tx).Limit(10).ForUpdate().SkipLocked()
+func (q *SelectQueryBuilderG[T]) SkipLocked() *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.SkipLocked(),
+	}
+}
+
+// NoWait makes the query fail immediately if a selected row is locked by another transaction.
+// Requires ForUpdate() or ForShare().
+//
+// Example:
+//
+//	FromG[Account]("accounts").WithClient(tx).Where("id = ?", 1).ForUpdate().NoWait()
+func (q *SelectQueryBuilderG[T]) NoWait() *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.NoWait(),
+	}
+}
+
+// Of restricts the locking clause to the rows of the given tables.
+// Requires ForUpdate() or ForShare().
+//
+// Example:
+//
+//	FromG[Job]("jobs").As("j").Join("queues q", "q.id = j.queue_id").ForUpdate().Of("j")
+func (q *SelectQueryBuilderG[T]) Of(tables ...string) *SelectQueryBuilderG[T] {
+	return &SelectQueryBuilderG[T]{
+		qb: q.qb.Of(tables...),
+	}
+}
+
 // ToSql generates the final SQL query string and parameter list.
 // Returns the SQL string, parameters slice, and any error encountered during building.
 // This method should be called when you need the raw SQL for manual execution.
@@ -270,3 +507,113 @@ func (q *SelectQueryBuilderG[T]) Select(ctx context.Context) ([]T, error) {
 
 	return result, err
 }
+
+// SelectWithTotal executes the query like Select and additionally returns the total number of entities
+// matching the query regardless of its LIMIT and OFFSET, as needed for offset paged responses.
+// See SelectQueryBuilder.SelectWithTotal for details.
+//
+// Example:
+//
+//	users, total, err := FromG[User]("users").
+//	    WithClient(client).
+//	    Where("status = ?", "active").
+//	    OrderBy("name").
+//	    Limit(20).
+//	    Offset(40).
+//	    SelectWithTotal(ctx)
+func (q *SelectQueryBuilderG[T]) SelectWithTotal(ctx context.Context) ([]T, int64, error) {
+	var result []T
+	total, err := q.qb.SelectWithTotal(ctx, &result)
+
+	return result, total, err
+}
+
+// Count returns the number of entities the query would return without its ORDER BY, LIMIT and OFFSET clauses.
+// See SelectQueryBuilder.Count for details.
+//
+// Example:
+//
+//	count, err := FromG[User]("users").
+//	    WithClient(client).
+//	    Where("status = ?", "active").
+//	    Count(ctx)
+func (q *SelectQueryBuilderG[T]) Count(ctx context.Context) (int64, error) {
+	return q.qb.Count(ctx)
+}
+
+// Exists returns whether the query would return any entity.
+// See SelectQueryBuilder.Exists for details.
+//
+// Example:
+//
+//	exists, err := FromG[User]("users").
+//	    WithClient(client).
+//	    Where("email = ?", email).
+//	    Exists(ctx)
+func (q *SelectQueryBuilderG[T]) Exists(ctx context.Context) (bool, error) {
+	return q.qb.Exists(ctx)
+}
+
+// Iterate executes the query and returns an iterator over the result, which is scanned row by row
+// instead of loading it into a slice. Memory stays constant regardless of the size of the result,
+// which makes it suitable for exports over millions of rows. Errors are yielded once with a zero
+// entity, after which the iteration stops. The rows are closed when the iteration finishes or is stopped early.
+//
+// If no columns have been explicitly set via Columns() or Column(), Iterate will
+// automatically call ForType() to map struct fields to database columns using the
+// `db` struct tag.
+//
+// Example:
+//
+//	for user, err := range FromG[User]("users").WithClient(client).OrderBy("id").Iterate(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//
+//		fmt.Println(user.Name)
+//	}
+func (q *SelectQueryBuilderG[T]) Iterate(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var row T
+
+		for err := range q.qb.Iterate(ctx, &row) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+
+				return
+			}
+
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Paginate executes the query as keyset pagination and returns the page of entities after (or before) the cursor,
+// together with the cursors of the following and preceding pages. Pass an empty cursor to get the first page.
+// See SelectQueryBuilder.Paginate for the requirements on the ORDER BY clause and the cursor key.
+//
+// Example:
+//
+//	page, err := FromG[User]("users").
+//		WithClient(client).
+//		OrderBy("name ASC", "id ASC").
+//		Paginate(ctx, request.Cursor, 50)
+//	if err != nil {
+//	    return err
+//	}
+//	// page.Items is []User, page.Next and page.Prev are empty at the last and first page
+func (q *SelectQueryBuilderG[T]) Paginate(ctx context.Context, cursor string, pageSize int) (*Page[T], error) {
+	page := &Page[T]{}
+
+	cursors, err := q.qb.Paginate(ctx, cursor, pageSize, &page.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	page.PageCursors = *cursors
+
+	return page, nil
+}
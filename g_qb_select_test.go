@@ -181,3 +181,33 @@ func TestGenericSelectWithExpressions(t *testing.T) {
 	assert.Len(t, params, 1)
 	assert.Equal(t, 18, params[0])
 }
+
+func TestGenericSelectWithJoins(t *testing.T) {
+	q := sqlc.FromG[TestUser]("users").
+		As("u").
+		Columns("u.id", "u.name").
+		Join("orders o", "o.user_id = u.id AND o.status = ?", "paid").
+		LeftJoin("profiles p", "p.user_id = u.id").
+		Where("u.age > ?", 18)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `u`.`id`, `u`.`name` FROM `users` AS u INNER JOIN `orders` AS o ON o.user_id = u.id AND o.status = ? LEFT JOIN `profiles` AS p ON p.user_id = u.id WHERE u.age > ?", sql)
+	assert.Equal(t, []any{"paid", 18}, params)
+}
+
+func TestGenericSelectUnion(t *testing.T) {
+	q := sqlc.FromG[TestUser]("users").
+		Columns("id", "name").
+		Where("status = ?", "active").
+		Union(sqlc.FromG[TestUser]("archived_users").Columns("id", "name")).
+		OrderBy("name").
+		Limit(10)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `id`, `name` FROM `users` WHERE status = ? UNION SELECT `id`, `name` FROM `archived_users` ORDER BY `name` LIMIT ?", sql)
+	assert.Equal(t, []any{"active", 10}, params)
+}
@@ -141,6 +141,30 @@ func (q *UpdateQueryBuilderG[T]) SetRecord(record T) *UpdateQueryBuilderG[T] {
 	}
 }
 
+// With adds a common table expression which is rendered in a WITH clause.
+// The optional columns are rendered as the column list of the CTE.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	UpdateG[User]("users").
+//		With("inactive", From("users").Columns("id").Where("last_login < ?", cutoff)).
+//		Set("status", "inactive").
+//		Where("id IN (SELECT id FROM inactive)")
+func (q *UpdateQueryBuilderG[T]) With(name string, query *SelectQueryBuilder, columns ...string) *UpdateQueryBuilderG[T] {
+	return &UpdateQueryBuilderG[T]{
+		qb: q.qb.With(name, query, columns...),
+	}
+}
+
+// WithRecursive adds a recursive common table expression which is rendered in a WITH RECURSIVE clause.
+// Returns a new query builder with the CTE added.
+func (q *UpdateQueryBuilderG[T]) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *UpdateQueryBuilderG[T] {
+	return &UpdateQueryBuilderG[T]{
+		qb: q.qb.WithRecursive(name, query, columns...),
+	}
+}
+
 // Where adds a WHERE condition to the query.
 // Multiple Where() calls are combined with AND.
 // Accepts either:
@@ -227,3 +251,34 @@ func (q *UpdateQueryBuilderG[T]) ToSql() (query string, params []any, err error)
 func (q *UpdateQueryBuilderG[T]) Exec(ctx context.Context) (Result, error) {
 	return q.qb.Exec(ctx)
 }
+
+// Returning adds a RETURNING clause, which makes the query return the given columns of the updated rows.
+// Replaces any previously set RETURNING columns. Use ExecReturning() to execute the query and scan the rows.
+// Returns a new query builder with the RETURNING clause set.
+//
+// Example:
+//
+//	Returning("id", "created_at")   // RETURNING `id`, `created_at`
+func (q *UpdateQueryBuilderG[T]) Returning(cols ...string) *UpdateQueryBuilderG[T] {
+	return &UpdateQueryBuilderG[T]{
+		qb: q.qb.Returning(cols...),
+	}
+}
+
+// ExecReturning executes the query and returns the rows of the RETURNING clause as a slice of type T.
+// If no columns have been set via Returning(), the columns are derived from the struct tags of T.
+// Returns ErrReturningNotSupported if the dialect of the client does not support RETURNING (e.g. MySQL).
+//
+// Example:
+//
+//	users, err := UpdateG[User]("users").
+//		WithClient(client).
+//		Set("status", "active").
+//		Where("last_login > ?", cutoff).
+//		ExecReturning(ctx)
+func (q *UpdateQueryBuilderG[T]) ExecReturning(ctx context.Context) ([]T, error) {
+	var result []T
+	err := q.qb.ExecReturning(ctx, &result)
+
+	return result, err
+}
@@ -0,0 +1,188 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Operation is the Querier method a Call was made with.
+type Operation string
+
+const (
+	OperationGet       Operation = "Get"
+	OperationExec      Operation = "Exec"
+	OperationNamedExec Operation = "NamedExec"
+	OperationPrepare   Operation = "Prepare"
+	OperationQuery     Operation = "Query"
+	OperationQueryRow  Operation = "QueryRow"
+	OperationSelect    Operation = "Select"
+)
+
+// Call describes a single call of a Querier method as seen by interceptors.
+// Interceptors may rewrite the query and args before passing the call on.
+type Call struct {
+	Operation Operation
+	Query     string
+	// Args are the bind parameters of the query. For NamedExec, it contains the single struct or map argument.
+	Args []any
+	// Table is the table of the query builder the call was made by, empty for calls made directly on a Querier.
+	Table string
+
+	statement string       // kind of the statement of the query builder the call was made by, empty for raw SQL
+	querier   *baseQuerier // the querier the call was made on
+}
+
+// statementKind returns the kind of the statement of the call (select, insert, update, delete or other).
+// It is known for calls made by a query builder, e.g. for statements starting with a WITH clause,
+// and taken from the leading keyword of the query otherwise.
+func (c *Call) statementKind() string {
+	if c.statement != "" {
+		return c.statement
+	}
+
+	return statementKind(c.Query)
+}
+
+// CallResult describes the outcome of a Call.
+type CallResult struct {
+	// Value is returned to the caller: the Result of Exec and NamedExec, the *Rows of Query,
+	// the *sql.Row of QueryRow and the *Stmt of Prepare. It is nil for Get and Select, which scan into their destination.
+	Value any
+	// Duration is the time spent executing the call against the database, including retries.
+	Duration time.Duration
+	// RowsAffected is the number of rows affected by Exec and NamedExec, -1 if unknown or for other operations.
+	RowsAffected int64
+	// RowsReturned is the number of rows scanned by Get and Select, -1 for other operations.
+	RowsReturned int64
+	// Retries is the number of times the executor retried the call after an error.
+	Retries int
+	Err     error
+}
+
+// Invoker executes a Call, either by calling the next interceptor or the database.
+type Invoker func(ctx context.Context, call *Call) *CallResult
+
+// Interceptor is called for every call of a Querier method of a client or its transactions.
+// It has to call next to continue the call and returns the result of next, which it may inspect
+// or replace. An interceptor can rewrite a call by modifying it before calling next, or veto it by
+// returning a result with an error without calling next. As a *sql.Row can not be created with an error,
+// the row returned by a vetoed QueryRow reports the error of the interceptor from Scan and Err.
+//
+// Example:
+//
+//	func logSlowCalls(logger log.Logger) sqlc.Interceptor {
+//		return func(ctx context.Context, call *sqlc.Call, next sqlc.Invoker) *sqlc.CallResult {
+//			result := next(ctx, call)
+//			if result.Duration > time.Second {
+//				logger.Warn(ctx, "slow %s: %s", call.Operation, call.Query)
+//			}
+//
+//			return result
+//		}
+//	}
+//
+//	client = client.WithInterceptors(logSlowCalls(logger))
+type Interceptor func(ctx context.Context, call *Call, next Invoker) *CallResult
+
+type builderCtxKey struct{}
+
+// builderCall describes the query builder executing a call.
+type builderCall struct {
+	statement string // kind of the statement, e.g. statementSelect
+	table     string
+}
+
+// contextWithBuilder returns a copy of ctx telling the interceptors of querier the kind of statement and the table
+// of the query builder executing the call. An alias and quotes are removed from the table. Queriers not implemented
+// by this package have no interceptors and receive ctx unchanged.
+func contextWithBuilder(ctx context.Context, querier Querier, statement string, table string) context.Context {
+	switch querier.(type) {
+	case *client, *tx:
+	default:
+		return ctx
+	}
+
+	builder := builderCall{statement: statement}
+	if fields := strings.Fields(table); len(fields) > 0 {
+		builder.table = strings.Trim(fields[0], "`\"")
+	}
+
+	return context.WithValue(ctx, builderCtxKey{}, builder)
+}
+
+func builderFromContext(ctx context.Context) builderCall {
+	builder, _ := ctx.Value(builderCtxKey{}).(builderCall)
+
+	return builder
+}
+
+// chainInterceptors returns an Invoker calling the interceptors in order, the first interceptor being the outermost one.
+func chainInterceptors(interceptors []Interceptor, invoke Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, call *Call) *CallResult {
+			return interceptor(ctx, call, next)
+		}
+	}
+
+	return invoke
+}
+
+// callErr returns the error of the result of an operation without a value.
+func callErr(result *CallResult, operation Operation) error {
+	if result == nil {
+		return fmt.Errorf("interceptor returned no result for %s", operation)
+	}
+
+	return result.Err
+}
+
+// callValue returns the value of the result with the type expected by the caller of the operation.
+func callValue[T any](result *CallResult, operation Operation) (T, error) {
+	var value T
+
+	if result == nil {
+		return value, fmt.Errorf("interceptor returned no result for %s", operation)
+	}
+
+	if result.Err != nil {
+		return value, result.Err
+	}
+
+	value, ok := result.Value.(T)
+	if !ok {
+		return value, fmt.Errorf("interceptor returned a value of type %T instead of %T for %s", result.Value, value, operation)
+	}
+
+	return value, nil
+}
+
+// errorRow returns a row whose Scan and Err return err. database/sql offers no way to create a *sql.Row
+// with an error, so the row is queried from a database whose connections fail with err.
+func errorRow(err error) *sql.Row {
+	db := sql.OpenDB(errConnector{err: err})
+	defer db.Close()
+
+	return db.QueryRow("")
+}
+
+// errConnector is a driver.Connector failing every connection attempt with its error.
+type errConnector struct {
+	err error
+}
+
+func (c errConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, c.err
+}
+
+func (c errConnector) Driver() driver.Driver {
+	return c
+}
+
+func (c errConnector) Open(string) (driver.Conn, error) {
+	return nil, c.err
+}
@@ -2,6 +2,7 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"math"
@@ -68,26 +69,8 @@ func (p LifeCyclePurger) Purge(ctx context.Context) (err error) {
 		}
 	}()
 
-	rows, err := p.db.QueryContext(ctx, "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?;", p.settings.Uri.Database)
-	if err != nil {
-		return fmt.Errorf("failed to check tables of database: %w", err)
-	}
-
-	for rows.Next() {
-		var table string
-		if err = rows.Scan(&table); err != nil {
-			// on error, we will end the iteration and read the error afterwards with rows.Err()
-			break
-		}
-		tables = append(tables, table)
-	}
-
-	if err = rows.Close(); err != nil {
-		return fmt.Errorf("could not close rows: %w", err)
-	}
-
-	if err = rows.Err(); err != nil {
-		return fmt.Errorf("could not iterate over rows: %w", err)
+	if tables, err = p.listTables(ctx); err != nil {
+		return err
 	}
 
 	tables = funk.Filter(tables, func(s string) bool {
@@ -98,6 +81,10 @@ func (p LifeCyclePurger) Purge(ctx context.Context) (err error) {
 		return nil
 	}
 
+	if p.settings.Driver == DriverSqlite {
+		return p.purgeSqlite(ctx, tables)
+	}
+
 	chunks := funk.Chunk(tables, int(math.Ceil(float64(len(tables))/float64(runtime.NumCPU()))))
 
 	cfn := coffin.New()
@@ -128,3 +115,56 @@ func (p LifeCyclePurger) Purge(ctx context.Context) (err error) {
 
 	return nil
 }
+
+// listTables returns the names of all tables of the database.
+// SQLite has no information_schema, its tables are listed from sqlite_master instead.
+// This includes sqlite_sequence, which holds the AUTOINCREMENT counters, but no other internal tables.
+func (p LifeCyclePurger) listTables(ctx context.Context) (tables []string, err error) {
+	var rows *sql.Rows
+
+	if p.settings.Driver == DriverSqlite {
+		rows, err = p.db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND (name = 'sqlite_sequence' OR name NOT LIKE 'sqlite_%');")
+	} else {
+		rows, err = p.db.QueryContext(ctx, "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?;", p.settings.Uri.Database)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tables of database: %w", err)
+	}
+
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			// on error, we will end the iteration and read the error afterwards with rows.Err()
+			break
+		}
+		tables = append(tables, table)
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, fmt.Errorf("could not close rows: %w", err)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate over rows: %w", err)
+	}
+
+	return tables, nil
+}
+
+// purgeSqlite deletes all rows of the tables. SQLite has no TRUNCATE and allows only a single writer,
+// so the tables are purged one after another within a single statement instead of in parallel.
+// Foreign key checks are disabled for the connection first, as FOREIGN_KEY_CHECKS is MySQL only.
+// Deleting from sqlite_sequence resets the AUTOINCREMENT counters, like TRUNCATE does.
+func (p LifeCyclePurger) purgeSqlite(ctx context.Context, tables []string) error {
+	sqls := []string{"PRAGMA foreign_keys = OFF;"}
+	sqls = append(sqls, funk.Map(tables, func(table string) string {
+		return fmt.Sprintf("DELETE FROM %s;", quoteIdentifier(table, `"`))
+	})...)
+
+	if _, err := p.db.ExecContext(ctx, strings.Join(sqls, " ")); err != nil {
+		return fmt.Errorf("could not delete rows of tables: %w", err)
+	}
+
+	return nil
+}
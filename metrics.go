@@ -4,30 +4,57 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/funk"
 	"github.com/justtrackio/gosoline/pkg/metric"
 	"github.com/justtrackio/gosoline/pkg/uuid"
 )
 
 const (
 	metricNameDbConnectionCount = "DbConnectionCount"
+	metricNameDbTxAttempts      = "DbTxAttempts"
+	metricNameDbQueryCount      = "DbQueryCount"
+	metricNameDbQueryErrorCount = "DbQueryErrorCount"
+	metricNameDbQueryLatency    = "DbQueryLatency"
+	metricNameDbQueryRetryCount = "DbQueryRetryCount"
+	metricNameDbQueryRows       = "DbQueryRows"
+	metricNameDbStmtCacheCount  = "DbStmtCacheCount"
+
+	connectionRolePrimary = "primary"
+	connectionRoleReplica = "replica"
+
+	stmtCacheHit      = "hit"
+	stmtCacheMiss     = "miss"
+	stmtCacheEviction = "eviction"
+
+	statementSelect = "select"
+	statementInsert = "insert"
+	statementUpdate = "update"
+	statementDelete = "delete"
+	statementOther  = "other"
 )
 
 type metricDriver struct {
 	driver.Driver
 
 	metricWriter metric.Writer
+	name         string
+	role         string
 }
 
-func newMetricDriver(driver driver.Driver) string {
+func newMetricDriver(driver driver.Driver, name string, role string) string {
 	mw := metric.NewWriter()
 
 	id := uuid.New().NewV4()
 	md := &metricDriver{
 		Driver:       driver,
 		metricWriter: mw,
+		name:         name,
+		role:         role,
 	}
 
 	sql.Register(id, md)
@@ -36,20 +63,28 @@ func newMetricDriver(driver driver.Driver) string {
 }
 
 func (m *metricDriver) Open(dsn string) (driver.Conn, error) {
+	dimensions := map[string]string{
+		"Type": "new",
+		"Role": m.role,
+	}
+
+	// connections created without the name of a client, e.g. by NewConnectionWithInterfaces, have no Client dimension
+	if m.name != "" {
+		dimensions["Client"] = m.name
+	}
+
 	m.metricWriter.WriteOne(context.Background(), &metric.Datum{
 		Priority:   metric.PriorityHigh,
 		MetricName: metricNameDbConnectionCount,
-		Dimensions: map[string]string{
-			"Type": "new",
-		},
-		Unit:  metric.UnitCountAverage,
-		Value: 1.0,
+		Dimensions: dimensions,
+		Unit:       metric.UnitCountAverage,
+		Value:      1.0,
 	})
 
 	return m.Driver.Open(dsn)
 }
 
-func publishConnectionMetrics(conn *sqlx.DB) {
+func publishConnectionMetrics(conn *sqlx.DB, name string, role string) {
 	output := metric.NewWriter()
 
 	go func() {
@@ -61,7 +96,9 @@ func publishConnectionMetrics(conn *sqlx.DB) {
 					Priority:   metric.PriorityHigh,
 					MetricName: metricNameDbConnectionCount,
 					Dimensions: map[string]string{
-						"Type": "open",
+						"Type":   "open",
+						"Client": name,
+						"Role":   role,
 					},
 					Unit:  metric.UnitCountAverage,
 					Value: float64(stats.OpenConnections),
@@ -70,7 +107,9 @@ func publishConnectionMetrics(conn *sqlx.DB) {
 					Priority:   metric.PriorityHigh,
 					MetricName: metricNameDbConnectionCount,
 					Dimensions: map[string]string{
-						"Type": "inUse",
+						"Type":   "inUse",
+						"Client": name,
+						"Role":   role,
 					},
 					Unit:  metric.UnitCountAverage,
 					Value: float64(stats.InUse),
@@ -79,7 +118,9 @@ func publishConnectionMetrics(conn *sqlx.DB) {
 					Priority:   metric.PriorityHigh,
 					MetricName: metricNameDbConnectionCount,
 					Dimensions: map[string]string{
-						"Type": "idle",
+						"Type":   "idle",
+						"Client": name,
+						"Role":   role,
 					},
 					Unit:  metric.UnitCountAverage,
 					Value: float64(stats.Idle),
@@ -90,3 +131,121 @@ func publishConnectionMetrics(conn *sqlx.DB) {
 		}
 	}()
 }
+
+func publishTxAttemptsMetric(ctx context.Context, writer metric.Writer, name string, attempts int) {
+	writer.WriteOne(ctx, &metric.Datum{
+		Priority:   metric.PriorityHigh,
+		MetricName: metricNameDbTxAttempts,
+		Dimensions: metric.Dimensions{
+			"Client": name,
+		},
+		Unit:  metric.UnitCountAverage,
+		Value: float64(attempts),
+	})
+}
+
+// NewQueryMetricsInterceptor returns an interceptor publishing the latency, errors, retries and rows of every call
+// with the client name, the kind of statement (select, insert, update, delete or other) and, for calls made by a
+// query builder, the table as dimensions. Clients created from settings install it with their name already.
+//
+// Example:
+//
+//	client := sqlc.NewClientWithInterfaces(logger, connection, executor, qbConfig).
+//		WithInterceptors(sqlc.NewQueryMetricsInterceptor(metric.NewWriter(), "main"))
+func NewQueryMetricsInterceptor(writer metric.Writer, name string) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) *CallResult {
+		result := next(ctx, call)
+		if result != nil {
+			publishQueryMetrics(ctx, writer, name, call, result)
+		}
+
+		return result
+	}
+}
+
+func publishQueryMetrics(ctx context.Context, writer metric.Writer, name string, call *Call, result *CallResult) {
+	dimensions := metric.Dimensions{
+		"Client":    name,
+		"Operation": call.statementKind(),
+	}
+
+	if call.Table != "" {
+		dimensions["Table"] = call.Table
+	}
+
+	failed := 0.0
+	if result.Err != nil && !errors.Is(result.Err, sql.ErrNoRows) {
+		failed = 1.0
+	}
+
+	data := metric.Data{
+		&metric.Datum{
+			Priority:   metric.PriorityHigh,
+			MetricName: metricNameDbQueryCount,
+			Dimensions: dimensions,
+			Unit:       metric.UnitCount,
+			Value:      1.0,
+		},
+		&metric.Datum{
+			Priority:   metric.PriorityHigh,
+			MetricName: metricNameDbQueryErrorCount,
+			Dimensions: dimensions,
+			Unit:       metric.UnitCount,
+			Value:      failed,
+		},
+		&metric.Datum{
+			Priority:   metric.PriorityHigh,
+			MetricName: metricNameDbQueryLatency,
+			Dimensions: dimensions,
+			Unit:       metric.UnitMillisecondsAverage,
+			Value:      float64(result.Duration.Microseconds()) / 1000,
+		},
+		&metric.Datum{
+			Priority:   metric.PriorityHigh,
+			MetricName: metricNameDbQueryRetryCount,
+			Dimensions: dimensions,
+			Unit:       metric.UnitCount,
+			Value:      float64(result.Retries),
+		},
+	}
+
+	if result.RowsReturned >= 0 {
+		data = append(data, queryRowsDatum(dimensions, "returned", result.RowsReturned))
+	}
+
+	if result.RowsAffected >= 0 {
+		data = append(data, queryRowsDatum(dimensions, "affected", result.RowsAffected))
+	}
+
+	writer.Write(ctx, data)
+}
+
+func queryRowsDatum(dimensions metric.Dimensions, typ string, rows int64) *metric.Datum {
+	return &metric.Datum{
+		Priority:   metric.PriorityHigh,
+		MetricName: metricNameDbQueryRows,
+		Dimensions: funk.MergeMaps(dimensions, metric.Dimensions{"Type": typ}),
+		Unit:       metric.UnitCountAverage,
+		Value:      float64(rows),
+	}
+}
+
+// statementKind returns the kind of the statement by its leading keyword.
+// Statements starting with a WITH clause, DDL and all other statements are reported as "other".
+func statementKind(query string) string {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	keyword, _, _ := strings.Cut(query, " ")
+
+	switch strings.ToLower(keyword) {
+	case "select":
+		return statementSelect
+	case "insert", "replace":
+		return statementInsert
+	case "update":
+		return statementUpdate
+	case "delete":
+		return statementDelete
+	default:
+		return statementOther
+	}
+}
@@ -666,3 +666,65 @@ func (_c *Client_Select_Call) RunAndReturn(run func(ctx context.Context, dest an
 	_c.Call.Return(run)
 	return _c
 }
+
+// WithInterceptors provides a mock function for the type Client
+func (_mock *Client) WithInterceptors(interceptors ...sqlc.Interceptor) sqlc.Client {
+	var tmpRet mock.Arguments
+	if len(interceptors) > 0 {
+		tmpRet = _mock.Called(interceptors)
+	} else {
+		tmpRet = _mock.Called()
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithInterceptors")
+	}
+
+	var r0 sqlc.Client
+	if returnFunc, ok := ret.Get(0).(func(...sqlc.Interceptor) sqlc.Client); ok {
+		r0 = returnFunc(interceptors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(sqlc.Client)
+		}
+	}
+	return r0
+}
+
+// Client_WithInterceptors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithInterceptors'
+type Client_WithInterceptors_Call struct {
+	*mock.Call
+}
+
+// WithInterceptors is a helper method to define mock.On call
+//   - interceptors ...sqlc.Interceptor
+func (_e *Client_Expecter) WithInterceptors(interceptors ...interface{}) *Client_WithInterceptors_Call {
+	return &Client_WithInterceptors_Call{Call: _e.mock.On("WithInterceptors",
+		append([]interface{}{}, interceptors...)...)}
+}
+
+func (_c *Client_WithInterceptors_Call) Run(run func(interceptors ...sqlc.Interceptor)) *Client_WithInterceptors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []sqlc.Interceptor
+		var variadicArgs []sqlc.Interceptor
+		if len(args) > 0 {
+			variadicArgs = args[0].([]sqlc.Interceptor)
+		}
+		arg0 = variadicArgs
+		run(
+			arg0...,
+		)
+	})
+	return _c
+}
+
+func (_c *Client_WithInterceptors_Call) Return(client sqlc.Client) *Client_WithInterceptors_Call {
+	_c.Call.Return(client)
+	return _c
+}
+
+func (_c *Client_WithInterceptors_Call) RunAndReturn(run func(interceptors ...sqlc.Interceptor) sqlc.Client) *Client_WithInterceptors_Call {
+	_c.Call.Return(run)
+	return _c
+}
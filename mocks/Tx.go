@@ -462,6 +462,86 @@ func (_c *Tx_NamedExec_Call) RunAndReturn(run func(ctx context.Context, query st
 	return _c
 }
 
+// OnCommit provides a mock function for the type Tx
+func (_mock *Tx) OnCommit(fn func(ctx context.Context)) {
+	_mock.Called(fn)
+	return
+}
+
+// Tx_OnCommit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OnCommit'
+type Tx_OnCommit_Call struct {
+	*mock.Call
+}
+
+// OnCommit is a helper method to define mock.On call
+//   - fn func(ctx context.Context)
+func (_e *Tx_Expecter) OnCommit(fn interface{}) *Tx_OnCommit_Call {
+	return &Tx_OnCommit_Call{Call: _e.mock.On("OnCommit", fn)}
+}
+
+func (_c *Tx_OnCommit_Call) Run(run func(fn func(ctx context.Context))) *Tx_OnCommit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(ctx context.Context)
+		if args[0] != nil {
+			arg0 = args[0].(func(ctx context.Context))
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Tx_OnCommit_Call) Return() *Tx_OnCommit_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Tx_OnCommit_Call) RunAndReturn(run func(fn func(ctx context.Context))) *Tx_OnCommit_Call {
+	_c.Run(run)
+	return _c
+}
+
+// OnRollback provides a mock function for the type Tx
+func (_mock *Tx) OnRollback(fn func(ctx context.Context, err error)) {
+	_mock.Called(fn)
+	return
+}
+
+// Tx_OnRollback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OnRollback'
+type Tx_OnRollback_Call struct {
+	*mock.Call
+}
+
+// OnRollback is a helper method to define mock.On call
+//   - fn func(ctx context.Context, err error)
+func (_e *Tx_Expecter) OnRollback(fn interface{}) *Tx_OnRollback_Call {
+	return &Tx_OnRollback_Call{Call: _e.mock.On("OnRollback", fn)}
+}
+
+func (_c *Tx_OnRollback_Call) Run(run func(fn func(ctx context.Context, err error))) *Tx_OnRollback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(ctx context.Context, err error)
+		if args[0] != nil {
+			arg0 = args[0].(func(ctx context.Context, err error))
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Tx_OnRollback_Call) Return() *Tx_OnRollback_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Tx_OnRollback_Call) RunAndReturn(run func(fn func(ctx context.Context, err error))) *Tx_OnRollback_Call {
+	_c.Run(run)
+	return _c
+}
+
 // Prepare provides a mock function for the type Tx
 func (_mock *Tx) Prepare(ctx context.Context, query string) (*sqlc.Stmt, error) {
 	ret := _mock.Called(ctx, query)
@@ -687,6 +767,57 @@ func (_c *Tx_QueryRow_Call) RunAndReturn(run func(ctx context.Context, query str
 	return _c
 }
 
+// Release provides a mock function for the type Tx
+func (_mock *Tx) Release(name string) error {
+	ret := _mock.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Tx_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type Tx_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - name string
+func (_e *Tx_Expecter) Release(name interface{}) *Tx_Release_Call {
+	return &Tx_Release_Call{Call: _e.mock.On("Release", name)}
+}
+
+func (_c *Tx_Release_Call) Run(run func(name string)) *Tx_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Tx_Release_Call) Return(err error) *Tx_Release_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Tx_Release_Call) RunAndReturn(run func(name string) error) *Tx_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Rollback provides a mock function for the type Tx
 func (_mock *Tx) Rollback() error {
 	ret := _mock.Called()
@@ -731,6 +862,108 @@ func (_c *Tx_Rollback_Call) RunAndReturn(run func() error) *Tx_Rollback_Call {
 	return _c
 }
 
+// RollbackTo provides a mock function for the type Tx
+func (_mock *Tx) RollbackTo(name string) error {
+	ret := _mock.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackTo")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Tx_RollbackTo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RollbackTo'
+type Tx_RollbackTo_Call struct {
+	*mock.Call
+}
+
+// RollbackTo is a helper method to define mock.On call
+//   - name string
+func (_e *Tx_Expecter) RollbackTo(name interface{}) *Tx_RollbackTo_Call {
+	return &Tx_RollbackTo_Call{Call: _e.mock.On("RollbackTo", name)}
+}
+
+func (_c *Tx_RollbackTo_Call) Run(run func(name string)) *Tx_RollbackTo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Tx_RollbackTo_Call) Return(err error) *Tx_RollbackTo_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Tx_RollbackTo_Call) RunAndReturn(run func(name string) error) *Tx_RollbackTo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Savepoint provides a mock function for the type Tx
+func (_mock *Tx) Savepoint(name string) error {
+	ret := _mock.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Savepoint")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Tx_Savepoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Savepoint'
+type Tx_Savepoint_Call struct {
+	*mock.Call
+}
+
+// Savepoint is a helper method to define mock.On call
+//   - name string
+func (_e *Tx_Expecter) Savepoint(name interface{}) *Tx_Savepoint_Call {
+	return &Tx_Savepoint_Call{Call: _e.mock.On("Savepoint", name)}
+}
+
+func (_c *Tx_Savepoint_Call) Run(run func(name string)) *Tx_Savepoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Tx_Savepoint_Call) Return(err error) *Tx_Savepoint_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Tx_Savepoint_Call) RunAndReturn(run func(name string) error) *Tx_Savepoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Select provides a mock function for the type Tx
 func (_mock *Tx) Select(ctx context.Context, dest any, query string, args ...any) error {
 	var tmpRet mock.Arguments
@@ -861,3 +1094,54 @@ func (_c *Tx_Value_Call) RunAndReturn(run func(key any) any) *Tx_Value_Call {
 	_c.Call.Return(run)
 	return _c
 }
+
+// WithTx provides a mock function for the type Tx
+func (_mock *Tx) WithTx(fn func(cttx sqlc.Tx) error) error {
+	ret := _mock.Called(fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(func(cttx sqlc.Tx) error) error); ok {
+		r0 = returnFunc(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Tx_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type Tx_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - fn func(cttx sqlc.Tx) error
+func (_e *Tx_Expecter) WithTx(fn interface{}) *Tx_WithTx_Call {
+	return &Tx_WithTx_Call{Call: _e.mock.On("WithTx", fn)}
+}
+
+func (_c *Tx_WithTx_Call) Run(run func(fn func(cttx sqlc.Tx) error)) *Tx_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(cttx sqlc.Tx) error
+		if args[0] != nil {
+			arg0 = args[0].(func(cttx sqlc.Tx) error)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Tx_WithTx_Call) Return(err error) *Tx_WithTx_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Tx_WithTx_Call) RunAndReturn(run func(fn func(cttx sqlc.Tx) error) error) *Tx_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
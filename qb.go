@@ -47,7 +47,7 @@ func NewQueryBuilderWithConfig(client Querier, config *QueryBuilderConfig) *Quer
 //
 //	qb := &QueryBuilder{client: myClient}
 //	query := qb.From("users")  // Equivalent to: From("users").WithClient(myClient)
-func (q *QueryBuilder) From(table string) *SelectQueryBuilder {
+func (q *QueryBuilder) From(table any) *SelectQueryBuilder {
 	builder := From(table).WithClient(q.client)
 	if q.config != nil {
 		builder = builder.WithConfig(q.config)
@@ -49,6 +49,28 @@ type QueryBuilderConfig struct {
 	//   - "\"" for PostgreSQL, Oracle
 	//   - "[" for SQL Server (uses [] pairs)
 	IdentifierQuote string
+
+	// Dialect is the SQL dialect of the database the queries are built for.
+	// It is used to translate or reject constructs the database does not support, e.g. RETURNING on MySQL.
+	// Default: nil (queries are rendered as written, no dialect specific checks)
+	Dialect Dialect
+
+	// CursorKey is the secret key the cursors of Paginate are signed with, so they can not be forged or modified.
+	// Default: nil (Paginate is not available)
+	CursorKey []byte
+}
+
+// querierConfig returns the config of the queries built by the clients and transactions of this package,
+// or nil for other queriers.
+func querierConfig(querier Querier) *QueryBuilderConfig {
+	switch q := querier.(type) {
+	case *client:
+		return q.qbConfig
+	case *tx:
+		return q.qbConfig
+	default:
+		return nil
+	}
 }
 
 // DefaultConfig returns the default configuration.
@@ -187,6 +187,67 @@ func TestConfigPostgreSQLPlaceholders(t *testing.T) {
 		assert.Equal(t, []any{5, 1000, 20}, args)
 	})
 
+	t.Run("SELECT with JOIN and PostgreSQL placeholders", func(t *testing.T) {
+		query := sqlc.From("users").
+			WithConfig(config).
+			As("u").
+			Columns("u.id", sqlc.JsonExtract(sqlc.Col("u.meta"), "$.name").As("name")).
+			Join("orders o", "o.user_id = u.id AND o.status = ?", "paid").
+			LeftJoin("refunds r", sqlc.Col("r.amount").Gt(10)).
+			Where("u.age > ?", 18).
+			GroupBy("u.id").
+			Having("COUNT(*) > ?", 2).
+			Limit(5)
+
+		sql, args, err := query.ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `u`.`id`, JSON_EXTRACT(`u`.`meta`, $1) AS name FROM `users` AS u INNER JOIN `orders` AS o ON o.user_id = u.id AND o.status = $2 LEFT JOIN `refunds` AS r ON `r`.`amount` > $3 WHERE u.age > $4 GROUP BY `u`.`id` HAVING COUNT(*) > $5 LIMIT $6", sql)
+		assert.Equal(t, []any{"$.name", "paid", 10, 18, 2, 5}, args)
+	})
+
+	t.Run("SELECT with subqueries and PostgreSQL placeholders", func(t *testing.T) {
+		paid := sqlc.From("orders").
+			WithConfig(config).
+			Columns(sqlc.Col("*").Count()).
+			Where("orders.user_id = u.id AND orders.status = ?", "paid")
+		active := sqlc.From("users").
+			WithConfig(config).
+			Where("status = ?", "active").
+			Limit(100)
+
+		query := sqlc.From(active).
+			WithConfig(config).
+			As("u").
+			Columns("u.id", sqlc.SubQuery(paid).As("paid_orders")).
+			Where(sqlc.Col("u.id").In(sqlc.From("admins").WithConfig(config).Columns("user_id").Where("level > ?", 2))).
+			Where("u.age > ?", 18)
+
+		sql, args, err := query.ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `u`.`id`, (SELECT COUNT(*) FROM `orders` WHERE orders.user_id = u.id AND orders.status = $1) AS paid_orders"+
+			" FROM (SELECT * FROM `users` WHERE status = $2 LIMIT $3) AS u"+
+			" WHERE `u`.`id` IN (SELECT `user_id` FROM `admins` WHERE level > $4) AND u.age > $5", sql)
+		assert.Equal(t, []any{"paid", "active", 100, 2, 18}, args)
+	})
+
+	t.Run("UNION with PostgreSQL placeholders", func(t *testing.T) {
+		query := sqlc.From("customers").
+			WithConfig(config).
+			Columns("email").
+			Where("active = ?", true).
+			UnionAll(sqlc.From("leads").WithConfig(config).Columns("email").Where("source = ?", "web").Limit(50)).
+			OrderBy("email").
+			Limit(10)
+
+		sql, args, err := query.ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `email` FROM `customers` WHERE active = $1 UNION ALL (SELECT `email` FROM `leads` WHERE source = $2 LIMIT $3) ORDER BY `email` LIMIT $4", sql)
+		assert.Equal(t, []any{true, "web", 50, 10}, args)
+	})
+
 	t.Run("INSERT with PostgreSQL placeholders", func(t *testing.T) {
 		query := sqlc.Into("users").
 			WithConfig(config).
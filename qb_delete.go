@@ -34,13 +34,15 @@ import (
 //		Limit(1000).
 //		Exec(ctx)
 type DeleteQueryBuilder struct {
-	client       Querier
-	table        string
-	sqlerWhere   *SqlerWhere
-	sqlerOrderBy *SqlerOrderBy
-	limitValue   *int
-	config       *QueryBuilderConfig // Configuration for struct tags and placeholders
-	err          error
+	client         Querier
+	table          string
+	sqlerWith      *SqlerWith
+	sqlerWhere     *SqlerWhere
+	sqlerOrderBy   *SqlerOrderBy
+	sqlerReturning *SqlerReturning
+	limitValue     *int
+	config         *QueryBuilderConfig // Configuration for struct tags and placeholders
+	err            error
 }
 
 // Delete creates a new DeleteQueryBuilder for the specified table.
@@ -53,10 +55,12 @@ type DeleteQueryBuilder struct {
 func Delete(table string) *DeleteQueryBuilder {
 	cfg := DefaultConfig()
 	return &DeleteQueryBuilder{
-		table:        table,
-		sqlerWhere:   NewSqlerWhere().WithConfig(cfg),
-		sqlerOrderBy: NewSqlerOrderBy().WithConfig(cfg),
-		config:       cfg,
+		table:          table,
+		sqlerWith:      NewSqlerWith().WithConfig(cfg),
+		sqlerWhere:     NewSqlerWhere().WithConfig(cfg),
+		sqlerOrderBy:   NewSqlerOrderBy().WithConfig(cfg),
+		sqlerReturning: NewSqlerReturning().WithConfig(cfg),
+		config:         cfg,
 	}
 }
 
@@ -80,12 +84,14 @@ func (q *DeleteQueryBuilder) copyQuery() *DeleteQueryBuilder {
 	}
 
 	newQuery := &DeleteQueryBuilder{
-		client:       q.client,
-		table:        q.table,
-		sqlerWhere:   newSqlerWhere,
-		sqlerOrderBy: newSqlerOrderBy,
-		config:       q.config,
-		err:          q.err,
+		client:         q.client,
+		table:          q.table,
+		sqlerWith:      q.sqlerWith.copy(),
+		sqlerWhere:     newSqlerWhere,
+		sqlerOrderBy:   newSqlerOrderBy,
+		sqlerReturning: q.sqlerReturning.copy(),
+		config:         q.config,
+		err:            q.err,
 	}
 
 	if q.limitValue != nil {
@@ -121,8 +127,45 @@ func (q *DeleteQueryBuilder) WithClient(client Querier) *DeleteQueryBuilder {
 func (q *DeleteQueryBuilder) WithConfig(config *QueryBuilderConfig) *DeleteQueryBuilder {
 	newQuery := q.copyQuery()
 	newQuery.config = config
+	newQuery.sqlerWith.WithConfig(config)
 	newQuery.sqlerWhere.WithConfig(config)
 	newQuery.sqlerOrderBy.WithConfig(config)
+	newQuery.sqlerReturning.WithConfig(config)
+
+	return newQuery
+}
+
+// With adds a common table expression which is rendered in a WITH clause before the DELETE.
+// The optional columns are rendered as the column list of the CTE.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	Delete("sessions").
+//		With("inactive", From("users").Columns("id").Where("last_login < ?", cutoff)).
+//		Where("user_id IN (SELECT id FROM inactive)")
+//	// WITH `inactive` AS (SELECT `id` FROM `users` WHERE last_login < ?) DELETE FROM `sessions` WHERE user_id IN (SELECT id FROM inactive)
+func (q *DeleteQueryBuilder) With(name string, query *SelectQueryBuilder, columns ...string) *DeleteQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.With(name, query, columns...)
+
+	if newQuery.sqlerWith.err != nil {
+		newQuery.err = newQuery.sqlerWith.err
+	}
+
+	return newQuery
+}
+
+// WithRecursive adds a recursive common table expression which is rendered in a WITH RECURSIVE clause.
+// The query may reference the CTE by its name.
+// Returns a new query builder with the CTE added.
+func (q *DeleteQueryBuilder) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *DeleteQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.WithRecursive(name, query, columns...)
+
+	if newQuery.sqlerWith.err != nil {
+		newQuery.err = newQuery.sqlerWith.err
+	}
 
 	return newQuery
 }
@@ -199,6 +242,24 @@ func (q *DeleteQueryBuilder) Limit(limit int) *DeleteQueryBuilder {
 	return newQuery
 }
 
+// Returning adds a RETURNING clause, which makes the query return the given columns of the deleted rows.
+// Replaces any previously set RETURNING columns. Use ExecReturning() to execute the query and scan the rows.
+// RETURNING is supported by PostgreSQL, queries built for a MySQL client fail with ErrReturningNotSupported.
+// Returns a new query builder with the RETURNING clause set.
+//
+// Example:
+//
+//	Delete("sessions").
+//		Where("expires_at < ?", now).
+//		Returning("id", "user_id")
+//	// DELETE FROM "sessions" WHERE expires_at < $1 RETURNING "id", "user_id"
+func (q *DeleteQueryBuilder) Returning(cols ...string) *DeleteQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerReturning.Returning(cols...)
+
+	return newQuery
+}
+
 // ToSql generates the final SQL DELETE query string with positional parameters.
 // Returns the SQL string, parameters slice, and any error encountered during building.
 //
@@ -239,6 +300,19 @@ func (q *DeleteQueryBuilder) buildDeleteSql() (query string, params []any, err e
 
 	var sql strings.Builder
 
+	// WITH clause
+	var withSQL string
+	var withArgs []any
+	if withSQL, withArgs, err = q.sqlerWith.toSqlWithStartIndex(paramIndex); err != nil {
+		return "", nil, fmt.Errorf("could not build WITH clause: %w", err)
+	}
+	if withSQL != "" {
+		sql.WriteString(withSQL)
+		sql.WriteString(" ")
+		params = append(params, withArgs...)
+		paramIndex += len(withArgs)
+	}
+
 	// DELETE FROM clause
 	sql.WriteString("DELETE FROM ")
 	sql.WriteString(quoteIdentifier(q.table, q.config.IdentifierQuote))
@@ -262,16 +336,34 @@ func (q *DeleteQueryBuilder) buildDeleteSql() (query string, params []any, err e
 		return "", nil, fmt.Errorf("could not build ORDER BY clause: %w", err)
 	}
 	if orderSQL != "" {
+		if err = checkDialectSupport(q.config, "ORDER BY on DELETE", Dialect.SupportsUpdateOrderByLimit, "restrict the rows with a subquery in WHERE instead"); err != nil {
+			return "", nil, err
+		}
+
 		sql.WriteString(" ORDER BY ")
 		sql.WriteString(orderSQL)
 	}
 
 	// LIMIT clause
 	if q.limitValue != nil {
+		if err = checkDialectSupport(q.config, "LIMIT on DELETE", Dialect.SupportsUpdateOrderByLimit, "restrict the rows with a subquery in WHERE instead"); err != nil {
+			return "", nil, err
+		}
+
 		sql.WriteString(fmt.Sprintf(" LIMIT %s", q.config.PlaceholderFormat(paramIndex)))
 		params = append(params, *q.limitValue)
 	}
 
+	// RETURNING clause
+	var returningSQL string
+	if returningSQL, err = q.sqlerReturning.ToSql(); err != nil {
+		return "", nil, fmt.Errorf("could not build RETURNING clause: %w", err)
+	}
+	if returningSQL != "" {
+		sql.WriteString(" RETURNING ")
+		sql.WriteString(returningSQL)
+	}
+
 	return sql.String(), params, nil
 }
 
@@ -316,5 +408,44 @@ func (q *DeleteQueryBuilder) Exec(ctx context.Context) (Result, error) {
 		return nil, fmt.Errorf("could not build sql for execution: %w", err)
 	}
 
-	return q.client.Exec(ctx, sql, args...)
+	return q.client.Exec(contextWithBuilder(ctx, q.client, statementDelete, q.table), sql, args...)
+}
+
+// ExecReturning executes the delete query and scans the rows returned by the RETURNING clause into dest.
+// The destination should be a pointer to a slice of structs.
+// If no columns have been set via Returning(), the columns are derived from the struct tags of dest.
+// Returns ErrReturningNotSupported if the dialect of the client does not support RETURNING (e.g. MySQL).
+// Requires that a client has been set via WithClient().
+//
+// Example:
+//
+//	var deleted []Session
+//	err := Delete("sessions").
+//		WithClient(client).
+//		Where("expires_at < ?", now).
+//		ExecReturning(ctx, &deleted)
+func (q *DeleteQueryBuilder) ExecReturning(ctx context.Context, dest any) error {
+	if err := validatePointer(dest, "ExecReturning", true); err != nil {
+		return err
+	}
+
+	if q.client == nil {
+		return errors.New("no client set for query execution")
+	}
+
+	qb := q
+	if qb.sqlerReturning.IsEmpty() {
+		qb = qb.Returning(returningColumnsForType(dest, q.config)...)
+	}
+
+	var err error
+	var sql string
+	var args []any
+
+	if sql, args, err = qb.ToSql(); err != nil {
+		return fmt.Errorf("could not build sql for execution: %w", err)
+	}
+
+	// the statement is a write returning rows, so it must not be sent to a replica like other selects
+	return qb.client.Select(contextWithBuilder(ContextWithPrimary(ctx), qb.client, statementDelete, qb.table), dest, sql, args...)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/gosoline-project/sqlc"
 	mocks "github.com/gosoline-project/sqlc/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -520,3 +521,40 @@ func ExampleDeleteQueryBuilder_Where_expression() {
 	// DELETE FROM `users` WHERE (`status` = ? AND `created_at` < ?)
 	// params: [inactive 2020-01-01]
 }
+
+func TestDeleteReturning(t *testing.T) {
+	config := postgresConfig()
+
+	sql, params, err := sqlc.Delete("sessions").
+		WithConfig(config).
+		Where("expires_at < ?", "2020-01-01").
+		Returning("id", "user_id").
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "sessions" WHERE expires_at < $1 RETURNING "id", "user_id"`, sql)
+	assert.Equal(t, []any{"2020-01-01"}, params)
+}
+
+func TestDeleteExecReturning(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mocks.NewClient(t)
+
+	mockClient.EXPECT().
+		Select(mock.Anything, mock.Anything, "DELETE FROM `users` WHERE status = ? RETURNING `id`", mock.Anything).
+		RunAndReturn(func(ctx context.Context, dest any, query string, args ...any) error {
+			assert.Equal(t, []any{"banned"}, args)
+			*dest.(*[]TestUser) = []TestUser{{ID: 3}}
+
+			return nil
+		})
+
+	var users []TestUser
+	err := sqlc.Delete("users").
+		WithClient(mockClient).
+		Where("status = ?", "banned").
+		Returning("id").
+		ExecReturning(ctx, &users)
+
+	require.NoError(t, err)
+	assert.Equal(t, []TestUser{{ID: 3}}, users)
+}
@@ -13,12 +13,13 @@ import (
 	"github.com/justtrackio/gosoline/pkg/refl"
 )
 
-// Assignment represents a column assignment for ON DUPLICATE KEY UPDATE clause.
+// Assignment represents a column assignment for ON DUPLICATE KEY UPDATE and ON CONFLICT DO UPDATE clauses.
 // It can contain either a direct value or an expression (e.g., "VALUES(col)" or "col + 1").
 type Assignment struct {
-	Column string // Column name (will be quoted)
-	Value  any    // Value or expression
-	IsExpr bool   // If true, Value is treated as raw SQL expression (not parameterized)
+	Column   string // Column name (will be quoted)
+	Value    any    // Value or expression
+	IsExpr   bool   // If true, Value is treated as raw SQL expression (not parameterized)
+	excluded bool   // If true, the column is set to the value proposed for insertion
 }
 
 // Assign creates an Assignment with a parameterized value.
@@ -51,6 +52,20 @@ func AssignExpr(column string, expression string) Assignment {
 	}
 }
 
+// AssignExcluded creates an Assignment which sets the column to the value proposed for insertion.
+// It is rendered as EXCLUDED.col in ON CONFLICT clauses and as VALUES(col) in ON DUPLICATE KEY UPDATE clauses.
+//
+// Example:
+//
+//	AssignExcluded("name")   // "name" = EXCLUDED."name" (PostgreSQL)
+//	                         // `name` = VALUES(`name`)  (MySQL)
+func AssignExcluded(column string) Assignment {
+	return Assignment{
+		Column:   column,
+		excluded: true,
+	}
+}
+
 // InsertQueryBuilder provides a fluent API for building SQL INSERT queries.
 // It implements an immutable builder pattern - each method returns a new instance
 // rather than modifying the receiver. This allows for query reuse and prevents
@@ -76,19 +91,24 @@ func AssignExpr(column string, expression string) Assignment {
 //
 //	result, err := Into("users").Records(user1, user2, user3).Exec(ctx)
 type InsertQueryBuilder struct {
-	client      Querier
-	table       string
-	columns     []string
-	rows        [][]any
-	records     []any               // Store all records (for NamedExec or later extraction)
-	maps        []map[string]any    // Store maps for ValuesMaps
-	useNamed    bool                // Whether to use named parameters
-	mode        string              // "INSERT" or "REPLACE"
-	ignore      bool                // Whether to use IGNORE modifier
-	priority    string              // Priority modifier: "", "LOW_PRIORITY", "HIGH_PRIORITY", "DELAYED"
-	onDuplicate []Assignment        // ON DUPLICATE KEY UPDATE assignments
-	config      *QueryBuilderConfig // Configuration for struct tags and placeholders
-	err         error
+	client         Querier
+	table          string
+	columns        []string
+	rows           [][]any
+	records        []any               // Store all records (for NamedExec or later extraction)
+	maps           []map[string]any    // Store maps for ValuesMaps
+	useNamed       bool                // Whether to use named parameters
+	mode           string              // "INSERT" or "REPLACE"
+	ignore         bool                // Whether to use IGNORE modifier
+	priority       string              // Priority modifier: "", "LOW_PRIORITY", "HIGH_PRIORITY", "DELAYED"
+	onDuplicate    []Assignment        // ON DUPLICATE KEY UPDATE assignments
+	onConflict     *onConflictClause   // ON CONFLICT clause
+	upsert         *upsertClause       // dialect-neutral upsert, translated when building
+	selectQuery    *SelectQueryBuilder // source query for INSERT ... SELECT
+	sqlerWith      *SqlerWith          // CTEs rendered in front of the source query
+	sqlerReturning *SqlerReturning     // RETURNING columns
+	config         *QueryBuilderConfig // Configuration for struct tags and placeholders
+	err            error
 }
 
 // Into creates a new InsertQueryBuilder for the specified table.
@@ -99,12 +119,15 @@ type InsertQueryBuilder struct {
 //	Into("users")                   // INSERT INTO `users`
 //	Into("orders")                  // INSERT INTO `orders`
 func Into(table string) *InsertQueryBuilder {
+	cfg := DefaultConfig()
 	return &InsertQueryBuilder{
-		table:   table,
-		columns: []string{},
-		rows:    [][]any{},
-		mode:    "INSERT", // Default to INSERT mode
-		config:  DefaultConfig(),
+		table:          table,
+		columns:        []string{},
+		rows:           [][]any{},
+		mode:           "INSERT", // Default to INSERT mode
+		sqlerWith:      NewSqlerWith().WithConfig(cfg),
+		sqlerReturning: NewSqlerReturning().WithConfig(cfg),
+		config:         cfg,
 	}
 }
 
@@ -113,18 +136,23 @@ func Into(table string) *InsertQueryBuilder {
 // Each builder method creates a copy, modifies it, and returns the new copy.
 func (q *InsertQueryBuilder) copyQuery() *InsertQueryBuilder {
 	newQuery := &InsertQueryBuilder{
-		client:      q.client,
-		table:       q.table,
-		columns:     append([]string{}, q.columns...),
-		records:     append([]any{}, q.records...),
-		maps:        append([]map[string]any{}, q.maps...),
-		useNamed:    q.useNamed,
-		mode:        q.mode,
-		ignore:      q.ignore,
-		priority:    q.priority,
-		onDuplicate: append([]Assignment{}, q.onDuplicate...),
-		config:      q.config,
-		err:         q.err,
+		client:         q.client,
+		table:          q.table,
+		columns:        append([]string{}, q.columns...),
+		records:        append([]any{}, q.records...),
+		maps:           append([]map[string]any{}, q.maps...),
+		useNamed:       q.useNamed,
+		mode:           q.mode,
+		ignore:         q.ignore,
+		priority:       q.priority,
+		onDuplicate:    append([]Assignment{}, q.onDuplicate...),
+		onConflict:     q.onConflict.copy(),
+		upsert:         q.upsert,
+		selectQuery:    q.selectQuery,
+		sqlerWith:      q.sqlerWith.copy(),
+		sqlerReturning: q.sqlerReturning.copy(),
+		config:         q.config,
+		err:            q.err,
 	}
 
 	// Deep copy rows
@@ -161,6 +189,73 @@ func (q *InsertQueryBuilder) WithClient(client Querier) *InsertQueryBuilder {
 func (q *InsertQueryBuilder) WithConfig(config *QueryBuilderConfig) *InsertQueryBuilder {
 	newQuery := q.copyQuery()
 	newQuery.config = config
+	newQuery.sqlerWith.WithConfig(config)
+	newQuery.sqlerReturning.WithConfig(config)
+
+	if newQuery.onConflict != nil {
+		newQuery.onConflict.where.WithConfig(config)
+	}
+
+	return newQuery
+}
+
+// FromSelect uses the given query as the source of the inserted rows (INSERT ... SELECT).
+// Columns() is optional and maps the selected columns to the columns of the target table.
+// It can not be combined with Values, ValuesRows, ValuesMaps or Records.
+// Returns a new query builder with the source query set.
+//
+// Example:
+//
+//	Into("archived_orders").
+//		Columns("id", "total").
+//		FromSelect(From("orders").Columns("id", "total").Where("created_at < ?", cutoff))
+//	// INSERT INTO `archived_orders` (`id`, `total`) SELECT `id`, `total` FROM `orders` WHERE created_at < ?
+func (q *InsertQueryBuilder) FromSelect(query *SelectQueryBuilder) *InsertQueryBuilder {
+	newQuery := q.copyQuery()
+
+	if query == nil {
+		newQuery.err = errors.New("invalid FromSelect argument: query must not be nil")
+
+		return newQuery
+	}
+
+	newQuery.selectQuery = query
+
+	return newQuery
+}
+
+// With adds a common table expression for an INSERT ... SELECT query.
+// The WITH clause is rendered directly in front of the SELECT, which is supported by MySQL 8 and PostgreSQL.
+// The optional columns are rendered as the column list of the CTE.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	Into("daily_totals").
+//		With("paid", From("orders").Where("status = ?", "paid")).
+//		FromSelect(From("paid").Columns("day", Col("total").Sum()).GroupBy("day"))
+//	// INSERT INTO `daily_totals` WITH `paid` AS (SELECT * FROM `orders` WHERE status = ?) SELECT `day`, SUM(`total`) FROM `paid` GROUP BY `day`
+func (q *InsertQueryBuilder) With(name string, query *SelectQueryBuilder, columns ...string) *InsertQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.With(name, query, columns...)
+
+	if newQuery.sqlerWith.err != nil {
+		newQuery.err = newQuery.sqlerWith.err
+	}
+
+	return newQuery
+}
+
+// WithRecursive adds a recursive common table expression for an INSERT ... SELECT query.
+// The query may reference the CTE by its name.
+// Returns a new query builder with the CTE added.
+func (q *InsertQueryBuilder) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *InsertQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.WithRecursive(name, query, columns...)
+
+	if newQuery.sqlerWith.err != nil {
+		newQuery.err = newQuery.sqlerWith.err
+	}
 
 	return newQuery
 }
@@ -282,6 +377,25 @@ func (q *InsertQueryBuilder) OnDuplicateKeyUpdate(assignments ...Assignment) *In
 	return newQuery
 }
 
+// Returning adds a RETURNING clause, which makes the query return the given columns of the inserted rows.
+// Replaces any previously set RETURNING columns. Use ExecReturning() to execute the query and scan the rows.
+// RETURNING is supported by PostgreSQL, queries built for a MySQL client fail with ErrReturningNotSupported.
+// Returns a new query builder with the RETURNING clause set.
+//
+// Example:
+//
+//	Into("users").
+//		Columns("name", "email").
+//		Values("John", "john@example.com").
+//		Returning("id", "created_at")
+//	// INSERT INTO "users" ("name", "email") VALUES ($1, $2) RETURNING "id", "created_at"
+func (q *InsertQueryBuilder) Returning(cols ...string) *InsertQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerReturning.Returning(cols...)
+
+	return newQuery
+}
+
 // Columns sets the column list for the insert query.
 // This method replaces any previously set columns.
 // Column names will be automatically quoted in the generated SQL.
@@ -546,6 +660,18 @@ func (q *InsertQueryBuilder) ToSql() (query string, params []any, err error) {
 		return "", nil, errors.New("table name is required")
 	}
 
+	if q.selectQuery != nil {
+		if len(q.rows) > 0 || len(q.records) > 0 || len(q.maps) > 0 {
+			return "", nil, errors.New("FromSelect can not be combined with Values, ValuesRows, ValuesMaps or Records")
+		}
+
+		return q.buildInsertSelectSql()
+	}
+
+	if !q.sqlerWith.IsEmpty() {
+		return "", nil, errors.New("a WITH clause on INSERT requires FromSelect")
+	}
+
 	if len(q.columns) == 0 {
 		return "", nil, errors.New("columns are required")
 	}
@@ -661,14 +787,12 @@ func (q *InsertQueryBuilder) buildNamedSqlForMaps(sql *strings.Builder) (query s
 	sql.WriteString(strings.Join(namedParams, ", "))
 	sql.WriteString(")")
 
-	// ON DUPLICATE KEY UPDATE clause for named params
-	if len(q.onDuplicate) > 0 {
-		duplicateClause, err := q.buildOnDuplicateClauseNamed()
-		if err != nil {
-			return "", nil, err
-		}
-		sql.WriteString(duplicateClause)
+	// ON DUPLICATE KEY UPDATE or ON CONFLICT clause for named params
+	conflictClause, _, err := q.buildConflictClause(0, true)
+	if err != nil {
+		return "", nil, err
 	}
+	sql.WriteString(conflictClause)
 
 	// Convert maps to []any for return
 	params = make([]any, len(q.maps))
@@ -676,6 +800,11 @@ func (q *InsertQueryBuilder) buildNamedSqlForMaps(sql *strings.Builder) (query s
 		params[i] = m
 	}
 
+	// RETURNING clause
+	if err = q.writeReturningClause(sql); err != nil {
+		return "", nil, err
+	}
+
 	return sql.String(), params, nil
 }
 
@@ -690,42 +819,20 @@ func (q *InsertQueryBuilder) buildNamedSqlForRecords(sql *strings.Builder) (quer
 	sql.WriteString(strings.Join(namedParams, ", "))
 	sql.WriteString(")")
 
-	// ON DUPLICATE KEY UPDATE clause for named params
-	if len(q.onDuplicate) > 0 {
-		duplicateClause, err := q.buildOnDuplicateClauseNamed()
-		if err != nil {
-			return "", nil, err
-		}
-		sql.WriteString(duplicateClause)
+	// ON DUPLICATE KEY UPDATE or ON CONFLICT clause for named params
+	conflictClause, _, err := q.buildConflictClause(0, true)
+	if err != nil {
+		return "", nil, err
 	}
+	sql.WriteString(conflictClause)
 
 	// Return the untouched records (what NamedExec expects)
-	return sql.String(), q.records, nil
-}
-
-// buildOnDuplicateClauseNamed builds the ON DUPLICATE KEY UPDATE clause for named parameters.
-// For named parameters, expressions remain as-is, and values use named placeholders.
-func (q *InsertQueryBuilder) buildOnDuplicateClauseNamed() (string, error) {
-	if len(q.onDuplicate) == 0 {
-		return "", nil
-	}
-
-	var parts []string
-
-	for _, assignment := range q.onDuplicate {
-		quotedCol := quoteIdentifier(assignment.Column, q.config.IdentifierQuote)
-		if assignment.IsExpr {
-			// Expression - insert directly without parameterization
-			parts = append(parts, fmt.Sprintf("%s = %v", quotedCol, assignment.Value))
-		} else {
-			// Value - use named placeholder (same as column name)
-			parts = append(parts, fmt.Sprintf("%s = :%s", quotedCol, assignment.Column))
-		}
+	// RETURNING clause
+	if err = q.writeReturningClause(sql); err != nil {
+		return "", nil, err
 	}
 
-	clause := " ON DUPLICATE KEY UPDATE " + strings.Join(parts, ", ")
-
-	return clause, nil
+	return sql.String(), q.records, nil
 }
 
 // Exec executes the insert query using the attached client.
@@ -789,10 +896,10 @@ func (q *InsertQueryBuilder) Exec(ctx context.Context) (Result, error) {
 
 		// NamedExec accepts both single item and slice of items
 		if len(records) == 1 {
-			return q.client.NamedExec(ctx, sql, records[0])
+			return q.client.NamedExec(contextWithBuilder(ctx, q.client, statementInsert, q.table), sql, records[0])
 		}
 
-		return q.client.NamedExec(ctx, sql, records)
+		return q.client.NamedExec(contextWithBuilder(ctx, q.client, statementInsert, q.table), sql, records)
 	}
 
 	// For value-based inserts, use ToSql to extract values and use Exec
@@ -800,7 +907,48 @@ func (q *InsertQueryBuilder) Exec(ctx context.Context) (Result, error) {
 		return nil, fmt.Errorf("could not build sql for execution: %w", err)
 	}
 
-	return q.client.Exec(ctx, sql, args...)
+	return q.client.Exec(contextWithBuilder(ctx, q.client, statementInsert, q.table), sql, args...)
+}
+
+// ExecReturning executes the insert query and scans the rows returned by the RETURNING clause into dest.
+// The destination should be a pointer to a slice of structs.
+// If no columns have been set via Returning(), the columns are derived from the struct tags of dest.
+// Records and maps are always inserted using positional parameters, as named queries can not return rows.
+// Returns ErrReturningNotSupported if the dialect of the client does not support RETURNING (e.g. MySQL).
+// Requires that a client has been set via WithClient().
+//
+// Example:
+//
+//	var inserted []User
+//	err := Into("users").
+//		WithClient(client).
+//		Records(user1, user2).
+//		Returning("id", "name", "created_at").
+//		ExecReturning(ctx, &inserted)
+func (q *InsertQueryBuilder) ExecReturning(ctx context.Context, dest any) error {
+	if err := validatePointer(dest, "ExecReturning", true); err != nil {
+		return err
+	}
+
+	if q.client == nil {
+		return errors.New("no client set for query execution")
+	}
+
+	qb := q
+	if qb.sqlerReturning.IsEmpty() {
+		qb = qb.Returning(returningColumnsForType(dest, q.config)...)
+	}
+
+	var err error
+	var sql string
+	var args []any
+
+	if sql, args, err = qb.ToSql(); err != nil {
+		return fmt.Errorf("could not build sql for execution: %w", err)
+	}
+
+	// the statement is a write returning rows, so it must not be sent to a replica like other selects
+	return qb.client.Select(contextWithBuilder(ContextWithPrimary(ctx), qb.client, statementInsert, qb.table), dest, sql, args...)
 }
 
 // extractValuesFromStruct extracts field values from a struct in the order specified by tags.
@@ -933,19 +1081,99 @@ func (q *InsertQueryBuilder) buildInsertSql() (query string, params []any, err e
 
 	sql.WriteString(strings.Join(valueClauses, ", "))
 
-	// ON DUPLICATE KEY UPDATE clause
-	if len(q.onDuplicate) > 0 {
-		duplicateClause, duplicateParams, err := q.buildOnDuplicateClause(paramIndex)
-		if err != nil {
-			return "", nil, err
-		}
-		sql.WriteString(duplicateClause)
-		params = append(params, duplicateParams...)
+	// ON DUPLICATE KEY UPDATE or ON CONFLICT clause
+	conflictClause, conflictParams, err := q.buildConflictClause(paramIndex, false)
+	if err != nil {
+		return "", nil, err
+	}
+	sql.WriteString(conflictClause)
+	params = append(params, conflictParams...)
+
+	// RETURNING clause
+	if err = q.writeReturningClause(&sql); err != nil {
+		return "", nil, err
 	}
 
 	return sql.String(), params, nil
 }
 
+// buildInsertSelectSql builds the final INSERT ... SELECT SQL query string with positional parameters.
+func (q *InsertQueryBuilder) buildInsertSelectSql() (query string, params []any, err error) {
+	params = []any{}
+	paramIndex := 0 // Track parameter index for numbered placeholders (0-based)
+
+	var sql strings.Builder
+
+	// Build INSERT prefix with modifiers
+	prefix, err := q.buildInsertPrefix()
+	if err != nil {
+		return "", nil, err
+	}
+	sql.WriteString(prefix)
+
+	// Optional columns clause
+	if len(q.columns) > 0 {
+		sql.WriteString(" (")
+		quotedColumns := funk.Map(q.columns, func(col string) string {
+			return quoteIdentifier(col, q.config.IdentifierQuote)
+		})
+		sql.WriteString(strings.Join(quotedColumns, ", "))
+		sql.WriteString(")")
+	}
+
+	// WITH clause
+	withSQL, withArgs, err := q.sqlerWith.toSqlWithStartIndex(paramIndex)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not build WITH clause: %w", err)
+	}
+	if withSQL != "" {
+		sql.WriteString(" ")
+		sql.WriteString(withSQL)
+		params = append(params, withArgs...)
+		paramIndex += len(withArgs)
+	}
+
+	// SELECT clause
+	selectSQL, selectArgs, err := SubQuery(q.selectQuery).subqueryToSql(q.config)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not build SELECT clause: %w", err)
+	}
+	sql.WriteString(" ")
+	sql.WriteString(numberPlaceholders(selectSQL, len(selectArgs), paramIndex, q.config))
+	params = append(params, selectArgs...)
+	paramIndex += len(selectArgs)
+
+	// ON DUPLICATE KEY UPDATE or ON CONFLICT clause
+	conflictClause, conflictParams, err := q.buildConflictClause(paramIndex, false)
+	if err != nil {
+		return "", nil, err
+	}
+	sql.WriteString(conflictClause)
+	params = append(params, conflictParams...)
+
+	// RETURNING clause
+	if err = q.writeReturningClause(&sql); err != nil {
+		return "", nil, err
+	}
+
+	return sql.String(), params, nil
+}
+
+// writeReturningClause appends the RETURNING clause to the query, if any columns have been set.
+func (q *InsertQueryBuilder) writeReturningClause(sql *strings.Builder) error {
+	returningSQL, err := q.sqlerReturning.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not build RETURNING clause: %w", err)
+	}
+
+	if returningSQL != "" {
+		sql.WriteString(" RETURNING ")
+		sql.WriteString(returningSQL)
+	}
+
+	return nil
+}
+
 // buildInsertPrefix builds the INSERT/REPLACE prefix with modifiers.
 // Returns the prefix string like "INSERT", "INSERT IGNORE", "INSERT LOW_PRIORITY", etc.
 func (q *InsertQueryBuilder) buildInsertPrefix() (string, error) {
@@ -954,16 +1182,35 @@ func (q *InsertQueryBuilder) buildInsertPrefix() (string, error) {
 		return "", errors.New("REPLACE cannot be used with ON DUPLICATE KEY UPDATE")
 	}
 
+	// Validate: REPLACE cannot be used with ON CONFLICT or Upsert
+	if q.mode == "REPLACE" && (q.onConflict != nil || q.upsert != nil) {
+		return "", errors.New("REPLACE cannot be used with ON CONFLICT or Upsert")
+	}
+
+	if q.mode == "REPLACE" {
+		if err := checkDialectSupport(q.config, "REPLACE", Dialect.SupportsReplace, "use OnConflict().DoUpdate() or Upsert() instead"); err != nil {
+			return "", err
+		}
+	}
+
 	var parts []string
 	parts = append(parts, q.mode)
 
 	// Add priority modifier if set
 	if q.priority != "" {
+		if err := checkDialectSupport(q.config, q.priority, Dialect.SupportsInsertPriority, ""); err != nil {
+			return "", err
+		}
+
 		parts = append(parts, q.priority)
 	}
 
-	// Add IGNORE modifier if set
-	if q.ignore {
+	// Add IGNORE modifier if set, dialects without IGNORE render ON CONFLICT DO NOTHING instead
+	if q.ignore && !q.ignoreAsOnConflict() {
+		if err := checkDialectSupport(q.config, "IGNORE", Dialect.SupportsInsertIgnore, ""); err != nil {
+			return "", err
+		}
+
 		parts = append(parts, "IGNORE")
 	}
 
@@ -973,31 +1220,3 @@ func (q *InsertQueryBuilder) buildInsertPrefix() (string, error) {
 
 	return strings.Join(parts, " "), nil
 }
-
-// buildOnDuplicateClause builds the ON DUPLICATE KEY UPDATE clause.
-// Returns the clause string and any additional parameters.
-func (q *InsertQueryBuilder) buildOnDuplicateClause(paramIndex int) (clause string, params []any, err error) {
-	if len(q.onDuplicate) == 0 {
-		return "", nil, nil
-	}
-
-	var parts []string
-	params = []any{}
-
-	for _, assignment := range q.onDuplicate {
-		quotedCol := quoteIdentifier(assignment.Column, q.config.IdentifierQuote)
-		if assignment.IsExpr {
-			// Expression - insert directly without parameterization
-			parts = append(parts, fmt.Sprintf("%s = %v", quotedCol, assignment.Value))
-		} else {
-			// Value - use placeholder
-			parts = append(parts, fmt.Sprintf("%s = %s", quotedCol, q.config.PlaceholderFormat(paramIndex)))
-			params = append(params, assignment.Value)
-			paramIndex++
-		}
-	}
-
-	clause = " ON DUPLICATE KEY UPDATE " + strings.Join(parts, ", ")
-
-	return clause, params, nil
-}
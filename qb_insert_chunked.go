@@ -0,0 +1,206 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const (
+	// maxInsertPlaceholders is the maximum number of bind parameters of a single statement on MySQL and PostgreSQL.
+	maxInsertPlaceholders = 65535
+	// maxSqlitePlaceholders is the default maximum number of bind parameters of a single statement on SQLite.
+	maxSqlitePlaceholders = 32766
+)
+
+// maxPlaceholders returns the maximum number of bind parameters of a single statement for the dialect of the config.
+func maxPlaceholders(config *QueryBuilderConfig) int {
+	if dialect := dialectOf(config); dialect != nil && dialect.Name() == DialectSqlite {
+		return maxSqlitePlaceholders
+	}
+
+	return maxInsertPlaceholders
+}
+
+// chunkedResult combines the results of the statements of a chunked insert.
+type chunkedResult struct {
+	results []Result
+}
+
+// LastInsertId returns the id of the first chunk, which MySQL reports as the id of the first inserted row.
+func (r *chunkedResult) LastInsertId() (int64, error) {
+	if len(r.results) == 0 {
+		return 0, nil
+	}
+
+	return r.results[0].LastInsertId()
+}
+
+// RowsAffected returns the sum of the rows affected by all chunks.
+func (r *chunkedResult) RowsAffected() (int64, error) {
+	var total int64
+
+	for i, result := range r.results {
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("could not get rows affected of chunk %d: %w", i, err)
+		}
+
+		total += rowsAffected
+	}
+
+	return total, nil
+}
+
+// ExecChunked executes the insert query as a series of statements of at most chunkSize rows each,
+// so large batches of Values, ValuesRows, ValuesMaps or Records stay below the limit of 65535 bind
+// parameters of MySQL and PostgreSQL (32766 on SQLite) and below max_allowed_packet. If chunkSize
+// is zero or negative, the chunks are sized to use as many bind parameters per statement as allowed.
+//
+// The returned Result sums RowsAffected across all chunks, LastInsertId is the one of the first chunk.
+// The chunks are executed independently: if a chunk fails, the previous chunks stay inserted unless the
+// query runs inside a transaction. Use ExecChunkedTx to insert all chunks or none of them.
+//
+// Example:
+//
+//	result, err := Into("events").
+//		WithClient(client).
+//		Records(events). // 100k events
+//		ExecChunked(ctx, 1000)
+//	// INSERT INTO `events` (`id`, `name`) VALUES (?, ?), (?, ?), ... (100 statements of 1000 rows)
+func (q *InsertQueryBuilder) ExecChunked(ctx context.Context, chunkSize int) (Result, error) {
+	if q.client == nil {
+		return nil, errors.New("no client set for query execution")
+	}
+
+	chunks, err := q.chunks(chunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &chunkedResult{
+		results: make([]Result, 0, len(chunks)),
+	}
+
+	for i, chunk := range chunks {
+		var chunkResult Result
+		if chunkResult, err = chunk.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("could not execute chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+
+		result.results = append(result.results, chunkResult)
+	}
+
+	return result, nil
+}
+
+// ExecChunkedTx executes the insert query in chunks like ExecChunked, but inside a single transaction,
+// so either all chunks are inserted or none of them. If the client of the query is a transaction already,
+// the chunks are inserted within a savepoint of it. The options are passed to Client.WithTx.
+//
+// Example:
+//
+//	result, err := Into("events").
+//		WithClient(client).
+//		Records(events).
+//		ExecChunkedTx(ctx, 0) // chunks as large as the bind parameter limit allows
+func (q *InsertQueryBuilder) ExecChunkedTx(ctx context.Context, chunkSize int, ops ...*sql.TxOptions) (Result, error) {
+	var result Result
+
+	exec := func(cttx Tx) (err error) {
+		result, err = q.WithClient(cttx).ExecChunked(ctx, chunkSize)
+
+		return err
+	}
+
+	var err error
+	switch querier := q.client.(type) {
+	case nil:
+		return nil, errors.New("no client set for query execution")
+	case Client:
+		err = querier.WithTx(ctx, exec, ops...)
+	case Tx:
+		err = querier.WithTx(exec)
+	default:
+		return nil, fmt.Errorf("ExecChunkedTx requires a Client or Tx to start a transaction, got %T", q.client)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// chunks splits the rows, maps and records of the query into queries of at most chunkSize rows.
+func (q *InsertQueryBuilder) chunks(chunkSize int) ([]*InsertQueryBuilder, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	if q.selectQuery != nil {
+		return nil, errors.New("an INSERT ... SELECT can not be executed in chunks")
+	}
+
+	if !q.sqlerReturning.IsEmpty() {
+		return nil, errors.New("an INSERT with a RETURNING clause can not be executed in chunks, use ExecReturning instead")
+	}
+
+	total := len(q.rows) + len(q.maps) + len(q.records)
+	if total == 0 {
+		return nil, errors.New("at least one row of values is required")
+	}
+
+	if chunkSize <= 0 {
+		var err error
+		if chunkSize, err = q.maxChunkSize(); err != nil {
+			return nil, err
+		}
+	}
+
+	chunks := make([]*InsertQueryBuilder, 0, (total+chunkSize-1)/chunkSize)
+	for start := 0; start < total; start += chunkSize {
+		end := min(start+chunkSize, total)
+
+		// rows, maps and records are numbered in the order ToSql renders them
+		chunk := q.copyQuery()
+		chunk.rows = chunkWindow(chunk.rows, start, end, 0)
+		chunk.maps = chunkWindow(chunk.maps, start, end, len(q.rows))
+		chunk.records = chunkWindow(chunk.records, start, end, len(q.rows)+len(q.maps))
+
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
+
+// maxChunkSize returns the number of rows fitting into a statement without exceeding the bind parameter limit.
+// The parameters of the statement not depending on the rows, e.g. of the ON DUPLICATE KEY UPDATE clause,
+// are taken from the statement of a single row.
+func (q *InsertQueryBuilder) maxChunkSize() (int, error) {
+	single := q.copyQuery()
+	single.rows = chunkWindow(single.rows, 0, 1, 0)
+	single.maps = chunkWindow(single.maps, 0, 1, len(q.rows))
+	single.records = chunkWindow(single.records, 0, 1, len(q.rows)+len(q.maps))
+
+	_, params, err := single.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("could not build sql to size chunks: %w", err)
+	}
+
+	perRow := len(q.columns)
+	if perRow == 0 {
+		return 0, errors.New("columns are required")
+	}
+
+	return max((maxPlaceholders(q.config)-(len(params)-perRow))/perRow, 1), nil
+}
+
+// chunkWindow returns the items in the window [start, end) of all rows, where the first item is at offset.
+func chunkWindow[T any](items []T, start int, end int, offset int) []T {
+	from := min(max(start-offset, 0), len(items))
+	to := min(max(end-offset, 0), len(items))
+
+	return items[from:to]
+}
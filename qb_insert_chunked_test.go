@@ -0,0 +1,156 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gosoline-project/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertExecChunked(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`id`, `name`) VALUES (?, ?), (?, ?)")).
+		WithArgs(1, "John", 2, "Jane").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`id`, `name`) VALUES (?, ?), (?, ?)")).
+		WithArgs(3, "Jim", 4, "Kim").
+		WillReturnResult(sqlmock.NewResult(3, 2))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`id`, `name`) VALUES (?, ?)")).
+		WithArgs(5, "Tim").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	result, err := client.Q().Into("users").
+		Columns("id", "name").
+		ValuesRows([]any{1, "John"}, []any{2, "Jane"}, []any{3, "Jim"}, []any{4, "Kim"}, []any{5, "Tim"}).
+		ExecChunked(context.Background(), 2)
+	require.NoError(t, err)
+
+	rowsAffected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), rowsAffected)
+
+	lastInsertId, err := result.LastInsertId()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), lastInsertId)
+}
+
+func TestInsertExecChunked_PlaceholderLimit(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+
+	rows := make([][]any, 65536)
+	for i := range rows {
+		rows[i] = []any{i}
+	}
+
+	// one parameter is taken by the ON DUPLICATE KEY UPDATE clause, leaving 65534 for the rows
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `counters` (`id`) VALUES (?), (?)")).
+		WillReturnResult(sqlmock.NewResult(0, 65534))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `counters` (`id`) VALUES (?), (?) ON DUPLICATE KEY UPDATE `hits` = ?")).
+		WithArgs(65534, 65535, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	result, err := client.Q().Into("counters").
+		Columns("id").
+		ValuesRows(rows...).
+		OnDuplicateKeyUpdate(sqlc.Assign("hits", 1)).
+		ExecChunked(context.Background(), 0)
+	require.NoError(t, err)
+
+	rowsAffected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(65536), rowsAffected)
+}
+
+func TestInsertExecChunked_SqlitePlaceholderLimit(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+	sqlite := &sqlc.QueryBuilderConfig{StructTag: "db", Placeholder: "?", IdentifierQuote: "`", Dialect: sqlc.NewSqliteDialect()}
+
+	rows := make([][]any, 32767)
+	for i := range rows {
+		rows[i] = []any{i}
+	}
+
+	// SQLite allows at most 32766 bind parameters per statement
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `counters` (`id`) VALUES (?), (?)")).
+		WillReturnResult(sqlmock.NewResult(0, 32766))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `counters` (`id`) VALUES (?)")).
+		WithArgs(32766).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := sqlc.Into("counters").
+		WithConfig(sqlite).
+		WithClient(client).
+		Columns("id").
+		ValuesRows(rows...).
+		ExecChunked(context.Background(), 0)
+	require.NoError(t, err)
+
+	rowsAffected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(32767), rowsAffected)
+}
+
+func TestInsertExecChunkedTxG(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+	users := []TestUser{
+		{ID: 1, Name: "John", Email: "john@example.com"},
+		{ID: 2, Name: "Jane", Email: "jane@example.com"},
+		{ID: 3, Name: "Jim", Email: "jim@example.com"},
+	}
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`id`, `name`, `email`) VALUES")).
+		WithArgs(1, "John", "john@example.com", 2, "Jane", "jane@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`id`, `name`, `email`) VALUES")).
+		WithArgs(3, "Jim", "jim@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	result, err := sqlc.IntoG[TestUser]("users").WithClient(client).Records(users).ExecChunkedTx(context.Background(), 2)
+	require.NoError(t, err)
+
+	rowsAffected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), rowsAffected)
+}
+
+func TestInsertExecChunkedTx_Rollback(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+	insertErr := errors.New("duplicate entry")
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`email`, `id`) VALUES")).
+		WithArgs("john@example.com", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`email`, `id`) VALUES")).
+		WithArgs("jane@example.com", 2).
+		WillReturnError(insertErr)
+	sqlMock.ExpectRollback()
+
+	_, err := client.Q().Into("users").
+		ValuesMaps(
+			map[string]any{"id": 1, "email": "john@example.com"},
+			map[string]any{"id": 2, "email": "jane@example.com"},
+		).
+		ExecChunkedTx(context.Background(), 1)
+
+	assert.ErrorIs(t, err, insertErr)
+	assert.ErrorContains(t, err, "could not execute chunk 2 of 2")
+}
+
+func TestInsertExecChunked_Unsupported(t *testing.T) {
+	client, _ := newIterateClient(t)
+
+	_, err := client.Q().Into("users").FromSelect(sqlc.From("leads")).ExecChunked(context.Background(), 10)
+	assert.EqualError(t, err, "an INSERT ... SELECT can not be executed in chunks")
+
+	_, err = client.Q().Into("users").Columns("id").Values(1).Returning("id").ExecChunked(context.Background(), 10)
+	assert.EqualError(t, err, "an INSERT with a RETURNING clause can not be executed in chunks, use ExecReturning instead")
+}
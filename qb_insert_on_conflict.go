@@ -0,0 +1,385 @@
+package sqlc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/justtrackio/gosoline/pkg/funk"
+)
+
+// ErrOnConflictNotSupported is returned when an ON CONFLICT clause is built for a dialect
+// which does not support it (e.g. MySQL). Use Upsert() to build a query which works for both.
+// It wraps ErrNotSupportedByDialect.
+var ErrOnConflictNotSupported = fmt.Errorf("ON CONFLICT clause is %w", ErrNotSupportedByDialect)
+
+// onConflictClause holds the conflict target and the action of an ON CONFLICT clause.
+type onConflictClause struct {
+	columns     []string
+	doNothing   bool
+	assignments []Assignment
+	where       *SqlerWhere
+}
+
+// copy creates a copy of the clause, which can be reconfigured without affecting the original.
+func (c *onConflictClause) copy() *onConflictClause {
+	if c == nil {
+		return nil
+	}
+
+	return &onConflictClause{
+		columns:     append([]string{}, c.columns...),
+		doNothing:   c.doNothing,
+		assignments: append([]Assignment{}, c.assignments...),
+		where: &SqlerWhere{
+			clauses: append([]string{}, c.where.clauses...),
+			params:  append([]any{}, c.where.params...),
+			config:  c.where.config,
+			err:     c.where.err,
+		},
+	}
+}
+
+// upsertClause holds the key and update columns of a dialect-neutral upsert.
+// It is translated into ON CONFLICT or ON DUPLICATE KEY UPDATE when the query is built.
+type upsertClause struct {
+	keyColumns    []string
+	updateColumns []string
+}
+
+// OnConflictBuilder configures the ON CONFLICT clause of an insert query.
+// It is created by InsertQueryBuilder.OnConflict() and completed by DoUpdate() or DoNothing(),
+// which return the insert query builder with the clause attached.
+type OnConflictBuilder struct {
+	query   *InsertQueryBuilder
+	columns []string
+	where   *SqlerWhere
+}
+
+// OnConflict starts an ON CONFLICT clause (PostgreSQL) for the given conflict target columns.
+// The clause has to be completed with DoUpdate() or DoNothing().
+// Without columns, the clause applies to any unique constraint, which is only allowed for DoNothing().
+// Queries built for a dialect without ON CONFLICT support fail with ErrOnConflictNotSupported.
+//
+// Example:
+//
+//	Into("users").
+//		Columns("id", "name").
+//		Values(1, "John").
+//		OnConflict("id").
+//		DoUpdate(AssignExcluded("name"))
+//	// INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"
+func (q *InsertQueryBuilder) OnConflict(cols ...string) *OnConflictBuilder {
+	return &OnConflictBuilder{
+		query:   q,
+		columns: append([]string{}, cols...),
+		where:   NewSqlerWhere().WithConfig(q.config),
+	}
+}
+
+// Where adds a condition to the DO UPDATE action, only rows matching it are updated.
+// Multiple Where() calls are combined with AND. Use AssignExcluded() or "EXCLUDED.col" to refer
+// to the row proposed for insertion.
+// Returns a new OnConflictBuilder with the condition added.
+//
+// Example:
+//
+//	OnConflict("id").
+//		Where("users.version < EXCLUDED.version").
+//		DoUpdate(AssignExcluded("name"), AssignExcluded("version"))
+//	// ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "version" = EXCLUDED."version" WHERE users.version < EXCLUDED.version
+func (b *OnConflictBuilder) Where(condition any, params ...any) *OnConflictBuilder {
+	where := &SqlerWhere{
+		clauses: append([]string{}, b.where.clauses...),
+		params:  append([]any{}, b.where.params...),
+		config:  b.where.config,
+		err:     b.where.err,
+	}
+	where.Where(condition, params...)
+
+	return &OnConflictBuilder{
+		query:   b.query,
+		columns: b.columns,
+		where:   where,
+	}
+}
+
+// DoUpdate completes the clause with a DO UPDATE SET action using the given assignments.
+// Replaces any previously set ON CONFLICT clause.
+// Returns a new insert query builder with the ON CONFLICT clause set.
+//
+// Example:
+//
+//	Into("counters").
+//		Columns("id", "count").
+//		Values(1, 1).
+//		OnConflict("id").
+//		DoUpdate(AssignExpr("count", "counters.count + 1"))
+//	// ... ON CONFLICT ("id") DO UPDATE SET "count" = counters.count + 1
+func (b *OnConflictBuilder) DoUpdate(assignments ...Assignment) *InsertQueryBuilder {
+	newQuery := b.query.copyQuery()
+	newQuery.onConflict = &onConflictClause{
+		columns:     b.columns,
+		assignments: append([]Assignment{}, assignments...),
+		where:       b.where,
+	}
+
+	return newQuery
+}
+
+// DoNothing completes the clause with a DO NOTHING action, conflicting rows are skipped.
+// Replaces any previously set ON CONFLICT clause.
+// Returns a new insert query builder with the ON CONFLICT clause set.
+//
+// Example:
+//
+//	Into("users").
+//		Columns("id", "name").
+//		Values(1, "John").
+//		OnConflict("id").
+//		DoNothing()
+//	// ... ON CONFLICT ("id") DO NOTHING
+func (b *OnConflictBuilder) DoNothing() *InsertQueryBuilder {
+	newQuery := b.query.copyQuery()
+	newQuery.onConflict = &onConflictClause{
+		columns:   b.columns,
+		doNothing: true,
+		where:     b.where,
+	}
+
+	return newQuery
+}
+
+// Upsert makes the insert update the given columns of an existing row with the same key
+// instead of failing. The syntax is chosen based on the dialect of the config:
+// ON CONFLICT for dialects supporting it (PostgreSQL), ON DUPLICATE KEY UPDATE otherwise (MySQL).
+// Without update columns, conflicting rows are kept as they are.
+// Replaces any previously set upsert.
+// Returns a new query builder with the upsert set.
+//
+// Example:
+//
+//	Into("users").
+//		Records(user).
+//		Upsert([]string{"id"}, []string{"name", "email"})
+//	// MySQL:      ... ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `email` = VALUES(`email`)
+//	// PostgreSQL: ... ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "email" = EXCLUDED."email"
+func (q *InsertQueryBuilder) Upsert(keyCols []string, updateCols []string) *InsertQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.upsert = &upsertClause{
+		keyColumns:    append([]string{}, keyCols...),
+		updateColumns: append([]string{}, updateCols...),
+	}
+
+	return newQuery
+}
+
+// supportsOnConflict reports whether conflicts are resolved with ON CONFLICT for the configured dialect.
+func (q *InsertQueryBuilder) supportsOnConflict() bool {
+	dialect := dialectOf(q.config)
+
+	return dialect != nil && dialect.SupportsOnConflict()
+}
+
+// ignoreAsOnConflict reports whether the IGNORE modifier is translated into ON CONFLICT DO NOTHING,
+// which is the case for dialects supporting ON CONFLICT but no INSERT IGNORE (PostgreSQL).
+func (q *InsertQueryBuilder) ignoreAsOnConflict() bool {
+	dialect := dialectOf(q.config)
+
+	return q.ignore && dialect != nil && !dialect.SupportsInsertIgnore() && dialect.SupportsOnConflict()
+}
+
+// resolveConflictClause returns the ON DUPLICATE KEY UPDATE assignments and the ON CONFLICT clause
+// the query is built with. An upsert or the IGNORE modifier is translated into the syntax of the configured dialect.
+func (q *InsertQueryBuilder) resolveConflictClause() (onDuplicate []Assignment, onConflict *onConflictClause, err error) {
+	onDuplicate, onConflict = q.onDuplicate, q.onConflict
+
+	if q.upsert != nil {
+		if len(onDuplicate) > 0 || onConflict != nil {
+			return nil, nil, errors.New("Upsert can not be combined with ON DUPLICATE KEY UPDATE or ON CONFLICT")
+		}
+
+		if q.supportsOnConflict() {
+			onConflict, err = q.upsert.toOnConflict(q.config)
+		} else {
+			onDuplicate, err = q.upsert.toOnDuplicate(q.config)
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not build upsert: %w", err)
+		}
+	}
+
+	if q.ignoreAsOnConflict() {
+		if len(onDuplicate) > 0 || onConflict != nil {
+			return nil, nil, fmt.Errorf("IGNORE can not be combined with a conflict clause for dialect %s", q.config.Dialect.Name())
+		}
+
+		onConflict = &onConflictClause{
+			doNothing: true,
+			where:     NewSqlerWhere().WithConfig(q.config),
+		}
+	}
+
+	if len(onDuplicate) > 0 && onConflict != nil {
+		return nil, nil, errors.New("ON DUPLICATE KEY UPDATE can not be combined with ON CONFLICT")
+	}
+
+	if len(onDuplicate) > 0 {
+		if err = checkDialectSupport(q.config, "ON DUPLICATE KEY UPDATE", Dialect.SupportsOnDuplicateKeyUpdate, "use OnConflict() or Upsert() instead"); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if onConflict != nil && dialectOf(q.config) != nil && !q.supportsOnConflict() {
+		return nil, nil, ErrOnConflictNotSupported
+	}
+
+	return onDuplicate, onConflict, nil
+}
+
+// toOnConflict translates the upsert into an ON CONFLICT clause.
+func (u *upsertClause) toOnConflict(config *QueryBuilderConfig) (*onConflictClause, error) {
+	clause := &onConflictClause{
+		columns: u.keyColumns,
+		where:   NewSqlerWhere().WithConfig(config),
+	}
+
+	if len(u.updateColumns) == 0 {
+		clause.doNothing = true
+
+		return clause, nil
+	}
+
+	if len(u.keyColumns) == 0 {
+		return nil, errors.New("key columns are required to update conflicting rows")
+	}
+
+	clause.assignments = funk.Map(u.updateColumns, AssignExcluded)
+
+	return clause, nil
+}
+
+// toOnDuplicate translates the upsert into ON DUPLICATE KEY UPDATE assignments.
+// Without update columns, the first key column is assigned to itself, which keeps the existing row.
+func (u *upsertClause) toOnDuplicate(config *QueryBuilderConfig) ([]Assignment, error) {
+	if len(u.updateColumns) > 0 {
+		return funk.Map(u.updateColumns, AssignExcluded), nil
+	}
+
+	if len(u.keyColumns) == 0 {
+		return nil, errors.New("key or update columns are required")
+	}
+
+	return []Assignment{AssignExpr(u.keyColumns[0], quoteIdentifier(u.keyColumns[0], config.IdentifierQuote))}, nil
+}
+
+// buildConflictClause builds the ON DUPLICATE KEY UPDATE or ON CONFLICT clause.
+// Values of assignments use positional placeholders starting at paramIndex, or named placeholders
+// (the column name) if named is set.
+// Returns the clause string and any additional parameters.
+func (q *InsertQueryBuilder) buildConflictClause(paramIndex int, named bool) (clause string, params []any, err error) {
+	onDuplicate, onConflict, err := q.resolveConflictClause()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(onDuplicate) > 0 {
+		assignments, params := q.buildAssignments(onDuplicate, paramIndex, named, func(quotedCol string) string {
+			return "VALUES(" + quotedCol + ")"
+		})
+
+		return " ON DUPLICATE KEY UPDATE " + assignments, params, nil
+	}
+
+	if onConflict != nil {
+		return q.buildOnConflictClause(onConflict, paramIndex, named)
+	}
+
+	return "", nil, nil
+}
+
+// buildOnConflictClause builds the ON CONFLICT clause.
+func (q *InsertQueryBuilder) buildOnConflictClause(onConflict *onConflictClause, paramIndex int, named bool) (clause string, params []any, err error) {
+	var sql strings.Builder
+	sql.WriteString(" ON CONFLICT")
+
+	if len(onConflict.columns) > 0 {
+		quotedColumns := funk.Map(onConflict.columns, func(col string) string {
+			return quoteIdentifier(col, q.config.IdentifierQuote)
+		})
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(quotedColumns, ", "))
+		sql.WriteString(")")
+	}
+
+	if onConflict.doNothing {
+		sql.WriteString(" DO NOTHING")
+
+		return sql.String(), []any{}, nil
+	}
+
+	if len(onConflict.columns) == 0 {
+		return "", nil, errors.New("ON CONFLICT DO UPDATE requires conflict columns")
+	}
+
+	if len(onConflict.assignments) == 0 {
+		return "", nil, errors.New("ON CONFLICT DO UPDATE requires at least one assignment")
+	}
+
+	assignments, params := q.buildAssignments(onConflict.assignments, paramIndex, named, func(quotedCol string) string {
+		return "EXCLUDED." + quotedCol
+	})
+	paramIndex += len(params)
+
+	sql.WriteString(" DO UPDATE SET ")
+	sql.WriteString(assignments)
+
+	whereSQL, whereArgs, err := onConflict.where.toSqlWithStartIndex(paramIndex)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not build ON CONFLICT WHERE clause: %w", err)
+	}
+
+	if whereSQL != "" {
+		if named && len(whereArgs) > 0 {
+			return "", nil, errors.New("ON CONFLICT WHERE parameters can not be used with named parameters")
+		}
+
+		sql.WriteString(" WHERE ")
+		sql.WriteString(whereSQL)
+		params = append(params, whereArgs...)
+	}
+
+	return sql.String(), params, nil
+}
+
+// buildAssignments builds the comma separated assignments of a conflict clause.
+// Expressions are inserted directly, values use placeholders and references to the row proposed
+// for insertion are rendered using the excluded function.
+func (q *InsertQueryBuilder) buildAssignments(assignments []Assignment, paramIndex int, named bool, excluded func(quotedCol string) string) (string, []any) {
+	parts := make([]string, len(assignments))
+	params := []any{}
+
+	for i, assignment := range assignments {
+		quotedCol := quoteIdentifier(assignment.Column, q.config.IdentifierQuote)
+
+		switch {
+		case assignment.excluded:
+			// Reference to the value proposed for insertion
+			parts[i] = fmt.Sprintf("%s = %s", quotedCol, excluded(quotedCol))
+		case assignment.IsExpr:
+			// Expression - insert directly without parameterization
+			parts[i] = fmt.Sprintf("%s = %v", quotedCol, assignment.Value)
+		case named:
+			// Value - use named placeholder (same as column name)
+			parts[i] = fmt.Sprintf("%s = :%s", quotedCol, assignment.Column)
+		default:
+			// Value - use placeholder
+			parts[i] = fmt.Sprintf("%s = %s", quotedCol, q.config.PlaceholderFormat(paramIndex))
+			params = append(params, assignment.Value)
+			paramIndex++
+		}
+	}
+
+	return strings.Join(parts, ", "), params
+}
@@ -0,0 +1,265 @@
+package sqlc_test
+
+import (
+	"testing"
+
+	"github.com/gosoline-project/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnConflictDoUpdate(t *testing.T) {
+	config := postgresConfig()
+
+	sql, params, err := sqlc.Into("users").
+		WithConfig(config).
+		Columns("id", "name", "version").
+		Values(1, "John", 2).
+		OnConflict("id").
+		Where("users.version < ?", 2).
+		DoUpdate(sqlc.AssignExcluded("name"), sqlc.Assign("status", "active"), sqlc.AssignExpr("version", "users.version + 1")).
+		Returning("id").
+		ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `INSERT INTO "users" ("id", "name", "version") VALUES ($1, $2, $3) `+
+		`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "status" = $4, "version" = users.version + 1 `+
+		`WHERE users.version < $5 RETURNING "id"`, sql)
+	assert.Equal(t, []any{1, "John", 2, "active", 2}, params)
+}
+
+func TestOnConflictDoNothing(t *testing.T) {
+	config := postgresConfig()
+
+	sql, params, err := sqlc.Into("users").
+		WithConfig(config).
+		Columns("id", "name").
+		Values(1, "John").
+		OnConflict().
+		DoNothing().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT DO NOTHING`, sql)
+	assert.Equal(t, []any{1, "John"}, params)
+
+	sql, _, err = sqlc.Into("users").
+		WithConfig(config).
+		Columns("email").
+		Values("john@example.com").
+		OnConflict("email").
+		DoNothing().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("email") VALUES ($1) ON CONFLICT ("email") DO NOTHING`, sql)
+}
+
+func TestOnConflictWithRecords(t *testing.T) {
+	config := postgresConfig()
+	user := TestUser{ID: 1, Name: "John", Email: "john@example.com"}
+
+	sql, params, err := sqlc.Into("users").
+		WithConfig(config).
+		Records(user).
+		OnConflict("id").
+		DoUpdate(sqlc.AssignExcluded("name"), sqlc.Assign("email", nil)).
+		ToNamedSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `INSERT INTO "users" ("id", "name", "email") VALUES (:id, :name, :email) `+
+		`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "email" = :email`, sql)
+	assert.Equal(t, []any{user}, params)
+
+	_, _, err = sqlc.Into("users").
+		WithConfig(config).
+		Records(user).
+		OnConflict("id").
+		Where("users.name != ?", "admin").
+		DoUpdate(sqlc.AssignExcluded("name")).
+		ToNamedSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "can not be used with named parameters")
+}
+
+func TestOnConflictFromSelect(t *testing.T) {
+	config := postgresConfig()
+
+	sql, params, err := sqlc.Into("totals").
+		WithConfig(config).
+		Columns("user_id", "total").
+		FromSelect(sqlc.From("orders").WithConfig(config).Columns("user_id", sqlc.Col("total").Sum()).Where("status = ?", "paid").GroupBy("user_id")).
+		OnConflict("user_id").
+		DoUpdate(sqlc.AssignExcluded("total")).
+		ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `INSERT INTO "totals" ("user_id", "total") SELECT "user_id", SUM("total") FROM "orders" WHERE status = $1 GROUP BY "user_id" `+
+		`ON CONFLICT ("user_id") DO UPDATE SET "total" = EXCLUDED."total"`, sql)
+	assert.Equal(t, []any{"paid"}, params)
+}
+
+func TestOnConflictImmutability(t *testing.T) {
+	config := postgresConfig()
+
+	base := sqlc.Into("users").WithConfig(config).Columns("id", "name").Values(1, "John")
+	conflict := base.OnConflict("id")
+	filtered := conflict.Where("users.locked = ?", false)
+
+	sql, _, err := conflict.DoUpdate(sqlc.AssignExcluded("name")).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`, sql)
+
+	sql, params, err := filtered.DoUpdate(sqlc.AssignExcluded("name")).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name" WHERE users.locked = $3`, sql)
+	assert.Equal(t, []any{1, "John", false}, params)
+
+	sql, _, err = base.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name") VALUES ($1, $2)`, sql)
+}
+
+func TestOnConflictErrors(t *testing.T) {
+	mysqlConfig := sqlc.DefaultConfig()
+	mysqlConfig.Dialect = sqlc.NewMysqlDialect()
+
+	_, _, err := sqlc.Into("users").
+		WithConfig(mysqlConfig).
+		Columns("id").
+		Values(1).
+		OnConflict("id").
+		DoNothing().
+		ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrOnConflictNotSupported)
+
+	_, _, err = sqlc.Into("users").
+		Columns("id", "name").
+		Values(1, "John").
+		OnConflict().
+		DoUpdate(sqlc.AssignExcluded("name")).
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "requires conflict columns")
+
+	_, _, err = sqlc.Into("users").
+		Columns("id", "name").
+		Values(1, "John").
+		OnConflict("id").
+		DoUpdate().
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "requires at least one assignment")
+
+	_, _, err = sqlc.Into("users").
+		Columns("id", "name").
+		Values(1, "John").
+		OnDuplicateKeyUpdate(sqlc.AssignExcluded("name")).
+		OnConflict("id").
+		DoNothing().
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "can not be combined with ON CONFLICT")
+
+	_, _, err = sqlc.Into("users").
+		Columns("id", "name").
+		Values(1, "John").
+		Replace().
+		OnConflict("id").
+		DoNothing().
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "REPLACE cannot be used with ON CONFLICT")
+}
+
+func TestAssignExcludedOnDuplicateKeyUpdate(t *testing.T) {
+	sql, params, err := sqlc.Into("users").
+		Columns("id", "name", "count").
+		Values(1, "John", 5).
+		OnDuplicateKeyUpdate(sqlc.AssignExcluded("name"), sqlc.AssignExpr("count", "count + 1")).
+		ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "INSERT INTO `users` (`id`, `name`, `count`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `count` = count + 1", sql)
+	assert.Equal(t, []any{1, "John", 5}, params)
+}
+
+func TestUpsert(t *testing.T) {
+	query := sqlc.Into("users").
+		Columns("id", "name", "email").
+		Values(1, "John", "john@example.com").
+		Upsert([]string{"id"}, []string{"name", "email"})
+
+	// without a dialect the MySQL syntax of the default config is used
+	sql, params, err := query.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`id`, `name`, `email`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `email` = VALUES(`email`)", sql)
+	assert.Equal(t, []any{1, "John", "john@example.com"}, params)
+
+	sql, params, err = query.WithConfig(postgresConfig()).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name", "email") VALUES ($1, $2, $3) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "email" = EXCLUDED."email"`, sql)
+	assert.Equal(t, []any{1, "John", "john@example.com"}, params)
+}
+
+func TestUpsertWithoutUpdateColumns(t *testing.T) {
+	query := sqlc.Into("users").
+		Columns("id", "name").
+		Values(1, "John").
+		Upsert([]string{"id"}, nil)
+
+	sql, _, err := query.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `id` = `id`", sql)
+
+	sql, _, err = query.WithConfig(postgresConfig()).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO NOTHING`, sql)
+}
+
+func TestUpsertErrors(t *testing.T) {
+	_, _, err := sqlc.Into("users").
+		Columns("id").
+		Values(1).
+		Upsert(nil, nil).
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "key or update columns are required")
+
+	_, _, err = sqlc.Into("users").
+		WithConfig(postgresConfig()).
+		Columns("id", "name").
+		Values(1, "John").
+		Upsert(nil, []string{"name"}).
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "key columns are required")
+
+	_, _, err = sqlc.Into("users").
+		Columns("id", "name").
+		Values(1, "John").
+		OnDuplicateKeyUpdate(sqlc.AssignExcluded("name")).
+		Upsert([]string{"id"}, []string{"name"}).
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Upsert can not be combined")
+}
+
+func TestGenericUpsert(t *testing.T) {
+	user := TestUser{ID: 1, Name: "John", Email: "john@example.com"}
+
+	sql, _, err := sqlc.IntoG[TestUser]("users").
+		Records(user).
+		Upsert([]string{"id"}, []string{"name"}).
+		ToNamedSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`id`, `name`, `email`) VALUES (:id, :name, :email) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", sql)
+
+	sql, _, err = sqlc.IntoG[TestUser]("users").
+		WithConfig(postgresConfig()).
+		Records(user).
+		OnConflict("id").
+		Where("users.email = EXCLUDED.email").
+		DoUpdate(sqlc.AssignExcluded("name")).
+		ToNamedSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name", "email") VALUES (:id, :name, :email) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name" WHERE users.email = EXCLUDED.email`, sql)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gosoline-project/sqlc"
 	mocks "github.com/gosoline-project/sqlc/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1371,3 +1372,111 @@ func TestOnDuplicateKeyUpdateWithNamedExec(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func postgresConfig() *sqlc.QueryBuilderConfig {
+	return &sqlc.QueryBuilderConfig{
+		StructTag:       "db",
+		Placeholder:     "$",
+		IdentifierQuote: `"`,
+		Dialect:         sqlc.NewPostgresDialect(),
+	}
+}
+
+func TestInsertReturning(t *testing.T) {
+	config := postgresConfig()
+
+	sql, params, err := sqlc.Into("users").
+		WithConfig(config).
+		Columns("name", "email").
+		Values("John", "john@example.com").
+		Returning("id", "created_at").
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("name", "email") VALUES ($1, $2) RETURNING "id", "created_at"`, sql)
+	assert.Equal(t, []any{"John", "john@example.com"}, params)
+
+	sql, params, err = sqlc.Into("archived_users").
+		WithConfig(config).
+		FromSelect(sqlc.From("users").WithConfig(config).Where("status = ?", "inactive")).
+		Returning("*").
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "archived_users" SELECT * FROM "users" WHERE status = $1 RETURNING *`, sql)
+	assert.Equal(t, []any{"inactive"}, params)
+
+	sql, _, err = sqlc.Into("users").
+		WithConfig(config).
+		Records(TestUser{Name: "John", Email: "john@example.com"}).
+		Returning("id").
+		ToNamedSql()
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id", "name", "email") VALUES (:id, :name, :email) RETURNING "id"`, sql)
+}
+
+func TestInsertReturningNotSupported(t *testing.T) {
+
+	config := sqlc.DefaultConfig()
+	config.Dialect = sqlc.NewMysqlDialect()
+
+	_, _, err := sqlc.Into("users").
+		WithConfig(config).
+		Columns("name").
+		Values("John").
+		Returning("id").
+		ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrReturningNotSupported)
+}
+
+func TestInsertExecReturning(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mocks.NewClient(t)
+
+	mockClient.EXPECT().
+		Select(mock.Anything, mock.Anything, "INSERT INTO `users` (`id`, `name`, `email`) VALUES (?, ?, ?), (?, ?, ?) RETURNING `id`, `name`, `email`", mock.Anything).
+		RunAndReturn(func(ctx context.Context, dest any, query string, args ...any) error {
+			assert.Equal(t, []any{0, "John", "john@example.com", 0, "Jane", "jane@example.com"}, args)
+			*dest.(*[]TestUser) = []TestUser{{ID: 1, Name: "John"}, {ID: 2, Name: "Jane"}}
+
+			return nil
+		})
+
+	var users []TestUser
+	err := sqlc.Into("users").
+		WithClient(mockClient).
+		Records(TestUser{Name: "John", Email: "john@example.com"}, TestUser{Name: "Jane", Email: "jane@example.com"}).
+		ExecReturning(ctx, &users)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, []int{users[0].ID, users[1].ID})
+
+	err = sqlc.Into("users").Columns("name").Values("John").ExecReturning(ctx, &users)
+	assert.EqualError(t, err, "no client set for query execution")
+
+	err = sqlc.Into("users").WithClient(mockClient).Columns("name").Values("John").ExecReturning(ctx, users)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ExecReturning: destination must be a pointer")
+}
+
+func TestGenericInsertExecReturning(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mocks.NewClient(t)
+
+	mockClient.EXPECT().
+		Select(mock.Anything, mock.Anything, "INSERT INTO `users` (`name`) VALUES (?) RETURNING `id`, `name`", mock.Anything).
+		RunAndReturn(func(ctx context.Context, dest any, query string, args ...any) error {
+			assert.Equal(t, []any{"John"}, args)
+			*dest.(*[]TestUser) = []TestUser{{ID: 7, Name: "John"}}
+
+			return nil
+		})
+
+	users, err := sqlc.IntoG[TestUser]("users").
+		WithClient(mockClient).
+		Columns("name").
+		Values("John").
+		Returning("id", "name").
+		ExecReturning(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []TestUser{{ID: 7, Name: "John"}}, users)
+}
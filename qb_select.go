@@ -27,37 +27,101 @@ type SelectQueryBuilder struct {
 	client          Querier
 	config          *QueryBuilderConfig
 	table           string
+	fromQuery       *SelectQueryBuilder // derived table, set if From() was called with a subquery
 	tableAlias      string
-	projections     []string
-	projectionExprs []*Expression // expressions in projections that may have bind parameters
+	projections     []string      // column names, empty for expressions
+	projectionExprs []*Expression // expressions in projections, nil for column names
+	joins           []joinClause
 	distinct        bool
+	sqlerWith       *SqlerWith
 	sqlerWhere      *SqlerWhere
 	sqlerGroupBy    *SqlerGroupBy
 	sqlerHaving     *SqlerHaving
+	windows         []namedWindow
 	sqlerOrderBy    *SqlerOrderBy
 	limitValue      *int
 	offsetValue     *int
+	setParts        []setPart // parts of a compound query (UNION, INTERSECT, EXCEPT)
+	lock            *lockClause
 	err             error
 }
 
+// setPart is a single query of a compound query.
+// The operator of the first part is empty, all following parts are combined
+// with the previous ones using their operator.
+type setPart struct {
+	operator string // "", "UNION", "UNION ALL", "INTERSECT" or "EXCEPT"
+	query    *SelectQueryBuilder
+}
+
+// namedWindow is a window defined in the WINDOW clause of a SELECT query.
+type namedWindow struct {
+	name string
+	spec *WindowSpec
+}
+
+// joinClause represents a single JOIN of a SELECT query.
+// The table and condition are rendered when the final query is built, so they are quoted
+// like the rest of the query even if its config is changed after the join has been added.
+type joinClause struct {
+	kind          string      // "INNER JOIN", "LEFT JOIN", "RIGHT JOIN", "FULL OUTER JOIN" or "CROSS JOIN"
+	table         string      // table name including an optional alias
+	condition     string      // raw ON condition, empty for CROSS JOIN and expression conditions
+	conditionExpr *Expression // ON condition given as expression
+	params        []any
+}
+
+// conditionSQL renders the ON condition of the join, or an empty string for a CROSS JOIN.
+func (j joinClause) conditionSQL(config *QueryBuilderConfig) string {
+	if j.conditionExpr != nil {
+		return j.conditionExpr.toConditionSQL(config)
+	}
+
+	return j.condition
+}
+
 // From creates a new SelectQueryBuilder for the specified table.
 // This is the entry point for building SELECT queries.
+// The table is either a table name or a *SelectQueryBuilder which is used as a derived table.
+// Derived tables require an alias set via As().
 //
 // Example:
 //
 //	From("users")                   // SELECT * FROM `users`
 //	From("orders").As("o")          // SELECT * FROM `orders` AS o
-func From(table string) *SelectQueryBuilder {
+//	From(From("orders").Where("total > ?", 100)).As("t") // SELECT * FROM (SELECT * FROM `orders` WHERE total > ?) AS t
+func From(table any) *SelectQueryBuilder {
 	cfg := DefaultConfig()
-	return &SelectQueryBuilder{
-		table:        table,
+	query := &SelectQueryBuilder{
 		config:       cfg,
 		projections:  []string{},
+		sqlerWith:    NewSqlerWith().WithConfig(cfg),
 		sqlerWhere:   NewSqlerWhere().WithConfig(cfg),
 		sqlerGroupBy: NewSqlerGroupBy().WithConfig(cfg),
 		sqlerHaving:  NewSqlerHaving().WithConfig(cfg),
 		sqlerOrderBy: NewSqlerOrderBy().WithConfig(cfg),
 	}
+
+	switch v := table.(type) {
+	case string:
+		query.table = v
+	case *SelectQueryBuilder:
+		if v == nil {
+			query.err = errors.New("invalid From argument: subquery must not be nil")
+
+			return query
+		}
+		if err := SubQuery(v).validate(); err != nil {
+			query.err = fmt.Errorf("invalid From argument: %w", err)
+
+			return query
+		}
+		query.fromQuery = v
+	default:
+		query.err = fmt.Errorf("invalid type for From argument: expected string or *SelectQueryBuilder, got %T", table)
+	}
+
+	return query
 }
 
 // copyQuery creates a shallow copy of the query builder.
@@ -98,14 +162,20 @@ func (q *SelectQueryBuilder) copyQuery() *SelectQueryBuilder {
 		client:          q.client,
 		config:          q.config,
 		table:           q.table,
+		fromQuery:       q.fromQuery,
 		tableAlias:      q.tableAlias,
 		projections:     append([]string{}, q.projections...),
 		projectionExprs: append([]*Expression{}, q.projectionExprs...),
+		joins:           append([]joinClause{}, q.joins...),
 		distinct:        q.distinct,
+		sqlerWith:       q.sqlerWith.copy(),
 		sqlerWhere:      newSqlerWhere,
 		sqlerGroupBy:    newSqlerGroupBy,
 		sqlerHaving:     newSqlerHaving,
+		windows:         append([]namedWindow{}, q.windows...),
 		sqlerOrderBy:    newSqlerOrderBy,
+		setParts:        append([]setPart{}, q.setParts...),
+		lock:            q.lock.copy(),
 		err:             q.err,
 	}
 	if q.limitValue != nil {
@@ -147,6 +217,7 @@ func (q *SelectQueryBuilder) WithClient(client Querier) *SelectQueryBuilder {
 func (q *SelectQueryBuilder) WithConfig(config *QueryBuilderConfig) *SelectQueryBuilder {
 	newQuery := q.copyQuery()
 	newQuery.config = config
+	newQuery.sqlerWith.WithConfig(config)
 	newQuery.sqlerWhere.WithConfig(config)
 	newQuery.sqlerHaving.WithConfig(config)
 	newQuery.sqlerGroupBy.WithConfig(config)
@@ -170,7 +241,8 @@ func (q *SelectQueryBuilder) As(alias string) *SelectQueryBuilder {
 }
 
 // Columns replaces the current column list with the specified columns.
-// Accepts strings (column names) or *Expression objects for more complex selections.
+// Accepts strings (column names), *Expression objects for more complex selections
+// or *SelectQueryBuilder objects for scalar subqueries.
 // Returns a new query builder with the updated column list.
 //
 // Example:
@@ -178,21 +250,16 @@ func (q *SelectQueryBuilder) As(alias string) *SelectQueryBuilder {
 //	Columns("id", "name", "email")              // SELECT `id`, `name`, `email`
 //	Columns(Col("id"), Col("name").As("user_name")) // SELECT `id`, `name` AS user_name
 //	Columns(Col("id").Count().As("total"))      // SELECT COUNT(`id`) AS total
+//	Columns("id", SubQuery(From("orders").Columns(Col("*").Count()).Where("orders.user_id = users.id")).As("orders"))
+//	// SELECT `id`, (SELECT COUNT(*) FROM `orders` WHERE orders.user_id = users.id) AS orders
 func (q *SelectQueryBuilder) Columns(cols ...any) *SelectQueryBuilder {
 	newQuery := q.copyQuery()
 	newQuery.projections = []string{}
 	newQuery.projectionExprs = []*Expression{}
 
 	for i, col := range cols {
-		switch v := col.(type) {
-		case string:
-			newQuery.projections = append(newQuery.projections, quoteIdentifier(v, newQuery.config.IdentifierQuote))
-			newQuery.projectionExprs = append(newQuery.projectionExprs, nil) // no expression for string columns
-		case *Expression:
-			newQuery.projections = append(newQuery.projections, v.toSQL(newQuery.config.IdentifierQuote))
-			newQuery.projectionExprs = append(newQuery.projectionExprs, v) // store expression for parameter collection
-		default:
-			newQuery.err = fmt.Errorf("invalid type for Columns argument %d: expected string or *Expression, got %T", i, col)
+		if err := newQuery.addProjection(col); err != nil {
+			newQuery.err = fmt.Errorf("invalid type for Columns argument %d: %w", i, err)
 
 			return newQuery
 		}
@@ -215,20 +282,38 @@ func (q *SelectQueryBuilder) Columns(cols ...any) *SelectQueryBuilder {
 func (q *SelectQueryBuilder) Column(col any) *SelectQueryBuilder {
 	newQuery := q.copyQuery()
 
+	if err := newQuery.addProjection(col); err != nil {
+		newQuery.err = fmt.Errorf("invalid type for Column argument: %w", err)
+
+		return newQuery
+	}
+
+	return newQuery
+}
+
+// addProjection appends a single column to the projection list. The columns are rendered by ToSql,
+// so they are quoted like the query they end up in, e.g. when used as a subquery.
+// Subqueries are converted to expressions, so their parameters are collected as well.
+func (q *SelectQueryBuilder) addProjection(col any) error {
+	if v, ok := col.(*SelectQueryBuilder); ok {
+		col = SubQuery(v)
+	}
+
 	switch v := col.(type) {
 	case string:
-		newQuery.projections = append(newQuery.projections, quoteIdentifier(v, newQuery.config.IdentifierQuote))
-		newQuery.projectionExprs = append(newQuery.projectionExprs, nil) // no expression for string columns
+		q.projections = append(q.projections, v)
+		q.projectionExprs = append(q.projectionExprs, nil) // no expression for string columns
 	case *Expression:
-		newQuery.projections = append(newQuery.projections, v.toSQL(newQuery.config.IdentifierQuote))
-		newQuery.projectionExprs = append(newQuery.projectionExprs, v) // store expression for parameter collection
+		if err := v.validate(); err != nil {
+			return err
+		}
+		q.projections = append(q.projections, "")
+		q.projectionExprs = append(q.projectionExprs, v)
 	default:
-		newQuery.err = fmt.Errorf("invalid type for Column argument: expected string or *Expression, got %T", col)
-
-		return newQuery
+		return fmt.Errorf("expected string, *Expression or *SelectQueryBuilder, got %T", col)
 	}
 
-	return newQuery
+	return nil
 }
 
 // ForType automatically sets the column list based on struct field tags.
@@ -245,6 +330,17 @@ func (q *SelectQueryBuilder) Column(col any) *SelectQueryBuilder {
 //	From("users").ForType(&User{})  // SELECT `id`, `name`, `email`
 func (q *SelectQueryBuilder) ForType(t any) *SelectQueryBuilder {
 	newQuery := q.copyQuery()
+
+	// For compound queries the columns are set on all parts without explicit columns
+	if newQuery.isCompound() {
+		for i, part := range newQuery.setParts {
+			if len(part.query.projections) == 0 {
+				newQuery.setParts[i].query = part.query.ForType(t)
+			}
+		}
+
+		return newQuery
+	}
 	structTag := dbStructTag
 	if newQuery.config != nil && newQuery.config.StructTag != "" {
 		structTag = newQuery.config.StructTag
@@ -276,6 +372,264 @@ func (q *SelectQueryBuilder) Distinct() *SelectQueryBuilder {
 	return newQuery
 }
 
+// Join adds an INNER JOIN to the query.
+// The table may contain an optional alias separated by whitespace ("orders o" or "orders AS o").
+// The condition is rendered as the ON clause and accepts either:
+//   - A raw SQL string with placeholders and corresponding parameter values
+//   - An *Expression object that encapsulates the condition and parameters
+//
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	From("users").As("u").
+//		Join("orders o", "o.user_id = u.id")          // INNER JOIN `orders` AS o ON o.user_id = u.id
+//	From("users").As("u").
+//		Join("orders AS o", "o.user_id = u.id AND o.status = ?", "paid")
+func (q *SelectQueryBuilder) Join(table string, condition any, params ...any) *SelectQueryBuilder {
+	return q.join("INNER JOIN", table, condition, params...)
+}
+
+// LeftJoin adds a LEFT JOIN to the query.
+// See Join for the accepted table and condition formats.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	From("users").As("u").
+//		LeftJoin("orders o", "o.user_id = u.id")      // LEFT JOIN `orders` AS o ON o.user_id = u.id
+func (q *SelectQueryBuilder) LeftJoin(table string, condition any, params ...any) *SelectQueryBuilder {
+	return q.join("LEFT JOIN", table, condition, params...)
+}
+
+// RightJoin adds a RIGHT JOIN to the query.
+// See Join for the accepted table and condition formats.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	From("orders").As("o").
+//		RightJoin("users u", "o.user_id = u.id")      // RIGHT JOIN `users` AS u ON o.user_id = u.id
+func (q *SelectQueryBuilder) RightJoin(table string, condition any, params ...any) *SelectQueryBuilder {
+	return q.join("RIGHT JOIN", table, condition, params...)
+}
+
+// FullJoin adds a FULL OUTER JOIN to the query.
+// See Join for the accepted table and condition formats.
+// Note that MySQL does not support FULL OUTER JOIN.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	From("users").As("u").
+//		FullJoin("orders o", "o.user_id = u.id")      // FULL OUTER JOIN `orders` AS o ON o.user_id = u.id
+func (q *SelectQueryBuilder) FullJoin(table string, condition any, params ...any) *SelectQueryBuilder {
+	return q.join("FULL OUTER JOIN", table, condition, params...)
+}
+
+// CrossJoin adds a CROSS JOIN to the query.
+// A cross join has no ON condition and produces the cartesian product of both tables.
+// Returns a new query builder with the join added.
+//
+// Example:
+//
+//	From("sizes").CrossJoin("colors")             // CROSS JOIN `colors`
+//	From("sizes s").CrossJoin("colors c")         // CROSS JOIN `colors` AS c
+func (q *SelectQueryBuilder) CrossJoin(table string) *SelectQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.joins = append(newQuery.joins, joinClause{
+		kind:  "CROSS JOIN",
+		table: table,
+	})
+
+	return newQuery
+}
+
+// join adds a join of the given kind with an ON condition to a copy of the query.
+func (q *SelectQueryBuilder) join(kind string, table string, condition any, params ...any) *SelectQueryBuilder {
+	newQuery := q.copyQuery()
+	clause := joinClause{
+		kind:  kind,
+		table: table,
+	}
+
+	switch v := condition.(type) {
+	case string:
+		clause.condition = v
+		clause.params = append([]any{}, params...)
+	case *Expression:
+		if v == nil {
+			newQuery.err = fmt.Errorf("invalid condition for %s %s: expression must not be nil", kind, table)
+
+			return newQuery
+		}
+		if err := v.validate(); err != nil {
+			newQuery.err = fmt.Errorf("invalid condition for %s %s: %w", kind, table, err)
+
+			return newQuery
+		}
+		clause.conditionExpr = v
+		clause.params = v.collectParameters()
+	default:
+		newQuery.err = fmt.Errorf("invalid type for %s condition: expected string or *Expression, got %T", kind, condition)
+
+		return newQuery
+	}
+
+	if clause.conditionSQL(newQuery.config) == "" {
+		newQuery.err = fmt.Errorf("%s %s requires a condition", kind, table)
+
+		return newQuery
+	}
+
+	newQuery.joins = append(newQuery.joins, clause)
+
+	return newQuery
+}
+
+// With adds a common table expression which is rendered in a WITH clause before the SELECT.
+// The optional columns are rendered as the column list of the CTE.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	From("active_users").
+//		With("active_users", From("users").Where("status = ?", "active"))
+//	// WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?) SELECT * FROM `active_users`
+func (q *SelectQueryBuilder) With(name string, query *SelectQueryBuilder, columns ...string) *SelectQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.With(name, query, columns...)
+
+	if newQuery.sqlerWith.err != nil {
+		newQuery.err = newQuery.sqlerWith.err
+	}
+
+	return newQuery
+}
+
+// WithRecursive adds a recursive common table expression which is rendered in a WITH RECURSIVE clause.
+// The query may reference the CTE by its name.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	anchor := From("categories").Columns("id", "parent_id").Where("id = ?", 1)
+//	children := From("categories").As("c").Columns("c.id", "c.parent_id").Join("tree t", "c.parent_id = t.id")
+//	From("tree").
+//		WithRecursive("tree", anchor.UnionAll(children), "id", "parent_id")
+//	// WITH RECURSIVE `tree` (`id`, `parent_id`) AS (SELECT ... UNION ALL SELECT ...) SELECT * FROM `tree`
+func (q *SelectQueryBuilder) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *SelectQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.WithRecursive(name, query, columns...)
+
+	if newQuery.sqlerWith.err != nil {
+		newQuery.err = newQuery.sqlerWith.err
+	}
+
+	return newQuery
+}
+
+// Union combines the query with the given queries using UNION, removing duplicate rows.
+// The result is a compound query: OrderBy, Limit and Offset called on it apply to the
+// combined result, while all other clauses have to be set on the individual parts.
+// Parts with their own ORDER BY, LIMIT, OFFSET or WITH clause are rendered in parentheses.
+// Returns a new query builder for the compound query.
+//
+// Example:
+//
+//	From("customers").Columns("email").
+//		Union(From("leads").Columns("email")).
+//		OrderBy("email").
+//		Limit(10)
+//	// SELECT `email` FROM `customers` UNION SELECT `email` FROM `leads` ORDER BY `email` LIMIT ?
+func (q *SelectQueryBuilder) Union(queries ...*SelectQueryBuilder) *SelectQueryBuilder {
+	return q.setOperation("UNION", queries...)
+}
+
+// UnionAll combines the query with the given queries using UNION ALL, keeping duplicate rows.
+// See Union for details about compound queries.
+// Returns a new query builder for the compound query.
+//
+// Example:
+//
+//	From("orders_2024").Columns("id", "total").
+//		UnionAll(From("orders_2025").Columns("id", "total"))
+//	// SELECT `id`, `total` FROM `orders_2024` UNION ALL SELECT `id`, `total` FROM `orders_2025`
+func (q *SelectQueryBuilder) UnionAll(queries ...*SelectQueryBuilder) *SelectQueryBuilder {
+	return q.setOperation("UNION ALL", queries...)
+}
+
+// Intersect combines the query with the given queries using INTERSECT,
+// returning only rows present in all results. Requires MySQL 8.0.31 or later.
+// See Union for details about compound queries.
+// Returns a new query builder for the compound query.
+//
+// Example:
+//
+//	From("customers").Columns("email").
+//		Intersect(From("newsletter").Columns("email"))
+//	// SELECT `email` FROM `customers` INTERSECT SELECT `email` FROM `newsletter`
+func (q *SelectQueryBuilder) Intersect(queries ...*SelectQueryBuilder) *SelectQueryBuilder {
+	return q.setOperation("INTERSECT", queries...)
+}
+
+// Except combines the query with the given queries using EXCEPT,
+// returning rows of the first result which are not present in the others. Requires MySQL 8.0.31 or later.
+// See Union for details about compound queries.
+// Returns a new query builder for the compound query.
+//
+// Example:
+//
+//	From("customers").Columns("email").
+//		Except(From("unsubscribed").Columns("email"))
+//	// SELECT `email` FROM `customers` EXCEPT SELECT `email` FROM `unsubscribed`
+func (q *SelectQueryBuilder) Except(queries ...*SelectQueryBuilder) *SelectQueryBuilder {
+	return q.setOperation("EXCEPT", queries...)
+}
+
+// setOperation combines the query with the given queries using the set operator.
+// Operators are chained onto an existing compound query as long as no ORDER BY, LIMIT or OFFSET
+// has been applied to it yet, otherwise the query becomes the first part of a new compound query.
+func (q *SelectQueryBuilder) setOperation(operator string, queries ...*SelectQueryBuilder) *SelectQueryBuilder {
+	var newQuery *SelectQueryBuilder
+
+	if q.isCompound() && !q.hasResultModifiers() {
+		newQuery = q.copyQuery()
+	} else {
+		newQuery = From("").WithConfig(q.config)
+		newQuery.client = q.client
+		newQuery.setParts = []setPart{{query: q}}
+	}
+
+	if len(queries) == 0 {
+		newQuery.err = fmt.Errorf("%s requires at least one query", operator)
+
+		return newQuery
+	}
+
+	for i, query := range queries {
+		if query == nil {
+			newQuery.err = fmt.Errorf("invalid %s argument %d: query must not be nil", operator, i)
+
+			return newQuery
+		}
+
+		newQuery.setParts = append(newQuery.setParts, setPart{operator: operator, query: query})
+	}
+
+	return newQuery
+}
+
+// isCompound returns true if the query combines multiple queries using set operations.
+func (q *SelectQueryBuilder) isCompound() bool {
+	return len(q.setParts) > 0
+}
+
+// hasResultModifiers returns true if ORDER BY, LIMIT or OFFSET has been set on the query.
+func (q *SelectQueryBuilder) hasResultModifiers() bool {
+	return !q.sqlerOrderBy.IsEmpty() || q.limitValue != nil || q.offsetValue != nil
+}
+
 // Where adds a WHERE condition to the query.
 // Multiple Where() calls are combined with AND.
 // Accepts either:
@@ -352,6 +706,42 @@ func (q *SelectQueryBuilder) Having(condition any, params ...any) *SelectQueryBu
 	return newQuery
 }
 
+// Window adds a named window to the WINDOW clause of the query.
+// Window functions reference it with OverWindow(name) or extend it with WindowFrom(name).
+// Multiple Window() calls add multiple windows, rendered in the order they were added.
+// Returns a new query builder with the window added.
+//
+// Example:
+//
+//	From("orders").
+//		Columns("user_id", Col("total").Sum().OverWindow("w").As("running_total"), RowNumber().OverWindow("w").As("rn")).
+//		Window("w", Window().PartitionBy("user_id").OrderBy("created_at"))
+//	// SELECT `user_id`, SUM(`total`) OVER w AS running_total, ROW_NUMBER() OVER w AS rn
+//	// FROM `orders` WINDOW w AS (PARTITION BY `user_id` ORDER BY `created_at`)
+func (q *SelectQueryBuilder) Window(name string, spec *WindowSpec) *SelectQueryBuilder {
+	newQuery := q.copyQuery()
+
+	if name == "" {
+		newQuery.err = errors.New("window name must not be empty")
+
+		return newQuery
+	}
+
+	if spec == nil {
+		spec = Window()
+	}
+
+	if err := spec.validate(); err != nil {
+		newQuery.err = fmt.Errorf("invalid window %s: %w", name, err)
+
+		return newQuery
+	}
+
+	newQuery.windows = append(newQuery.windows, namedWindow{name: name, spec: spec})
+
+	return newQuery
+}
+
 // OrderBy sets the ORDER BY clause for the query.
 // Accepts strings (column names with optional ASC/DESC) or *Expression objects.
 // Replaces any previously set ORDER BY clause.
@@ -422,7 +812,11 @@ func (q *SelectQueryBuilder) ToSql() (query string, params []any, err error) {
 		return "", nil, q.err
 	}
 
-	if q.table == "" {
+	if q.isCompound() {
+		return q.toCompoundSql()
+	}
+
+	if q.table == "" && q.fromQuery == nil {
 		return "", nil, errors.New("table name is required")
 	}
 
@@ -431,6 +825,17 @@ func (q *SelectQueryBuilder) ToSql() (query string, params []any, err error) {
 	var sqlBuilder strings.Builder
 	paramIndex := 0 // Track current parameter index for numbered placeholders (0-based)
 
+	// WITH clause
+	if sql, args, err = q.sqlerWith.toSqlWithStartIndex(paramIndex); err != nil {
+		return "", nil, fmt.Errorf("could not build WITH clause: %w", err)
+	}
+	if sql != "" {
+		sqlBuilder.WriteString(sql)
+		sqlBuilder.WriteString(" ")
+		params = append(params, args...)
+		paramIndex += len(args)
+	}
+
 	// SELECT clause
 	sqlBuilder.WriteString("SELECT ")
 	if q.distinct {
@@ -440,24 +845,66 @@ func (q *SelectQueryBuilder) ToSql() (query string, params []any, err error) {
 	if len(q.projections) == 0 {
 		sqlBuilder.WriteString("*")
 	} else {
-		sqlBuilder.WriteString(strings.Join(q.projections, ", "))
-		// Collect parameters from projection expressions
-		for _, expr := range q.projectionExprs {
-			if expr != nil {
-				params = append(params, expr.collectParameters()...)
+		// Render the projections and collect the parameters of their expressions
+		args = []any{}
+		columns := make([]string, len(q.projections))
+		for i, expr := range q.projectionExprs {
+			if expr == nil {
+				columns[i] = quoteIdentifier(q.projections[i], q.config.IdentifierQuote)
+
+				continue
+			}
+
+			if err = expr.checkDialect(q.config); err != nil {
+				return "", nil, fmt.Errorf("invalid projection: %w", err)
 			}
+
+			columns[i] = expr.toSQL(q.config)
+			args = append(args, expr.collectParameters()...)
 		}
-		paramIndex = len(params)
+		sqlBuilder.WriteString(numberPlaceholders(strings.Join(columns, ", "), len(args), paramIndex, q.config))
+		params = append(params, args...)
+		paramIndex += len(args)
 	}
 
 	// FROM clause
 	sqlBuilder.WriteString(" FROM ")
-	sqlBuilder.WriteString(quoteIdentifier(q.table, q.config.IdentifierQuote))
+	if q.fromQuery != nil {
+		if q.tableAlias == "" {
+			return "", nil, errors.New("a derived table requires an alias, use As() to set one")
+		}
+
+		fromExpr := SubQuery(q.fromQuery)
+		args = fromExpr.collectParameters()
+		sqlBuilder.WriteString(numberPlaceholders(fromExpr.toBaseSQL(q.config), len(args), paramIndex, q.config))
+		params = append(params, args...)
+		paramIndex += len(args)
+	} else {
+		sqlBuilder.WriteString(quoteIdentifier(q.table, q.config.IdentifierQuote))
+	}
 	if q.tableAlias != "" {
 		sqlBuilder.WriteString(" AS ")
 		sqlBuilder.WriteString(q.tableAlias)
 	}
 
+	// JOIN clauses
+	for _, join := range q.joins {
+		if err = join.conditionExpr.checkDialect(q.config); err != nil {
+			return "", nil, fmt.Errorf("invalid condition for %s %s: %w", join.kind, join.table, err)
+		}
+
+		sqlBuilder.WriteString(" ")
+		sqlBuilder.WriteString(join.kind)
+		sqlBuilder.WriteString(" ")
+		sqlBuilder.WriteString(quoteTableWithAlias(join.table, q.config.IdentifierQuote))
+		if condition := join.conditionSQL(q.config); condition != "" {
+			sqlBuilder.WriteString(" ON ")
+			sqlBuilder.WriteString(numberPlaceholders(condition, len(join.params), paramIndex, q.config))
+			params = append(params, join.params...)
+			paramIndex += len(join.params)
+		}
+	}
+
 	// WHERE clause
 	if sql, args, err = q.sqlerWhere.toSqlWithStartIndex(paramIndex); err != nil {
 		return "", nil, fmt.Errorf("could not build WHERE clause: %w", err)
@@ -489,6 +936,25 @@ func (q *SelectQueryBuilder) ToSql() (query string, params []any, err error) {
 		paramIndex += len(args)
 	}
 
+	// WINDOW clause
+	for i, window := range q.windows {
+		if i == 0 {
+			sqlBuilder.WriteString(" WINDOW ")
+		} else {
+			sqlBuilder.WriteString(", ")
+		}
+
+		if err = window.spec.checkDialect(q.config); err != nil {
+			return "", nil, fmt.Errorf("invalid window %s: %w", window.name, err)
+		}
+
+		args = window.spec.collectParameters()
+		sql = fmt.Sprintf("%s AS (%s)", window.name, window.spec.toSQL(q.config))
+		sqlBuilder.WriteString(numberPlaceholders(sql, len(args), paramIndex, q.config))
+		params = append(params, args...)
+		paramIndex += len(args)
+	}
+
 	// ORDER BY clause
 	if sql, err = q.sqlerOrderBy.ToSql(); err != nil {
 		return "", nil, fmt.Errorf("could not build ORDER BY clause: %w", err)
@@ -511,9 +977,116 @@ func (q *SelectQueryBuilder) ToSql() (query string, params []any, err error) {
 		params = append(params, *q.offsetValue)
 	}
 
+	// Locking clause
+	if sql, err = q.buildLockClause(); err != nil {
+		return "", nil, fmt.Errorf("could not build locking clause: %w", err)
+	}
+	sqlBuilder.WriteString(sql)
+
 	return sqlBuilder.String(), params, nil
 }
 
+// toCompoundSql builds the SQL of a compound query.
+// The parts are rendered with "?" placeholders and numbered afterwards, so the parameter
+// indexes stay continuous across all parts.
+func (q *SelectQueryBuilder) toCompoundSql() (query string, params []any, err error) {
+	if err = q.validateCompound(); err != nil {
+		return "", nil, err
+	}
+
+	var sql string
+	var args []any
+	var sqlBuilder strings.Builder
+	paramIndex := 0 // Track current parameter index for numbered placeholders (0-based)
+
+	// WITH clause
+	if sql, args, err = q.sqlerWith.toSqlWithStartIndex(paramIndex); err != nil {
+		return "", nil, fmt.Errorf("could not build WITH clause: %w", err)
+	}
+	if sql != "" {
+		sqlBuilder.WriteString(sql)
+		sqlBuilder.WriteString(" ")
+		params = append(params, args...)
+		paramIndex += len(args)
+	}
+
+	// Parts combined by their set operators
+	for i, part := range q.setParts {
+		if i > 0 {
+			sqlBuilder.WriteString(" ")
+			sqlBuilder.WriteString(part.operator)
+			sqlBuilder.WriteString(" ")
+		}
+
+		if sql, args, err = SubQuery(part.query).subqueryToSql(q.config); err != nil {
+			return "", nil, fmt.Errorf("could not build part %d of compound query: %w", i, err)
+		}
+
+		// Parts with their own result modifiers would otherwise apply them to the combined result
+		if part.query.isCompound() || part.query.hasResultModifiers() || !part.query.sqlerWith.IsEmpty() {
+			sql = "(" + sql + ")"
+		}
+
+		sqlBuilder.WriteString(numberPlaceholders(sql, len(args), paramIndex, q.config))
+		params = append(params, args...)
+		paramIndex += len(args)
+	}
+
+	// ORDER BY clause
+	if sql, err = q.sqlerOrderBy.ToSql(); err != nil {
+		return "", nil, fmt.Errorf("could not build ORDER BY clause: %w", err)
+	}
+	if sql != "" {
+		sqlBuilder.WriteString(" ORDER BY ")
+		sqlBuilder.WriteString(sql)
+	}
+
+	// LIMIT clause
+	if q.limitValue != nil {
+		sqlBuilder.WriteString(fmt.Sprintf(" LIMIT %s", q.config.PlaceholderFormat(paramIndex)))
+		params = append(params, *q.limitValue)
+		paramIndex++
+	}
+
+	// OFFSET clause
+	if q.offsetValue != nil {
+		sqlBuilder.WriteString(fmt.Sprintf(" OFFSET %s", q.config.PlaceholderFormat(paramIndex)))
+		params = append(params, *q.offsetValue)
+	}
+
+	return sqlBuilder.String(), params, nil
+}
+
+// validateCompound checks that only clauses applying to the combined result are set on a compound query.
+func (q *SelectQueryBuilder) validateCompound() error {
+	var clause string
+
+	switch {
+	case len(q.projections) > 0:
+		clause = "columns"
+	case q.distinct:
+		clause = "DISTINCT"
+	case len(q.joins) > 0:
+		clause = "JOIN"
+	case !q.sqlerWhere.IsEmpty():
+		clause = "WHERE"
+	case !q.sqlerGroupBy.IsEmpty():
+		clause = "GROUP BY"
+	case !q.sqlerHaving.IsEmpty():
+		clause = "HAVING"
+	case len(q.windows) > 0:
+		clause = "WINDOW"
+	case q.tableAlias != "":
+		clause = "an alias"
+	case q.lock != nil:
+		clause = "a locking clause"
+	default:
+		return nil
+	}
+
+	return fmt.Errorf("%s can not be used on a compound query, set it on the individual parts instead", clause)
+}
+
 // Select executes the query and scans all results into the provided destination.
 // The destination should be a pointer to a slice of structs.
 //
@@ -548,6 +1121,10 @@ func (q *SelectQueryBuilder) Select(ctx context.Context, dest any) error {
 		return errors.New("no client set for query execution")
 	}
 
+	if err := q.checkLockClient(ctx); err != nil {
+		return err
+	}
+
 	qb := q
 	if len(q.projections) == 0 {
 		qb = qb.ForType(dest)
@@ -561,7 +1138,7 @@ func (q *SelectQueryBuilder) Select(ctx context.Context, dest any) error {
 		return fmt.Errorf("could not build sql for execution: %w", err)
 	}
 
-	return qb.client.Select(ctx, dest, sql, args...)
+	return qb.client.Select(contextWithBuilder(ctx, qb.client, statementSelect, qb.table), dest, sql, args...)
 }
 
 // Get executes the query and scans exactly one result into the provided destination.
@@ -608,6 +1185,10 @@ func (q *SelectQueryBuilder) Get(ctx context.Context, dest any) error {
 		return errors.New("no client set for query execution")
 	}
 
+	if err := q.checkLockClient(ctx); err != nil {
+		return err
+	}
+
 	qb := q
 	if len(q.projections) == 0 {
 		// Only call ForType for struct destinations
@@ -625,7 +1206,7 @@ func (q *SelectQueryBuilder) Get(ctx context.Context, dest any) error {
 		return fmt.Errorf("could not build sql for execution: %w", err)
 	}
 
-	return qb.client.Get(ctx, dest, sql, args...)
+	return qb.client.Get(contextWithBuilder(ctx, qb.client, statementSelect, qb.table), dest, sql, args...)
 }
 
 // validatePointer checks if the provided value is a pointer.
@@ -0,0 +1,169 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// countedAlias is the alias of the derived table Count and Exists wrap queries into, which can not be rewritten in place.
+const countedAlias = "counted"
+
+// Count returns the number of rows the query would return without its ORDER BY, LIMIT and OFFSET clauses.
+// The projection of a plain query is replaced by COUNT(*). Queries with DISTINCT, GROUP BY, HAVING or
+// set operations are counted as a derived table instead, so the number of distinct rows or groups is returned.
+//
+// Example:
+//
+//	count, err := From("users").
+//		WithClient(client).
+//		Where("status = ?", "active").
+//		OrderBy("name").
+//		Limit(10).
+//		Count(ctx)
+//	// SELECT COUNT(*) FROM `users` WHERE status = ?
+//
+//	count, err := From("orders").
+//		WithClient(client).
+//		Columns("user_id").
+//		GroupBy("user_id").
+//		Count(ctx)
+//	// SELECT COUNT(*) FROM (SELECT `user_id` FROM `orders` GROUP BY `user_id`) AS counted
+func (q *SelectQueryBuilder) Count(ctx context.Context) (int64, error) {
+	var count int64
+
+	if err := q.executeRewritten(ctx, q.countQuery(), &count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Exists returns whether the query would return any row. The ORDER BY, LIMIT and OFFSET clauses are
+// dropped and the query is executed as SELECT 1 ... LIMIT 1. Queries with DISTINCT, GROUP BY, HAVING or set
+// operations are wrapped into a derived table, so their HAVING clause can still refer to the aliases of the projection.
+//
+// Example:
+//
+//	exists, err := From("users").
+//		WithClient(client).
+//		Where("email = ?", email).
+//		Exists(ctx)
+//	// SELECT 1 FROM `users` WHERE email = ? LIMIT ?
+func (q *SelectQueryBuilder) Exists(ctx context.Context) (bool, error) {
+	var one int
+
+	err := q.executeRewritten(ctx, q.existsQuery(), &one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SelectWithTotal executes the query like Select and additionally returns the total number of rows
+// matching the query regardless of its LIMIT and OFFSET, as needed for offset paged responses.
+// The total is counted with a second query (see Count) unless the query is neither limited nor offset.
+//
+// Example:
+//
+//	var users []User
+//	total, err := From("users").
+//		WithClient(client).
+//		Where("status = ?", "active").
+//		OrderBy("name").
+//		Limit(20).
+//		Offset(40).
+//		SelectWithTotal(ctx, &users)
+//	// SELECT `id`, `name`, `email` FROM `users` WHERE status = ? ORDER BY `name` LIMIT ? OFFSET ?
+//	// SELECT COUNT(*) FROM `users` WHERE status = ?
+func (q *SelectQueryBuilder) SelectWithTotal(ctx context.Context, dest any) (int64, error) {
+	if err := q.Select(ctx, dest); err != nil {
+		return 0, err
+	}
+
+	if q.limitValue == nil && q.offsetValue == nil {
+		if items := reflect.ValueOf(dest).Elem(); items.Kind() == reflect.Slice {
+			return int64(items.Len()), nil
+		}
+	}
+
+	total, err := q.Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not count total rows: %w", err)
+	}
+
+	return total, nil
+}
+
+// countQuery rewrites the query to count its rows.
+func (q *SelectQueryBuilder) countQuery() *SelectQueryBuilder {
+	counted := q.withoutResultModifiers()
+	if !counted.needsDerivedTable() {
+		return counted.Columns(Col("*").Count())
+	}
+
+	return q.wrapDerived(counted).Columns(Col("*").Count())
+}
+
+// existsQuery rewrites the query to select a single constant row if it has any.
+func (q *SelectQueryBuilder) existsQuery() *SelectQueryBuilder {
+	probed := q.withoutResultModifiers()
+	if !probed.needsDerivedTable() {
+		return probed.Columns(Literal("1")).Limit(1)
+	}
+
+	return q.wrapDerived(probed).Columns(Literal("1")).Limit(1)
+}
+
+// withoutResultModifiers returns a copy of the query without ORDER BY, LIMIT, OFFSET and locking clause.
+func (q *SelectQueryBuilder) withoutResultModifiers() *SelectQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerOrderBy = NewSqlerOrderBy().WithConfig(q.config)
+	newQuery.limitValue = nil
+	newQuery.offsetValue = nil
+	newQuery.lock = nil
+
+	return newQuery
+}
+
+// needsDerivedTable returns true if replacing the projection of the query would change its number of rows.
+func (q *SelectQueryBuilder) needsDerivedTable() bool {
+	return q.isCompound() || q.distinct || !q.sqlerGroupBy.IsEmpty() || !q.sqlerHaving.IsEmpty()
+}
+
+// wrapDerived returns a query selecting from the given query as derived table.
+// The WITH clause is moved to the outer query, so the CTEs stay at the top level of the statement.
+func (q *SelectQueryBuilder) wrapDerived(inner *SelectQueryBuilder) *SelectQueryBuilder {
+	with := inner.sqlerWith
+	inner.sqlerWith = NewSqlerWith().WithConfig(q.config)
+
+	outer := From(inner).As(countedAlias).WithConfig(q.config)
+	outer.client = q.client
+	outer.sqlerWith = with
+
+	return outer
+}
+
+// executeRewritten executes a query rewritten by Count or Exists and scans the single value into dest.
+func (q *SelectQueryBuilder) executeRewritten(ctx context.Context, rewritten *SelectQueryBuilder, dest any) error {
+	if q.client == nil {
+		return errors.New("no client set for query execution")
+	}
+
+	var err error
+	var sql string
+	var args []any
+
+	if sql, args, err = rewritten.ToSql(); err != nil {
+		return fmt.Errorf("could not build sql for execution: %w", err)
+	}
+
+	return q.client.Get(contextWithBuilder(ctx, q.client, statementSelect, q.table), dest, sql, args...)
+}
@@ -0,0 +1,160 @@
+package sqlc_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gosoline-project/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectCount(t *testing.T) {
+	postgres := &sqlc.QueryBuilderConfig{StructTag: "db", Placeholder: "$", IdentifierQuote: `"`}
+
+	tests := []struct {
+		name  string
+		query *sqlc.SelectQueryBuilder
+		sql   string
+		args  []any
+	}{
+		{
+			name:  "plain",
+			query: sqlc.From("users").Columns("id", "name").Where("status = ?", "active").OrderBy("name").Limit(10).Offset(20),
+			sql:   "SELECT COUNT(*) FROM `users` WHERE status = ?",
+			args:  []any{"active"},
+		},
+		{
+			name:  "distinct",
+			query: sqlc.From("orders").Columns("user_id").Distinct().Where("total > ?", 100),
+			sql:   "SELECT COUNT(*) FROM (SELECT DISTINCT `user_id` FROM `orders` WHERE total > ?) AS counted",
+			args:  []any{100},
+		},
+		{
+			name:  "group by",
+			query: sqlc.From("orders").Columns("user_id").GroupBy("user_id").Having("COUNT(*) > ?", 2).OrderBy("user_id"),
+			sql:   "SELECT COUNT(*) FROM (SELECT `user_id` FROM `orders` GROUP BY `user_id` HAVING COUNT(*) > ?) AS counted",
+			args:  []any{2},
+		},
+		{
+			name:  "compound",
+			query: sqlc.From("customers").Columns("email").Union(sqlc.From("leads").Columns("email")).OrderBy("email").Limit(10),
+			sql:   "SELECT COUNT(*) FROM (SELECT `email` FROM `customers` UNION SELECT `email` FROM `leads`) AS counted",
+		},
+		{
+			name: "with",
+			query: sqlc.From("active_users").
+				With("active_users", sqlc.From("users").Where("status = ?", "active")).
+				Columns("country").
+				Distinct(),
+			sql:  "WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?) SELECT COUNT(*) FROM (SELECT DISTINCT `country` FROM `active_users`) AS counted",
+			args: []any{"active"},
+		},
+		{
+			name:  "postgres",
+			query: sqlc.From("orders").WithConfig(postgres).Columns("user_id").GroupBy("user_id").Having("SUM(total) > ?", 100).Where("status = ?", "paid"),
+			sql:   `SELECT COUNT(*) FROM (SELECT "user_id" FROM "orders" WHERE status = $1 GROUP BY "user_id" HAVING SUM(total) > $2) AS counted`,
+			args:  []any{"paid", 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, sqlMock := newIterateClient(t)
+
+			sqlMock.ExpectQuery(regexp.QuoteMeta(tt.sql)).
+				WithArgs(toDriverValues(tt.args)...).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+			count, err := tt.query.WithClient(client).Count(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), count)
+		})
+	}
+}
+
+func TestSelectExists(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+	query := sqlc.FromG[TestUser]("users").WithClient(client).Where("email = ?", "john@example.com").OrderBy("id")
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM `users` WHERE email = ? LIMIT ?")).
+		WithArgs("john@example.com", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	exists, err := query.Exists(context.Background())
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM `users` WHERE email = ? LIMIT ?")).
+		WithArgs("john@example.com", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	exists, err = query.Exists(context.Background())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestSelectExists_Compound(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM (SELECT `email` FROM `customers` UNION SELECT `email` FROM `leads`) AS counted LIMIT ?")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	exists, err := client.Q().From("customers").Columns("email").Union(sqlc.From("leads").Columns("email")).Exists(context.Background())
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestSelectExists_GroupBy(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM (SELECT `score`, COUNT(`id`) AS cnt FROM `users` GROUP BY `score` HAVING cnt > ?) AS counted LIMIT ?")).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	exists, err := client.Q().From("users").Columns("score", sqlc.Col("id").Count().As("cnt")).GroupBy("score").Having("cnt > ?", 2).Exists(context.Background())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestSelectWithTotalG(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+	query := sqlc.FromG[TestUser]("users").WithClient(client).Where("status = ?", "active").OrderBy("name")
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` WHERE status = ? ORDER BY `name` LIMIT ? OFFSET ?")).
+		WithArgs("active", 2, 2).
+		WillReturnRows(userRows(TestUser{ID: 3, Name: "Jim"}, TestUser{ID: 1, Name: "John"}))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM `users` WHERE status = ?")).
+		WithArgs("active").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	users, total, err := query.Limit(2).Offset(2).SelectWithTotal(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []TestUser{{ID: 3, Name: "Jim"}, {ID: 1, Name: "John"}}, users)
+	assert.Equal(t, int64(5), total)
+
+	// without LIMIT and OFFSET all rows have been selected already, so they are not counted again
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` WHERE status = ? ORDER BY `name`")).
+		WithArgs("active").
+		WillReturnRows(userRows(TestUser{ID: 2, Name: "Jane"}))
+
+	users, total, err = query.SelectWithTotal(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []TestUser{{ID: 2, Name: "Jane"}}, users)
+	assert.Equal(t, int64(1), total)
+}
+
+func toDriverValues(args []any) []driver.Value {
+	values := make([]driver.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg)
+	}
+
+	return values
+}
@@ -0,0 +1,101 @@
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// Iterate executes the query and returns an iterator scanning the result row by row into dest,
+// which must be a pointer to a struct. dest is reset to its zero value and overwritten for every row,
+// so memory stays constant regardless of the size of the result. Errors are yielded once, after
+// which the iteration stops. The rows are closed when the iteration finishes or is stopped early.
+//
+// If no columns have been explicitly set via Columns() or Column(), Iterate will
+// automatically call ForType() to map struct fields to database columns using the
+// `db` struct tag.
+//
+// While iterating inside a transaction, the connection of the transaction is busy and
+// no other statements can be executed on it until the iteration has finished.
+//
+// Example:
+//
+//	var user User
+//	for err := range From("users").WithClient(client).OrderBy("id").Iterate(ctx, &user) {
+//		if err != nil {
+//			return err
+//		}
+//
+//		if err := export(user); err != nil {
+//			return err
+//		}
+//	}
+func (q *SelectQueryBuilder) Iterate(ctx context.Context, dest any) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		rows, err := q.queryRows(ctx, dest)
+		if err != nil {
+			yield(err)
+
+			return
+		}
+
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		elem := reflect.ValueOf(dest).Elem()
+		for rows.Next() {
+			elem.SetZero()
+
+			if err := rows.StructScan(dest); err != nil {
+				yield(fmt.Errorf("could not scan row: %w", err))
+
+				return
+			}
+
+			if !yield(nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(fmt.Errorf("could not iterate rows: %w", err))
+		}
+	}
+}
+
+// queryRows validates dest and executes the query, returning the rows for Iterate.
+func (q *SelectQueryBuilder) queryRows(ctx context.Context, dest any) (*Rows, error) {
+	if err := validatePointer(dest, "Iterate", false); err != nil {
+		return nil, err
+	}
+
+	if kind := reflect.ValueOf(dest).Elem().Kind(); kind != reflect.Struct {
+		return nil, fmt.Errorf("Iterate: destination must be a pointer to a struct, got pointer to %s", kind)
+	}
+
+	if q.client == nil {
+		return nil, errors.New("no client set for query execution")
+	}
+
+	if err := q.checkLockClient(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := q
+	if len(q.projections) == 0 {
+		qb = qb.ForType(dest)
+	}
+
+	var err error
+	var sql string
+	var args []any
+
+	if sql, args, err = qb.ToSql(); err != nil {
+		return nil, fmt.Errorf("could not build sql for execution: %w", err)
+	}
+
+	return qb.client.Query(contextWithBuilder(ctx, qb.client, statementSelect, qb.table), sql, args...)
+}
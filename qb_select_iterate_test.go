@@ -0,0 +1,114 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gosoline-project/sqlc"
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/exec"
+	logmocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIterateClient(t *testing.T) (sqlc.Client, sqlmock.Sqlmock) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	logger := logmocks.NewLoggerMock(logmocks.WithMockAll, logmocks.WithTestingT(t))
+	client := sqlc.NewClientWithInterfaces(logger, sqlx.NewDb(mockDB, "sqlmock"), exec.NewDefaultExecutor(), sqlc.DefaultConfig())
+
+	t.Cleanup(func() {
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	return client, sqlMock
+}
+
+func TestSelectIterate(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+
+	sqlMock.ExpectQuery("SELECT `id`, `name`, `email` FROM `users` ORDER BY `id`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow(1, "John", "john@example.com").
+			AddRow(2, "Jane", "")).
+		RowsWillBeClosed()
+
+	var users []TestUser
+	var user TestUser
+
+	for err := range client.Q().From("users").OrderBy("id").Iterate(context.Background(), &user) {
+		require.NoError(t, err)
+		users = append(users, user)
+	}
+
+	assert.Equal(t, []TestUser{
+		{ID: 1, Name: "John", Email: "john@example.com"},
+		{ID: 2, Name: "Jane"},
+	}, users)
+}
+
+func TestSelectIterate_InvalidDestination(t *testing.T) {
+	client, _ := newIterateClient(t)
+
+	var ids []int
+	for err := range client.Q().From("users").Iterate(context.Background(), &ids) {
+		assert.EqualError(t, err, "Iterate: destination must be a pointer to a struct, got pointer to slice")
+	}
+}
+
+func TestSelectIterateG(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+
+	sqlMock.ExpectQuery("SELECT `id`, `name`, `email` FROM `users` WHERE status = ?").
+		WithArgs("active").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow(1, "John", "john@example.com").
+			AddRow(2, "Jane", "jane@example.com").
+			AddRow(3, "Jim", "jim@example.com")).
+		RowsWillBeClosed()
+
+	var names []string
+	for user, err := range sqlc.FromG[TestUser]("users").WithClient(client).Where("status = ?", "active").Iterate(context.Background()) {
+		require.NoError(t, err)
+		names = append(names, user.Name)
+
+		if len(names) == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"John", "Jane"}, names)
+}
+
+func TestSelectIterateG_Error(t *testing.T) {
+	client, sqlMock := newIterateClient(t)
+	queryErr := errors.New("connection lost")
+
+	sqlMock.ExpectQuery("SELECT `id`, `name`, `email` FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow(1, "John", "john@example.com").
+			AddRow(2, "Jane", "jane@example.com").
+			RowError(1, queryErr)).
+		RowsWillBeClosed()
+
+	var users []TestUser
+	var errs []error
+
+	for user, err := range sqlc.FromG[TestUser]("users").WithClient(client).Iterate(context.Background()) {
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	assert.Equal(t, []TestUser{{ID: 1, Name: "John", Email: "john@example.com"}}, users)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], queryErr)
+}
@@ -0,0 +1,181 @@
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrLockingRequiresTx is returned when a SELECT query with a locking clause is executed
+// with a client which is not bound to a transaction and without an ambient transaction in the context. Outside of a transaction the row locks
+// would be released as soon as the statement finishes, which is almost never intended.
+var ErrLockingRequiresTx = errors.New("row locking clauses require a transaction, execute the query with Tx.Q() or a context carrying the transaction")
+
+const (
+	lockStrengthUpdate = "FOR UPDATE"
+	lockStrengthShare  = "FOR SHARE"
+
+	lockWaitSkipLocked = "SKIP LOCKED"
+	lockWaitNoWait     = "NOWAIT"
+)
+
+// lockClause is the row locking clause of a SELECT query.
+type lockClause struct {
+	strength string   // "FOR UPDATE" or "FOR SHARE", empty if only modifiers have been set so far
+	tables   []string // tables of the OF list, quoted when the query is built
+	wait     string   // "", "SKIP LOCKED" or "NOWAIT"
+}
+
+func (c *lockClause) copy() *lockClause {
+	if c == nil {
+		return nil
+	}
+
+	return &lockClause{
+		strength: c.strength,
+		tables:   append([]string{}, c.tables...),
+		wait:     c.wait,
+	}
+}
+
+// ForUpdate locks the selected rows against concurrent updates, deletes and locking reads
+// until the end of the transaction. Replaces a previously set ForShare().
+// Queries with a locking clause can only be executed inside a transaction.
+//
+// Example:
+//
+//	tx.Q().From("jobs").Where("status = ?", "pending").Limit(10).ForUpdate()
+//	// SELECT * FROM `jobs` WHERE status = ? LIMIT ? FOR UPDATE
+func (q *SelectQueryBuilder) ForUpdate() *SelectQueryBuilder {
+	return q.withLock(func(lock *lockClause) {
+		lock.strength = lockStrengthUpdate
+	})
+}
+
+// ForShare locks the selected rows against concurrent updates and deletes until the end of
+// the transaction, while still allowing other transactions to read them with ForShare().
+// Replaces a previously set ForUpdate().
+// Queries with a locking clause can only be executed inside a transaction.
+//
+// Example:
+//
+//	tx.Q().From("accounts").Where("id = ?", 1).ForShare()
+//	// SELECT * FROM `accounts` WHERE id = ? FOR SHARE
+func (q *SelectQueryBuilder) ForShare() *SelectQueryBuilder {
+	return q.withLock(func(lock *lockClause) {
+		lock.strength = lockStrengthShare
+	})
+}
+
+// SkipLocked skips rows which are locked by another transaction instead of waiting for them.
+// This is the building block of job queue consumers, which can work on disjoint rows concurrently.
+// Replaces a previously set NoWait(). Requires ForUpdate() or ForShare().
+//
+// Example:
+//
+//	tx.Q().From("jobs").Where("status = ?", "pending").OrderBy("id").Limit(10).ForUpdate().SkipLocked()
+//	// SELECT * FROM `jobs` WHERE status = ? ORDER BY `id` LIMIT ? FOR UPDATE SKIP LOCKED
+func (q *SelectQueryBuilder) SkipLocked() *SelectQueryBuilder {
+	return q.withLock(func(lock *lockClause) {
+		lock.wait = lockWaitSkipLocked
+	})
+}
+
+// NoWait makes the query fail immediately if a selected row is locked by another transaction
+// instead of waiting for the lock. Replaces a previously set SkipLocked().
+// Requires ForUpdate() or ForShare().
+//
+// Example:
+//
+//	tx.Q().From("accounts").Where("id = ?", 1).ForUpdate().NoWait()
+//	// SELECT * FROM `accounts` WHERE id = ? FOR UPDATE NOWAIT
+func (q *SelectQueryBuilder) NoWait() *SelectQueryBuilder {
+	return q.withLock(func(lock *lockClause) {
+		lock.wait = lockWaitNoWait
+	})
+}
+
+// Of restricts the locking clause to the rows of the given tables, which is useful
+// for queries joining tables whose rows should not be locked. Use the alias for aliased tables.
+// Replaces a previously set list of tables. Requires ForUpdate() or ForShare().
+//
+// Example:
+//
+//	tx.Q().From("jobs").As("j").Join("queues q", "q.id = j.queue_id").ForUpdate().Of("j")
+//	// SELECT * FROM `jobs` AS j INNER JOIN `queues` AS q ON q.id = j.queue_id FOR UPDATE OF `j`
+func (q *SelectQueryBuilder) Of(tables ...string) *SelectQueryBuilder {
+	return q.withLock(func(lock *lockClause) {
+		lock.tables = append([]string{}, tables...)
+	})
+}
+
+// withLock returns a copy of the query with the modified locking clause.
+func (q *SelectQueryBuilder) withLock(modify func(lock *lockClause)) *SelectQueryBuilder {
+	newQuery := q.copyQuery()
+	if newQuery.lock == nil {
+		newQuery.lock = &lockClause{}
+	}
+
+	modify(newQuery.lock)
+
+	return newQuery
+}
+
+// buildLockClause renders the locking clause including a leading space,
+// or an empty string if the query has none.
+func (q *SelectQueryBuilder) buildLockClause() (string, error) {
+	if q.lock == nil {
+		return "", nil
+	}
+
+	if q.lock.strength == "" {
+		return "", errors.New("SkipLocked(), NoWait() and Of() require ForUpdate() or ForShare()")
+	}
+
+	if err := checkDialectSupport(q.config, q.lock.strength, func(dialect Dialect) bool {
+		return dialect.SupportsRowLocking()
+	}, ""); err != nil {
+		return "", err
+	}
+
+	var sqlBuilder strings.Builder
+	sqlBuilder.WriteString(" ")
+	sqlBuilder.WriteString(q.lock.strength)
+
+	if len(q.lock.tables) > 0 {
+		tables := make([]string, 0, len(q.lock.tables))
+		for _, table := range q.lock.tables {
+			tables = append(tables, quoteIdentifier(table, q.config.IdentifierQuote))
+		}
+
+		sqlBuilder.WriteString(" OF ")
+		sqlBuilder.WriteString(strings.Join(tables, ", "))
+	}
+
+	if q.lock.wait != "" {
+		sqlBuilder.WriteString(" ")
+		sqlBuilder.WriteString(q.lock.wait)
+	}
+
+	return sqlBuilder.String(), nil
+}
+
+// checkLockClient returns ErrLockingRequiresTx if the query has a locking clause
+// but its client is neither bound to a transaction nor a client finding one in ctx.
+func (q *SelectQueryBuilder) checkLockClient(ctx context.Context) error {
+	if q.lock == nil {
+		return nil
+	}
+
+	if _, ok := q.client.(Tx); ok {
+		return nil
+	}
+
+	if c, ok := q.client.(*client); ok {
+		if _, ok := c.txFromContext(ctx); ok {
+			return nil
+		}
+	}
+
+	return ErrLockingRequiresTx
+}
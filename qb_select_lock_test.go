@@ -0,0 +1,84 @@
+package sqlc_test
+
+import (
+	"testing"
+
+	"github.com/gosoline-project/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectForUpdate(t *testing.T) {
+	sql, params, err := sqlc.From("jobs").
+		Where("status = ?", "pending").
+		OrderBy("id").
+		Limit(10).
+		ForUpdate().
+		SkipLocked().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `jobs` WHERE status = ? ORDER BY `id` LIMIT ? FOR UPDATE SKIP LOCKED", sql)
+	assert.Equal(t, []any{"pending", 10}, params)
+
+	sql, params, err = sqlc.From("jobs").
+		WithConfig(postgresConfig()).
+		As("j").
+		Join("queues q", "q.id = j.queue_id AND q.name = ?", "default").
+		ForUpdate().
+		Of("j").
+		NoWait().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "jobs" AS j INNER JOIN "queues" AS q ON q.id = j.queue_id AND q.name = $1 FOR UPDATE OF "j" NOWAIT`, sql)
+	assert.Equal(t, []any{"default"}, params)
+}
+
+func TestSelectForShare(t *testing.T) {
+	sql, _, err := sqlc.From("accounts").WithConfig(mysqlConfig()).Where("id = ?", 1).ForShare().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `accounts` WHERE id = ? FOR SHARE", sql)
+
+	// the later strength and wait policy replace the earlier ones
+	sql, _, err = sqlc.From("accounts").ForUpdate().NoWait().ForShare().SkipLocked().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `accounts` FOR SHARE SKIP LOCKED", sql)
+}
+
+func TestSelectLockImmutability(t *testing.T) {
+	base := sqlc.From("jobs").Where("status = ?", "pending")
+	locked := base.ForUpdate()
+	skipping := locked.SkipLocked()
+
+	sql, _, err := base.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `jobs` WHERE status = ?", sql)
+
+	sql, _, err = locked.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `jobs` WHERE status = ? FOR UPDATE", sql)
+
+	sql, _, err = skipping.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `jobs` WHERE status = ? FOR UPDATE SKIP LOCKED", sql)
+}
+
+func TestSelectLockErrors(t *testing.T) {
+	_, _, err := sqlc.From("jobs").SkipLocked().ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "require ForUpdate() or ForShare()")
+
+	config := &sqlc.QueryBuilderConfig{StructTag: "db", Placeholder: "?", IdentifierQuote: `"`, Dialect: sqlc.NewSqliteDialect()}
+	_, _, err = sqlc.From("jobs").WithConfig(config).ForUpdate().ToSql()
+	assert.ErrorIs(t, err, sqlc.ErrNotSupportedByDialect)
+	assert.Contains(t, err.Error(), "FOR UPDATE is not supported by the SQL dialect sqlite")
+
+	_, _, err = sqlc.From("a").Union(sqlc.From("b")).ForUpdate().ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "a locking clause can not be used on a compound query")
+}
+
+func TestGenericSelectForUpdate(t *testing.T) {
+	sql, _, err := sqlc.FromG[TestUser]("users").Where("id = ?", 1).ForUpdate().Of("users").NoWait().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE id = ? FOR UPDATE OF `users` NOWAIT", sql)
+}
@@ -0,0 +1,419 @@
+package sqlc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned by Paginate if a cursor has been tampered with, was signed with
+// another key or was created for a query with a different ORDER BY clause.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+const (
+	cursorDirectionNext = "next"
+	cursorDirectionPrev = "prev"
+
+	cursorTypeBool   = "b"
+	cursorTypeBytes  = "y"
+	cursorTypeFloat  = "f"
+	cursorTypeInt    = "i"
+	cursorTypeString = "s"
+	cursorTypeTime   = "t"
+)
+
+// PageCursors are the opaque cursors of the pages around a page returned by Paginate.
+// A cursor is empty if there is no page in its direction.
+type PageCursors struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// Page is a page of the entities returned by SelectQueryBuilderG.Paginate.
+type Page[T any] struct {
+	PageCursors
+	Items []T `json:"items"`
+}
+
+// orderColumn is a column of the ORDER BY clause of a paginated query.
+type orderColumn struct {
+	expr string // the quoted column as rendered in the ORDER BY clause
+	name string // the unquoted column name, matching the struct tag of the destination
+	desc bool
+}
+
+// pageCursor is the signed content of a cursor.
+type pageCursor struct {
+	Direction string        `json:"d"`
+	Order     string        `json:"o"`
+	Values    []cursorValue `json:"v"`
+}
+
+// cursorValue is a typed value of an ORDER BY column, so it is bound with its original type again.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// Paginate executes the query as keyset (seek) pagination and scans the page into dest,
+// which must be a pointer to a slice of structs. Instead of an OFFSET, the page is selected by a
+// predicate seeking past the values of the ORDER BY columns stored in the cursor, which stays fast
+// on large tables. Pass an empty cursor to get the first page, and the Next or Prev cursor of the
+// returned PageCursors to get the following or preceding page.
+//
+// The query must be ordered by plain columns, which are mapped to the struct fields of dest by their
+// struct tags and may be mixed ASC and DESC. The columns must not be NULL, and the last column has to
+// be a unique tie-breaker (e.g. the primary key), otherwise rows with equal values can be skipped.
+//
+// Cursors are signed with the CursorKey of the QueryBuilderConfig or of the client (see the cursor_key setting),
+// so callers can neither forge nor modify them. Paginate returns ErrInvalidCursor for such cursors and for
+// cursors of another ORDER BY clause.
+//
+// Example:
+//
+//	var users []User
+//	cursors, err := From("users").
+//		WithClient(client).
+//		Where("status = ?", "active").
+//		OrderBy("created_at DESC", "id DESC").
+//		Paginate(ctx, request.Cursor, 50, &users)
+//	// SELECT `id`, `name`, `created_at` FROM `users` WHERE status = ?
+//	//   AND ((`created_at` < ?) OR (`created_at` = ? AND `id` < ?)) ORDER BY `created_at` DESC, `id` DESC LIMIT ?
+func (q *SelectQueryBuilder) Paginate(ctx context.Context, cursor string, pageSize int, dest any) (*PageCursors, error) {
+	if err := validatePointer(dest, "Paginate", true); err != nil {
+		return nil, err
+	}
+
+	items := reflect.ValueOf(dest).Elem()
+	if items.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Paginate: destination must be a pointer to a slice, got %T", dest)
+	}
+
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("Paginate: page size must be positive, got %d", pageSize)
+	}
+
+	key := q.cursorKey()
+	if len(key) == 0 {
+		return nil, errors.New("Paginate: the QueryBuilderConfig has no CursorKey to sign the cursors with")
+	}
+
+	if q.isCompound() || q.offsetValue != nil {
+		return nil, errors.New("Paginate can not be combined with compound queries or Offset()")
+	}
+
+	columns, order, err := q.orderColumns()
+	if err != nil {
+		return nil, err
+	}
+
+	qb := q
+	backward := false
+
+	if cursor != "" {
+		var decoded *pageCursor
+		if decoded, err = decodeCursor(key, cursor, order, len(columns)); err != nil {
+			return nil, err
+		}
+
+		var values []any
+		if values, err = decoded.values(); err != nil {
+			return nil, err
+		}
+
+		backward = decoded.Direction == cursorDirectionPrev
+		qb = qb.Where(seekPredicate(columns, backward), seekParams(values)...)
+	}
+
+	if backward {
+		qb = qb.OrderBy(reverseOrder(columns)...)
+	}
+
+	items.SetZero()
+
+	if err = qb.Limit(pageSize+1).Select(ctx, dest); err != nil {
+		return nil, err
+	}
+
+	hasMore := items.Len() > pageSize
+	if hasMore {
+		items.Set(items.Slice(0, pageSize))
+	}
+
+	if backward {
+		swap := reflect.Swapper(items.Interface())
+		for i, j := 0, items.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	cursors := &PageCursors{}
+	if items.Len() == 0 {
+		return cursors, nil
+	}
+
+	// going forward there is a preceding page if we came from one, going backward there always is a following page
+	hasNext, hasPrev := hasMore, cursor != ""
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		if cursors.Next, err = q.encodeCursor(key, cursorDirectionNext, order, columns, items.Index(items.Len()-1)); err != nil {
+			return nil, err
+		}
+	}
+
+	if hasPrev {
+		if cursors.Prev, err = q.encodeCursor(key, cursorDirectionPrev, order, columns, items.Index(0)); err != nil {
+			return nil, err
+		}
+	}
+
+	return cursors, nil
+}
+
+// cursorKey returns the CursorKey of the config of the builder. Builders created with From() instead of
+// client.Q() have the default config, so the key of the client they are executed on is used instead.
+func (q *SelectQueryBuilder) cursorKey() []byte {
+	if len(q.config.CursorKey) > 0 {
+		return q.config.CursorKey
+	}
+
+	config := querierConfig(q.client)
+	if config == nil {
+		return nil
+	}
+
+	return config.CursorKey
+}
+
+// orderColumns parses the ORDER BY clause into the columns to seek on.
+// It also returns the clause itself, which cursors are bound to.
+func (q *SelectQueryBuilder) orderColumns() ([]orderColumn, string, error) {
+	order, err := q.sqlerOrderBy.ToSql()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if order == "" {
+		return nil, "", errors.New("Paginate requires an ORDER BY clause")
+	}
+
+	columns := make([]orderColumn, 0, len(q.sqlerOrderBy.clauses))
+	for _, clause := range q.sqlerOrderBy.clauses {
+		parts := strings.Fields(clause)
+		column := orderColumn{expr: parts[0]}
+
+		if len(parts) == 2 && strings.EqualFold(parts[1], "DESC") {
+			column.desc = true
+		} else if len(parts) > 2 || len(parts) == 2 && !strings.EqualFold(parts[1], "ASC") {
+			return nil, "", fmt.Errorf("Paginate requires ORDER BY clauses of a column with an optional ASC or DESC, got %q", clause)
+		}
+
+		segments := strings.Split(column.expr, ".")
+		column.name = strings.Trim(segments[len(segments)-1], "`\"[]")
+
+		if !isPlainIdentifier(column.name) {
+			return nil, "", fmt.Errorf("Paginate requires ORDER BY clauses of a column, got %q", clause)
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns, order, nil
+}
+
+// seekPredicate returns the predicate selecting the rows after the cursor values in the order of the columns,
+// or before them if backward is set, e.g. (a > ?) OR (a = ? AND b < ?) for ORDER BY a ASC, b DESC.
+// It expects the parameters returned by seekParams.
+func seekPredicate(columns []orderColumn, backward bool) string {
+	alternatives := make([]string, 0, len(columns))
+
+	for i, column := range columns {
+		conditions := make([]string, 0, i+1)
+		for _, previous := range columns[:i] {
+			conditions = append(conditions, previous.expr+" = ?")
+		}
+
+		operator := ">"
+		if column.desc != backward {
+			operator = "<"
+		}
+
+		conditions = append(conditions, column.expr+" "+operator+" ?")
+		alternatives = append(alternatives, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// seekParams returns the parameters of the seekPredicate for the cursor values.
+func seekParams(values []any) []any {
+	params := make([]any, 0, len(values)*(len(values)+1)/2)
+	for i := range values {
+		params = append(params, values[:i+1]...)
+	}
+
+	return params
+}
+
+// reverseOrder returns the ORDER BY clauses with inverted directions to read a preceding page.
+func reverseOrder(columns []orderColumn) []any {
+	clauses := make([]any, 0, len(columns))
+	for _, column := range columns {
+		direction := "DESC"
+		if column.desc {
+			direction = "ASC"
+		}
+
+		clauses = append(clauses, column.expr+" "+direction)
+	}
+
+	return clauses
+}
+
+// encodeCursor creates a signed cursor from the values of the ORDER BY columns of the item.
+func (q *SelectQueryBuilder) encodeCursor(key []byte, direction string, order string, columns []orderColumn, item reflect.Value) (string, error) {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.name)
+	}
+
+	values, err := extractValuesFromStruct(item.Interface(), names, q.config.StructTag)
+	if err != nil {
+		return "", fmt.Errorf("could not read the ORDER BY columns of the page: %w", err)
+	}
+
+	cursor := pageCursor{
+		Direction: direction,
+		Order:     order,
+		Values:    make([]cursorValue, 0, len(values)),
+	}
+
+	for i, value := range values {
+		var encoded cursorValue
+		if encoded, err = encodeCursorValue(value); err != nil {
+			return "", fmt.Errorf("could not encode the value of ORDER BY column %s: %w", names[i], err)
+		}
+
+		cursor.Values = append(cursor.Values, encoded)
+	}
+
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(signCursor(key, payload)), nil
+}
+
+// decodeCursor verifies the signature of the cursor and that it belongs to the ORDER BY clause.
+func decodeCursor(key []byte, encoded string, order string, columns int) (*pageCursor, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(encoded, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signCursor(key, payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &pageCursor{}
+	if err = json.Unmarshal(payload, cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if cursor.Order != order || len(cursor.Values) != columns {
+		return nil, fmt.Errorf("%w: the cursor was created for a query ordered by %s", ErrInvalidCursor, cursor.Order)
+	}
+
+	if cursor.Direction != cursorDirectionNext && cursor.Direction != cursorDirectionPrev {
+		return nil, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+func signCursor(key []byte, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
+
+// values returns the cursor values with the types they have been read with.
+func (c *pageCursor) values() ([]any, error) {
+	values := make([]any, 0, len(c.Values))
+
+	for _, value := range c.Values {
+		decoded, err := decodeCursorValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+
+		values = append(values, decoded)
+	}
+
+	return values, nil
+}
+
+func encodeCursorValue(value any) (cursorValue, error) {
+	converted, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return cursorValue{}, err
+	}
+
+	switch v := converted.(type) {
+	case bool:
+		return cursorValue{Type: cursorTypeBool, Value: strconv.FormatBool(v)}, nil
+	case []byte:
+		return cursorValue{Type: cursorTypeBytes, Value: base64.StdEncoding.EncodeToString(v)}, nil
+	case float64:
+		return cursorValue{Type: cursorTypeFloat, Value: strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case int64:
+		return cursorValue{Type: cursorTypeInt, Value: strconv.FormatInt(v, 10)}, nil
+	case string:
+		return cursorValue{Type: cursorTypeString, Value: v}, nil
+	case time.Time:
+		return cursorValue{Type: cursorTypeTime, Value: v.Format(time.RFC3339Nano)}, nil
+	case nil:
+		return cursorValue{}, errors.New("NULL values can not be paginated")
+	default:
+		return cursorValue{}, fmt.Errorf("unsupported type %T", converted)
+	}
+}
+
+func decodeCursorValue(value cursorValue) (any, error) {
+	switch value.Type {
+	case cursorTypeBool:
+		return strconv.ParseBool(value.Value)
+	case cursorTypeBytes:
+		return base64.StdEncoding.DecodeString(value.Value)
+	case cursorTypeFloat:
+		return strconv.ParseFloat(value.Value, 64)
+	case cursorTypeInt:
+		return strconv.ParseInt(value.Value, 10, 64)
+	case cursorTypeString:
+		return value.Value, nil
+	case cursorTypeTime:
+		return time.Parse(time.RFC3339Nano, value.Value)
+	default:
+		return nil, fmt.Errorf("unknown value type %q", value.Type)
+	}
+}
@@ -0,0 +1,179 @@
+package sqlc_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gosoline-project/sqlc"
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/exec"
+	logmocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPaginateClient(t *testing.T, cursorKey string) (sqlc.Client, sqlmock.Sqlmock) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	config := sqlc.DefaultConfig()
+	config.CursorKey = []byte(cursorKey)
+
+	logger := logmocks.NewLoggerMock(logmocks.WithMockAll, logmocks.WithTestingT(t))
+	client := sqlc.NewClientWithInterfaces(logger, sqlx.NewDb(mockDB, "sqlmock"), exec.NewDefaultExecutor(), config)
+
+	t.Cleanup(func() {
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	return client, sqlMock
+}
+
+func userRows(users ...TestUser) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "name", "email"})
+	for _, user := range users {
+		rows.AddRow(user.ID, user.Name, user.Email)
+	}
+
+	return rows
+}
+
+func TestSelectPaginate(t *testing.T) {
+	ctx := context.Background()
+	client, sqlMock := newPaginateClient(t, "secret")
+	query := client.Q().From("users").Where("status = ?", "active").OrderBy("name ASC", "id ASC")
+
+	john := TestUser{ID: 1, Name: "John"}
+	jane := TestUser{ID: 2, Name: "Jane"}
+	jim := TestUser{ID: 3, Name: "Jim"}
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` WHERE status = ? ORDER BY `name` ASC, `id` ASC LIMIT ?")).
+		WithArgs("active", 3).
+		WillReturnRows(userRows(jane, jim, john))
+
+	var first []TestUser
+	cursors, err := query.Paginate(ctx, "", 2, &first)
+	require.NoError(t, err)
+
+	assert.Equal(t, []TestUser{jane, jim}, first)
+	assert.NotEmpty(t, cursors.Next)
+	assert.Empty(t, cursors.Prev)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` WHERE status = ? AND ((`name` > ?) OR (`name` = ? AND `id` > ?)) ORDER BY `name` ASC, `id` ASC LIMIT ?")).
+		WithArgs("active", "Jim", "Jim", int64(3), 3).
+		WillReturnRows(userRows(john))
+
+	var second []TestUser
+	cursors, err = query.Paginate(ctx, cursors.Next, 2, &second)
+	require.NoError(t, err)
+
+	assert.Equal(t, []TestUser{john}, second)
+	assert.Empty(t, cursors.Next)
+	assert.NotEmpty(t, cursors.Prev)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` WHERE status = ? AND ((`name` < ?) OR (`name` = ? AND `id` < ?)) ORDER BY `name` DESC, `id` DESC LIMIT ?")).
+		WithArgs("active", "John", "John", int64(1), 3).
+		WillReturnRows(userRows(jim, jane))
+
+	var previous []TestUser
+	cursors, err = query.Paginate(ctx, cursors.Prev, 2, &previous)
+	require.NoError(t, err)
+
+	assert.Equal(t, []TestUser{jane, jim}, previous)
+	assert.NotEmpty(t, cursors.Next)
+	assert.Empty(t, cursors.Prev)
+}
+
+func TestSelectPaginateG_MixedDirections(t *testing.T) {
+	ctx := context.Background()
+	client, sqlMock := newPaginateClient(t, "secret")
+	query := sqlc.FromG[TestUser]("users").WithClient(client).OrderBy("name DESC", sqlc.Col("id").Asc())
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` ORDER BY `name` DESC, `id` ASC LIMIT ?")).
+		WithArgs(2).
+		WillReturnRows(userRows(TestUser{ID: 4, Name: "Kim"}, TestUser{ID: 2, Name: "Jane"}))
+
+	page, err := query.Paginate(ctx, "", 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, []TestUser{{ID: 4, Name: "Kim"}}, page.Items)
+	require.NotEmpty(t, page.Next)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` WHERE ((`name` < ?) OR (`name` = ? AND `id` > ?)) ORDER BY `name` DESC, `id` ASC LIMIT ?")).
+		WithArgs("Kim", "Kim", int64(4), 2).
+		WillReturnRows(userRows(TestUser{ID: 2, Name: "Jane"}))
+
+	page, err = query.Paginate(ctx, page.Next, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, []TestUser{{ID: 2, Name: "Jane"}}, page.Items)
+	assert.Empty(t, page.Next)
+	assert.NotEmpty(t, page.Prev)
+}
+
+func TestSelectPaginate_InvalidCursor(t *testing.T) {
+	ctx := context.Background()
+	client, sqlMock := newPaginateClient(t, "secret")
+	query := sqlc.FromG[TestUser]("users").WithClient(client).OrderBy("id")
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` ORDER BY `id` LIMIT ?")).
+		WithArgs(2).
+		WillReturnRows(userRows(TestUser{ID: 1, Name: "John"}, TestUser{ID: 2, Name: "Jane"}))
+
+	page, err := query.Paginate(ctx, "", 1)
+	require.NoError(t, err)
+
+	otherClient, _ := newPaginateClient(t, "other secret")
+
+	for name, paginate := range map[string]func() error{
+		"tampered": func() error {
+			_, err := query.Paginate(ctx, "x"+page.Next, 1)
+
+			return err
+		},
+		"malformed": func() error {
+			_, err := query.Paginate(ctx, "not a cursor", 1)
+
+			return err
+		},
+		"other key": func() error {
+			_, err := query.WithClient(otherClient).Paginate(ctx, page.Next, 1)
+
+			return err
+		},
+		"other order": func() error {
+			_, err := query.OrderBy("id DESC").Paginate(ctx, page.Next, 1)
+
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, paginate(), sqlc.ErrInvalidCursor)
+		})
+	}
+}
+
+func TestSelectPaginate_Validation(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newPaginateClient(t, "secret")
+	noKeyClient, _ := newPaginateClient(t, "")
+
+	var users []TestUser
+
+	_, err := client.Q().From("users").Paginate(ctx, "", 10, &users)
+	assert.EqualError(t, err, "Paginate requires an ORDER BY clause")
+
+	_, err = client.Q().From("users").OrderBy(sqlc.Date(sqlc.Col("created_at")).Asc()).Paginate(ctx, "", 10, &users)
+	assert.ErrorContains(t, err, "Paginate requires ORDER BY clauses of a column")
+
+	_, err = client.Q().From("users").OrderBy("id").Offset(10).Paginate(ctx, "", 10, &users)
+	assert.EqualError(t, err, "Paginate can not be combined with compound queries or Offset()")
+
+	_, err = client.Q().From("users").OrderBy("id").Paginate(ctx, "", 0, &users)
+	assert.EqualError(t, err, "Paginate: page size must be positive, got 0")
+
+	_, err = noKeyClient.Q().From("users").OrderBy("id").Paginate(ctx, "", 10, &users)
+	assert.EqualError(t, err, "Paginate: the QueryBuilderConfig has no CursorKey to sign the cursors with")
+}
@@ -2055,3 +2055,315 @@ func TestConfigWithCustomStructTag(t *testing.T) {
 	assert.Equal(t, "SELECT `id`, `name`, `email` FROM `users` WHERE status = $1", sql)
 	assert.Equal(t, []any{"active"}, params)
 }
+
+func TestSelectWithJoins(t *testing.T) {
+	q := sqlc.From("users").As("u").
+		Columns("u.id", "o.total").
+		Join("orders o", "o.user_id = u.id").
+		LeftJoin("addresses AS a", "a.user_id = u.id AND a.kind = ?", "billing").
+		RightJoin("payments p", sqlc.Col("p.order_id").Eq(sqlc.Col("o.id"))).
+		FullJoin("refunds r", "r.payment_id = p.id").
+		CrossJoin("currencies").
+		Where("u.status = ?", "active")
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `u`.`id`, `o`.`total` FROM `users` AS u"+
+		" INNER JOIN `orders` AS o ON o.user_id = u.id"+
+		" LEFT JOIN `addresses` AS a ON a.user_id = u.id AND a.kind = ?"+
+		" RIGHT JOIN `payments` AS p ON `p`.`order_id` = `o`.`id`"+
+		" FULL OUTER JOIN `refunds` AS r ON r.payment_id = p.id"+
+		" CROSS JOIN `currencies`"+
+		" WHERE u.status = ?", sql)
+	assert.Equal(t, []any{"billing", "active"}, params)
+}
+
+func TestSelectJoinWithExpressionParams(t *testing.T) {
+	q := sqlc.From("users").As("u").
+		Join("orders o", sqlc.And(
+			sqlc.Col("o.user_id").Eq(sqlc.Col("u.id")),
+			sqlc.Col("o.amount").Gt(100),
+		))
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT * FROM `users` AS u INNER JOIN `orders` AS o ON (`o`.`user_id` = `u`.`id` AND `o`.`amount` > ?)", sql)
+	assert.Equal(t, []any{100}, params)
+}
+
+func TestSelectJoinUsesConfigOfQuery(t *testing.T) {
+	sql, params, err := sqlc.From("users").As("u").
+		Join("orders o", sqlc.Col("o.user_id").Eq(sqlc.Col("u.id"))).
+		LeftJoin("addresses a", "a.user_id = u.id AND a.kind = ?", "billing").
+		CrossJoin("currencies c").
+		ForUpdate().
+		Of("u").
+		WithConfig(postgresConfig()).
+		ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `SELECT * FROM "users" AS u`+
+		` INNER JOIN "orders" AS o ON "o"."user_id" = "u"."id"`+
+		` LEFT JOIN "addresses" AS a ON a.user_id = u.id AND a.kind = $1`+
+		` CROSS JOIN "currencies" AS c`+
+		` FOR UPDATE OF "u"`, sql)
+	assert.Equal(t, []any{"billing"}, params)
+}
+
+func TestSelectJoinWithInvalidCondition(t *testing.T) {
+	q := sqlc.From("users").
+		Join("orders", 123)
+
+	sql, params, err := q.ToSql()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid type for INNER JOIN condition")
+	assert.Contains(t, err.Error(), "got int")
+	assert.Empty(t, sql)
+	assert.Nil(t, params)
+}
+
+func TestSelectJoinImmutability(t *testing.T) {
+	base := sqlc.From("users").As("u")
+	joined := base.Join("orders o", "o.user_id = u.id")
+
+	sql, _, err := base.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` AS u", sql)
+
+	sql, _, err = joined.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` AS u INNER JOIN `orders` AS o ON o.user_id = u.id", sql)
+}
+
+func TestSelectWithInSubquery(t *testing.T) {
+	sub := sqlc.From("orders").
+		Columns("user_id").
+		Where("total > ?", 100)
+
+	q := sqlc.From("users").
+		Columns("id", "name").
+		Where("status = ?", "active").
+		Where(sqlc.Col("id").In(sub)).
+		Where(sqlc.Col("id").NotIn(sqlc.From("bans").Columns("user_id").Where("active = ?", true)))
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `id`, `name` FROM `users` WHERE status = ? AND `id` IN (SELECT `user_id` FROM `orders` WHERE total > ?) AND `id` NOT IN (SELECT `user_id` FROM `bans` WHERE active = ?)", sql)
+	assert.Equal(t, []any{"active", 100, true}, params)
+}
+
+func TestSelectWithExistsSubquery(t *testing.T) {
+	q := sqlc.From("users").As("u").
+		Where(sqlc.Exists(sqlc.From("orders").As("o").Where("o.user_id = u.id AND o.status = ?", "paid"))).
+		Where(sqlc.NotExists(sqlc.From("bans").As("b").Where("b.user_id = u.id").Limit(1)))
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT * FROM `users` AS u WHERE EXISTS (SELECT * FROM `orders` AS o WHERE o.user_id = u.id AND o.status = ?) AND NOT EXISTS (SELECT * FROM `bans` AS b WHERE b.user_id = u.id LIMIT ?)", sql)
+	assert.Equal(t, []any{"paid", 1}, params)
+}
+
+func TestSelectWithScalarSubqueryColumn(t *testing.T) {
+	orderCount := sqlc.From("orders").
+		Columns(sqlc.Col("*").Count()).
+		Where("orders.user_id = users.id AND orders.status = ?", "paid")
+
+	q := sqlc.From("users").
+		Columns("id", sqlc.SubQuery(orderCount).As("order_count")).
+		Column(sqlc.From("logins").Columns(sqlc.Col("created_at").Max()).Where("logins.user_id = users.id")).
+		Where(sqlc.SubQuery(orderCount).Gt(5))
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `id`, (SELECT COUNT(*) FROM `orders` WHERE orders.user_id = users.id AND orders.status = ?) AS order_count, "+
+		"(SELECT MAX(`created_at`) FROM `logins` WHERE logins.user_id = users.id) "+
+		"FROM `users` WHERE (SELECT COUNT(*) FROM `orders` WHERE orders.user_id = users.id AND orders.status = ?) > ?", sql)
+	assert.Equal(t, []any{"paid", "paid", 5}, params)
+}
+
+func TestSelectFromDerivedTable(t *testing.T) {
+	totals := sqlc.From("orders").
+		Columns("user_id", sqlc.Col("total").Sum().As("total")).
+		Where("status = ?", "paid").
+		GroupBy("user_id")
+
+	q := sqlc.From(totals).As("t").
+		Columns("t.user_id", "t.total").
+		Where("t.total > ?", 1000).
+		Limit(10)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `t`.`user_id`, `t`.`total` FROM (SELECT `user_id`, SUM(`total`) AS total FROM `orders` WHERE status = ? GROUP BY `user_id`) AS t WHERE t.total > ? LIMIT ?", sql)
+	assert.Equal(t, []any{"paid", 1000, 10}, params)
+}
+
+func TestSelectFromDerivedTableRequiresAlias(t *testing.T) {
+	_, _, err := sqlc.From(sqlc.From("orders")).ToSql()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "derived table requires an alias")
+}
+
+func TestSelectWithInvalidSubquery(t *testing.T) {
+	invalid := sqlc.From("orders").Columns(123)
+
+	_, _, err := sqlc.From("users").Where(sqlc.Col("id").In(invalid)).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not build subquery")
+
+	_, _, err = sqlc.From(invalid).As("t").ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid From argument")
+
+	_, _, err = sqlc.From(42).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid type for From argument")
+}
+
+func TestSelectWithSubqueryUsesOuterConfig(t *testing.T) {
+	pgConfig := &sqlc.QueryBuilderConfig{
+		StructTag:       "db",
+		Placeholder:     "$",
+		IdentifierQuote: `"`,
+		Dialect:         sqlc.NewPostgresDialect(),
+	}
+
+	// the subqueries are built with the default config, as in the examples of From
+	recent := sqlc.From("orders").Columns("user_id").Where("created_at > ?", "2024-01-01")
+
+	q := sqlc.From("users").WithConfig(pgConfig).
+		Columns("id", sqlc.SubQuery(sqlc.From("logins").Columns(sqlc.Col("*").Count()).Where("logins.user_id = users.id AND success = ?", true)).As("logins")).
+		Where(sqlc.Col("id").In(recent)).
+		Where("status = ?", "active")
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `SELECT "id", (SELECT COUNT(*) FROM "logins" WHERE logins.user_id = users.id AND success = $1) AS logins `+
+		`FROM "users" WHERE "id" IN (SELECT "user_id" FROM "orders" WHERE created_at > $2) AND status = $3`, sql)
+	assert.Equal(t, []any{true, "2024-01-01", "active"}, params)
+
+	union, _, err := sqlc.From("customers").WithConfig(pgConfig).Columns("email").Union(sqlc.From("leads").Columns("email")).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "email" FROM "customers" UNION SELECT "email" FROM "leads"`, union)
+}
+
+func TestSelectSetOperations(t *testing.T) {
+	customers := sqlc.From("customers").Columns("email").Where("active = ?", true)
+	leads := sqlc.From("leads").Columns("email").Where("source = ?", "web")
+	blocked := sqlc.From("blocked").Columns("email")
+
+	tests := []struct {
+		name     string
+		query    *sqlc.SelectQueryBuilder
+		expected string
+	}{
+		{
+			name:     "union",
+			query:    customers.Union(leads),
+			expected: "SELECT `email` FROM `customers` WHERE active = ? UNION SELECT `email` FROM `leads` WHERE source = ?",
+		},
+		{
+			name:     "union all",
+			query:    customers.UnionAll(leads),
+			expected: "SELECT `email` FROM `customers` WHERE active = ? UNION ALL SELECT `email` FROM `leads` WHERE source = ?",
+		},
+		{
+			name:     "intersect",
+			query:    customers.Intersect(leads),
+			expected: "SELECT `email` FROM `customers` WHERE active = ? INTERSECT SELECT `email` FROM `leads` WHERE source = ?",
+		},
+		{
+			name:     "except",
+			query:    customers.Except(leads),
+			expected: "SELECT `email` FROM `customers` WHERE active = ? EXCEPT SELECT `email` FROM `leads` WHERE source = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, params, err := tt.query.ToSql()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, sql)
+			assert.Equal(t, []any{true, "web"}, params)
+		})
+	}
+
+	t.Run("chained operators", func(t *testing.T) {
+		sql, params, err := customers.Union(leads).Except(blocked).ToSql()
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT `email` FROM `customers` WHERE active = ? UNION SELECT `email` FROM `leads` WHERE source = ? EXCEPT SELECT `email` FROM `blocked`", sql)
+		assert.Equal(t, []any{true, "web"}, params)
+	})
+}
+
+func TestSelectSetOperationWithResultModifiers(t *testing.T) {
+	latest := sqlc.From("orders_2024").Columns("id", "total").OrderBy(sqlc.Col("total").Desc()).Limit(5)
+
+	q := sqlc.From("orders_2025").Columns("id", "total").
+		UnionAll(latest).
+		OrderBy("id").
+		Limit(10).
+		Offset(20)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT `id`, `total` FROM `orders_2025` UNION ALL (SELECT `id`, `total` FROM `orders_2024` ORDER BY `total` DESC LIMIT ?) ORDER BY `id` LIMIT ? OFFSET ?", sql)
+	assert.Equal(t, []any{5, 10, 20}, params)
+
+	// after a result modifier the compound query becomes the first part of a new one
+	sql, params, err = q.Union(sqlc.From("orders_archive").Columns("id", "total")).ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "(SELECT `id`, `total` FROM `orders_2025` UNION ALL (SELECT `id`, `total` FROM `orders_2024` ORDER BY `total` DESC LIMIT ?) ORDER BY `id` LIMIT ? OFFSET ?) UNION SELECT `id`, `total` FROM `orders_archive`", sql)
+	assert.Equal(t, []any{5, 10, 20}, params)
+}
+
+func TestSelectSetOperationImmutability(t *testing.T) {
+	base := sqlc.From("customers").Columns("email")
+	union := base.Union(sqlc.From("leads").Columns("email"))
+	_ = union.Union(sqlc.From("partners").Columns("email"))
+
+	sql, _, err := base.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `email` FROM `customers`", sql)
+
+	sql, _, err = union.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `email` FROM `customers` UNION SELECT `email` FROM `leads`", sql)
+}
+
+func TestSelectSetOperationErrors(t *testing.T) {
+	union := sqlc.From("customers").Columns("email").Union(sqlc.From("leads").Columns("email"))
+
+	_, _, err := union.Where("email LIKE ?", "%@example.com").ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "WHERE can not be used on a compound query")
+
+	_, _, err = union.Columns("email").ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "columns can not be used on a compound query")
+
+	_, _, err = sqlc.From("customers").Union().ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "UNION requires at least one query")
+
+	_, _, err = sqlc.From("customers").Union(nil).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "query must not be nil")
+
+	_, _, err = sqlc.From("customers").Union(sqlc.From("leads").Columns(1)).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not build part 1 of compound query")
+}
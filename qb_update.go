@@ -37,16 +37,18 @@ import (
 //	updates := map[string]any{"name": "John", "email": "john@example.com"}
 //	result, err := Update("users").SetMap(updates).Where("id = ?", 1).Exec(ctx)
 type UpdateQueryBuilder struct {
-	client       Querier
-	table        string
-	sets         []Assignment
-	record       any            // Store record for value extraction
-	setMap       map[string]any // Store map for value extraction
-	sqlerWhere   *SqlerWhere
-	sqlerOrderBy *SqlerOrderBy
-	limitValue   *int
-	config       *QueryBuilderConfig // Configuration for struct tags and placeholders
-	err          error
+	client         Querier
+	table          string
+	sets           []Assignment
+	record         any            // Store record for value extraction
+	setMap         map[string]any // Store map for value extraction
+	sqlerWith      *SqlerWith
+	sqlerWhere     *SqlerWhere
+	sqlerOrderBy   *SqlerOrderBy
+	sqlerReturning *SqlerReturning
+	limitValue     *int
+	config         *QueryBuilderConfig // Configuration for struct tags and placeholders
+	err            error
 }
 
 // Update creates a new UpdateQueryBuilder for the specified table.
@@ -59,11 +61,13 @@ type UpdateQueryBuilder struct {
 func Update(table string) *UpdateQueryBuilder {
 	cfg := DefaultConfig()
 	return &UpdateQueryBuilder{
-		table:        table,
-		sets:         []Assignment{},
-		sqlerWhere:   NewSqlerWhere().WithConfig(cfg),
-		sqlerOrderBy: NewSqlerOrderBy().WithConfig(cfg),
-		config:       cfg,
+		table:          table,
+		sets:           []Assignment{},
+		sqlerWith:      NewSqlerWith().WithConfig(cfg),
+		sqlerWhere:     NewSqlerWhere().WithConfig(cfg),
+		sqlerOrderBy:   NewSqlerOrderBy().WithConfig(cfg),
+		sqlerReturning: NewSqlerReturning().WithConfig(cfg),
+		config:         cfg,
 	}
 }
 
@@ -96,15 +100,17 @@ func (q *UpdateQueryBuilder) copyQuery() *UpdateQueryBuilder {
 	}
 
 	newQuery := &UpdateQueryBuilder{
-		client:       q.client,
-		table:        q.table,
-		sets:         append([]Assignment{}, q.sets...),
-		record:       q.record,
-		setMap:       newSetMap,
-		sqlerWhere:   newSqlerWhere,
-		sqlerOrderBy: newSqlerOrderBy,
-		config:       q.config,
-		err:          q.err,
+		client:         q.client,
+		table:          q.table,
+		sets:           append([]Assignment{}, q.sets...),
+		record:         q.record,
+		setMap:         newSetMap,
+		sqlerWith:      q.sqlerWith.copy(),
+		sqlerWhere:     newSqlerWhere,
+		sqlerOrderBy:   newSqlerOrderBy,
+		sqlerReturning: q.sqlerReturning.copy(),
+		config:         q.config,
+		err:            q.err,
 	}
 
 	if q.limitValue != nil {
@@ -140,8 +146,10 @@ func (q *UpdateQueryBuilder) WithClient(client Querier) *UpdateQueryBuilder {
 func (q *UpdateQueryBuilder) WithConfig(config *QueryBuilderConfig) *UpdateQueryBuilder {
 	newQuery := q.copyQuery()
 	newQuery.config = config
+	newQuery.sqlerWith.WithConfig(config)
 	newQuery.sqlerWhere.WithConfig(config)
 	newQuery.sqlerOrderBy.WithConfig(config)
+	newQuery.sqlerReturning.WithConfig(config)
 
 	return newQuery
 }
@@ -242,6 +250,42 @@ func (q *UpdateQueryBuilder) SetRecord(record any) *UpdateQueryBuilder {
 	return newQuery
 }
 
+// With adds a common table expression which is rendered in a WITH clause before the UPDATE.
+// The optional columns are rendered as the column list of the CTE.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	Update("users").
+//		With("inactive", From("users").Columns("id").Where("last_login < ?", cutoff)).
+//		Set("status", "inactive").
+//		Where("id IN (SELECT id FROM inactive)")
+//	// WITH `inactive` AS (SELECT `id` FROM `users` WHERE last_login < ?) UPDATE `users` SET `status` = ? WHERE id IN (SELECT id FROM inactive)
+func (q *UpdateQueryBuilder) With(name string, query *SelectQueryBuilder, columns ...string) *UpdateQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.With(name, query, columns...)
+
+	if newQuery.sqlerWith.err != nil {
+		newQuery.err = newQuery.sqlerWith.err
+	}
+
+	return newQuery
+}
+
+// WithRecursive adds a recursive common table expression which is rendered in a WITH RECURSIVE clause.
+// The query may reference the CTE by its name.
+// Returns a new query builder with the CTE added.
+func (q *UpdateQueryBuilder) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *UpdateQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.WithRecursive(name, query, columns...)
+
+	if newQuery.sqlerWith.err != nil {
+		newQuery.err = newQuery.sqlerWith.err
+	}
+
+	return newQuery
+}
+
 // Where adds a WHERE condition to the query.
 // Multiple Where() calls are combined with AND.
 // Accepts either:
@@ -312,6 +356,25 @@ func (q *UpdateQueryBuilder) Limit(limit int) *UpdateQueryBuilder {
 	return newQuery
 }
 
+// Returning adds a RETURNING clause, which makes the query return the given columns of the updated rows.
+// Replaces any previously set RETURNING columns. Use ExecReturning() to execute the query and scan the rows.
+// RETURNING is supported by PostgreSQL, queries built for a MySQL client fail with ErrReturningNotSupported.
+// Returns a new query builder with the RETURNING clause set.
+//
+// Example:
+//
+//	Update("users").
+//		Set("status", "active").
+//		Where("id = ?", 1).
+//		Returning("id", "updated_at")
+//	// UPDATE "users" SET "status" = $1 WHERE id = $2 RETURNING "id", "updated_at"
+func (q *UpdateQueryBuilder) Returning(cols ...string) *UpdateQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerReturning.Returning(cols...)
+
+	return newQuery
+}
+
 // ToSql generates the final SQL UPDATE query string with positional parameters.
 // Returns the SQL string, parameters slice, and any error encountered during building.
 //
@@ -397,6 +460,19 @@ func (q *UpdateQueryBuilder) buildUpdateSql(assignments []Assignment) (query str
 
 	var sql strings.Builder
 
+	// WITH clause
+	var withSQL string
+	var withArgs []any
+	if withSQL, withArgs, err = q.sqlerWith.toSqlWithStartIndex(paramIndex); err != nil {
+		return "", nil, fmt.Errorf("could not build WITH clause: %w", err)
+	}
+	if withSQL != "" {
+		sql.WriteString(withSQL)
+		sql.WriteString(" ")
+		params = append(params, withArgs...)
+		paramIndex += len(withArgs)
+	}
+
 	// UPDATE clause
 	sql.WriteString("UPDATE ")
 	sql.WriteString(quoteIdentifier(q.table, q.config.IdentifierQuote))
@@ -439,16 +515,34 @@ func (q *UpdateQueryBuilder) buildUpdateSql(assignments []Assignment) (query str
 		return "", nil, fmt.Errorf("could not build ORDER BY clause: %w", err)
 	}
 	if orderSQL != "" {
+		if err = checkDialectSupport(q.config, "ORDER BY on UPDATE", Dialect.SupportsUpdateOrderByLimit, "restrict the rows with a subquery in WHERE instead"); err != nil {
+			return "", nil, err
+		}
+
 		sql.WriteString(" ORDER BY ")
 		sql.WriteString(orderSQL)
 	}
 
 	// LIMIT clause
 	if q.limitValue != nil {
+		if err = checkDialectSupport(q.config, "LIMIT on UPDATE", Dialect.SupportsUpdateOrderByLimit, "restrict the rows with a subquery in WHERE instead"); err != nil {
+			return "", nil, err
+		}
+
 		sql.WriteString(fmt.Sprintf(" LIMIT %s", q.config.PlaceholderFormat(paramIndex)))
 		params = append(params, *q.limitValue)
 	}
 
+	// RETURNING clause
+	var returningSQL string
+	if returningSQL, err = q.sqlerReturning.ToSql(); err != nil {
+		return "", nil, fmt.Errorf("could not build RETURNING clause: %w", err)
+	}
+	if returningSQL != "" {
+		sql.WriteString(" RETURNING ")
+		sql.WriteString(returningSQL)
+	}
+
 	return sql.String(), params, nil
 }
 
@@ -499,5 +593,45 @@ func (q *UpdateQueryBuilder) Exec(ctx context.Context) (Result, error) {
 		return nil, fmt.Errorf("could not build sql for execution: %w", err)
 	}
 
-	return q.client.Exec(ctx, sql, args...)
+	return q.client.Exec(contextWithBuilder(ctx, q.client, statementUpdate, q.table), sql, args...)
+}
+
+// ExecReturning executes the update query and scans the rows returned by the RETURNING clause into dest.
+// The destination should be a pointer to a slice of structs.
+// If no columns have been set via Returning(), the columns are derived from the struct tags of dest.
+// Returns ErrReturningNotSupported if the dialect of the client does not support RETURNING (e.g. MySQL).
+// Requires that a client has been set via WithClient().
+//
+// Example:
+//
+//	var updated []User
+//	err := Update("users").
+//		WithClient(client).
+//		Set("status", "active").
+//		Where("last_login > ?", cutoff).
+//		ExecReturning(ctx, &updated)
+func (q *UpdateQueryBuilder) ExecReturning(ctx context.Context, dest any) error {
+	if err := validatePointer(dest, "ExecReturning", true); err != nil {
+		return err
+	}
+
+	if q.client == nil {
+		return errors.New("no client set for query execution")
+	}
+
+	qb := q
+	if qb.sqlerReturning.IsEmpty() {
+		qb = qb.Returning(returningColumnsForType(dest, q.config)...)
+	}
+
+	var err error
+	var sql string
+	var args []any
+
+	if sql, args, err = qb.ToSql(); err != nil {
+		return fmt.Errorf("could not build sql for execution: %w", err)
+	}
+
+	// the statement is a write returning rows, so it must not be sent to a replica like other selects
+	return qb.client.Select(contextWithBuilder(ContextWithPrimary(ctx), qb.client, statementUpdate, qb.table), dest, sql, args...)
 }
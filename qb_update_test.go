@@ -8,6 +8,7 @@ import (
 	"github.com/gosoline-project/sqlc"
 	mocks "github.com/gosoline-project/sqlc/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -509,3 +510,40 @@ func TestUpdateWithMultipleOrderBy(t *testing.T) {
 	assert.Equal(t, "UPDATE `users` SET `processed` = ? WHERE processed = ? ORDER BY `priority` DESC, `created_at` ASC LIMIT ?", sql)
 	assert.Equal(t, []any{true, false, 50}, params)
 }
+
+func TestUpdateReturning(t *testing.T) {
+	config := postgresConfig()
+
+	sql, params, err := sqlc.Update("users").
+		WithConfig(config).
+		Set("status", "active").
+		Where("id = ?", 1).
+		Returning("id", "updated_at").
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "users" SET "status" = $1 WHERE id = $2 RETURNING "id", "updated_at"`, sql)
+	assert.Equal(t, []any{"active", 1}, params)
+}
+
+func TestUpdateExecReturning(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mocks.NewClient(t)
+
+	mockClient.EXPECT().
+		Select(mock.Anything, mock.Anything, "UPDATE `users` SET `status` = ? WHERE id > ? RETURNING `id`, `name`, `email`", mock.Anything).
+		RunAndReturn(func(ctx context.Context, dest any, query string, args ...any) error {
+			assert.Equal(t, []any{"active", 10}, args)
+			*dest.(*[]TestUser) = []TestUser{{ID: 11}, {ID: 12}}
+
+			return nil
+		})
+
+	users, err := sqlc.UpdateG[TestUser]("users").
+		WithClient(mockClient).
+		Set("status", "active").
+		Where("id > ?", 10).
+		ExecReturning(ctx)
+
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+}
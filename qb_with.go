@@ -0,0 +1,227 @@
+package sqlc
+
+// WithQueryBuilder collects common table expressions (CTEs) and is the entry point for
+// building SELECT, UPDATE, DELETE and INSERT ... SELECT queries prefixed with a WITH clause.
+// It implements an immutable builder pattern - each method returns a new instance
+// rather than modifying the receiver.
+//
+// Example usage:
+//
+//	query := With("active_users", From("users").Where("status = ?", "active")).
+//		From("active_users").
+//		Columns("id", "name")
+//	sql, args, err := query.ToSql()
+//	// sql: "WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?) SELECT `id`, `name` FROM `active_users`"
+type WithQueryBuilder struct {
+	client    Querier
+	config    *QueryBuilderConfig
+	sqlerWith *SqlerWith
+}
+
+// With creates a new WithQueryBuilder with a single common table expression.
+// Each CTE is itself built with From(). The optional columns are rendered as the column list of the CTE.
+//
+// Example:
+//
+//	With("active_users", From("users").Where("status = ?", "active")).
+//		From("active_users")
+//	// WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?) SELECT * FROM `active_users`
+func With(name string, query *SelectQueryBuilder, columns ...string) *WithQueryBuilder {
+	cfg := DefaultConfig()
+
+	return &WithQueryBuilder{
+		config:    cfg,
+		sqlerWith: NewSqlerWith().WithConfig(cfg).With(name, query, columns...),
+	}
+}
+
+// WithRecursive creates a new WithQueryBuilder with a single recursive common table expression.
+// The query may reference the CTE by its name, which is used for hierarchical data like trees.
+//
+// Example:
+//
+//	anchor := From("categories").Columns("id", "parent_id").Where("id = ?", 1)
+//	children := From("categories").As("c").Columns("c.id", "c.parent_id").Join("tree t", "c.parent_id = t.id")
+//	WithRecursive("tree", anchor.UnionAll(children), "id", "parent_id").
+//		From("tree")
+//	// WITH RECURSIVE `tree` (`id`, `parent_id`) AS (SELECT ... UNION ALL SELECT ...) SELECT * FROM `tree`
+func WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *WithQueryBuilder {
+	cfg := DefaultConfig()
+
+	return &WithQueryBuilder{
+		config:    cfg,
+		sqlerWith: NewSqlerWith().WithConfig(cfg).WithRecursive(name, query, columns...),
+	}
+}
+
+// copyQuery creates a shallow copy of the query builder.
+// This is used internally to implement the immutable builder pattern.
+func (q *WithQueryBuilder) copyQuery() *WithQueryBuilder {
+	return &WithQueryBuilder{
+		client:    q.client,
+		config:    q.config,
+		sqlerWith: q.sqlerWith.copy(),
+	}
+}
+
+// WithClient associates a database client with the query builder.
+// The client is passed on to the builders created by From, Update, Delete and Into.
+// Returns a new query builder with the client attached.
+func (q *WithQueryBuilder) WithClient(client Querier) *WithQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.client = client
+
+	return newQuery
+}
+
+// WithConfig sets a custom configuration for the query builder.
+// The config is passed on to the builders created by From, Update, Delete and Into.
+// Returns a new query builder with the config attached.
+func (q *WithQueryBuilder) WithConfig(config *QueryBuilderConfig) *WithQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.config = config
+	newQuery.sqlerWith.WithConfig(config)
+
+	return newQuery
+}
+
+// With adds another common table expression.
+// Later CTEs may reference the ones added before.
+// Returns a new query builder with the CTE added.
+//
+// Example:
+//
+//	With("paid", From("orders").Where("status = ?", "paid")).
+//		With("totals", From("paid").Columns("user_id", Col("total").Sum().As("total")).GroupBy("user_id")).
+//		From("totals")
+func (q *WithQueryBuilder) With(name string, query *SelectQueryBuilder, columns ...string) *WithQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.With(name, query, columns...)
+
+	return newQuery
+}
+
+// WithRecursive adds another recursive common table expression.
+// Returns a new query builder with the CTE added.
+func (q *WithQueryBuilder) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *WithQueryBuilder {
+	newQuery := q.copyQuery()
+	newQuery.sqlerWith.WithRecursive(name, query, columns...)
+
+	return newQuery
+}
+
+// From creates a new SelectQueryBuilder prefixed with the collected CTEs.
+//
+// Example:
+//
+//	With("active_users", From("users").Where("status = ?", "active")).
+//		From("active_users").
+//		Where("age > ?", 18)
+//	// WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?) SELECT * FROM `active_users` WHERE age > ?
+func (q *WithQueryBuilder) From(table any) *SelectQueryBuilder {
+	builder := From(table).WithConfig(q.config)
+	builder.sqlerWith = q.sqlerWith.copy().WithConfig(q.config)
+	builder.err = firstError(builder.err, builder.sqlerWith.err)
+
+	if q.client != nil {
+		builder = builder.WithClient(q.client)
+	}
+
+	return builder
+}
+
+// Update creates a new UpdateQueryBuilder prefixed with the collected CTEs.
+//
+// Example:
+//
+//	With("inactive", From("users").Columns("id").Where("last_login < ?", cutoff)).
+//		Update("users").
+//		Set("status", "inactive").
+//		Where("id IN (SELECT id FROM inactive)")
+func (q *WithQueryBuilder) Update(table string) *UpdateQueryBuilder {
+	builder := Update(table).WithConfig(q.config)
+	builder.sqlerWith = q.sqlerWith.copy().WithConfig(q.config)
+	builder.err = firstError(builder.err, builder.sqlerWith.err)
+
+	if q.client != nil {
+		builder = builder.WithClient(q.client)
+	}
+
+	return builder
+}
+
+// Delete creates a new DeleteQueryBuilder prefixed with the collected CTEs.
+//
+// Example:
+//
+//	With("inactive", From("users").Columns("id").Where("last_login < ?", cutoff)).
+//		Delete("sessions").
+//		Where("user_id IN (SELECT id FROM inactive)")
+func (q *WithQueryBuilder) Delete(table string) *DeleteQueryBuilder {
+	builder := Delete(table).WithConfig(q.config)
+	builder.sqlerWith = q.sqlerWith.copy().WithConfig(q.config)
+	builder.err = firstError(builder.err, builder.sqlerWith.err)
+
+	if q.client != nil {
+		builder = builder.WithClient(q.client)
+	}
+
+	return builder
+}
+
+// Into creates a new InsertQueryBuilder for an INSERT ... SELECT query prefixed with the collected CTEs.
+// The source query has to be set via FromSelect().
+//
+// Example:
+//
+//	With("paid", From("orders").Where("status = ?", "paid")).
+//		Into("paid_orders").
+//		FromSelect(From("paid"))
+//	// INSERT INTO `paid_orders` WITH `paid` AS (SELECT * FROM `orders` WHERE status = ?) SELECT * FROM `paid`
+func (q *WithQueryBuilder) Into(table string) *InsertQueryBuilder {
+	builder := Into(table).WithConfig(q.config)
+	builder.sqlerWith = q.sqlerWith.copy().WithConfig(q.config)
+	builder.err = firstError(builder.err, builder.sqlerWith.err)
+
+	if q.client != nil {
+		builder = builder.WithClient(q.client)
+	}
+
+	return builder
+}
+
+// With creates a new WithQueryBuilder with the client already attached.
+// This is a convenience method that combines With() and WithClient() into a single call.
+//
+// Example:
+//
+//	qb := &QueryBuilder{client: myClient}
+//	query := qb.With("active_users", qb.From("users").Where("status = ?", "active")).From("active_users")
+func (q *QueryBuilder) With(name string, query *SelectQueryBuilder, columns ...string) *WithQueryBuilder {
+	builder := With(name, query, columns...).WithClient(q.client)
+	if q.config != nil {
+		builder = builder.WithConfig(q.config)
+	}
+	return builder
+}
+
+// WithRecursive creates a new WithQueryBuilder for a recursive CTE with the client already attached.
+// This is a convenience method that combines WithRecursive() and WithClient() into a single call.
+func (q *QueryBuilder) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *WithQueryBuilder {
+	builder := WithRecursive(name, query, columns...).WithClient(q.client)
+	if q.config != nil {
+		builder = builder.WithConfig(q.config)
+	}
+	return builder
+}
+
+// firstError returns the first non-nil error.
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
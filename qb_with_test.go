@@ -0,0 +1,181 @@
+package sqlc_test
+
+import (
+	"testing"
+
+	"github.com/gosoline-project/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSelect(t *testing.T) {
+	q := sqlc.With("active_users", sqlc.From("users").Where("status = ?", "active")).
+		With("totals", sqlc.From("orders").Columns("user_id", sqlc.Col("total").Sum().As("total")).GroupBy("user_id"), "user_id", "total").
+		From("active_users").As("u").
+		Columns("u.id", "t.total").
+		Join("totals t", "t.user_id = u.id").
+		Where("t.total > ?", 100)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?), "+
+		"`totals` (`user_id`, `total`) AS (SELECT `user_id`, SUM(`total`) AS total FROM `orders` GROUP BY `user_id`) "+
+		"SELECT `u`.`id`, `t`.`total` FROM `active_users` AS u INNER JOIN `totals` AS t ON t.user_id = u.id WHERE t.total > ?", sql)
+	assert.Equal(t, []any{"active", 100}, params)
+}
+
+func TestWithRecursiveSelect(t *testing.T) {
+	q := sqlc.From("tree").
+		WithRecursive("tree", sqlc.From("categories").Columns("id", "parent_id").Where("id = ?", 1), "id", "parent_id").
+		Columns("id")
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "WITH RECURSIVE `tree` (`id`, `parent_id`) AS (SELECT `id`, `parent_id` FROM `categories` WHERE id = ?) SELECT `id` FROM `tree`", sql)
+	assert.Equal(t, []any{1}, params)
+}
+
+func TestWithUpdate(t *testing.T) {
+	q := sqlc.With("inactive", sqlc.From("users").Columns("id").Where("last_login < ?", "2020-01-01")).
+		Update("users").
+		Set("status", "inactive").
+		Where("id IN (SELECT id FROM inactive)").
+		Where("status != ?", "banned")
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "WITH `inactive` AS (SELECT `id` FROM `users` WHERE last_login < ?) UPDATE `users` SET `status` = ? WHERE id IN (SELECT id FROM inactive) AND status != ?", sql)
+	assert.Equal(t, []any{"2020-01-01", "inactive", "banned"}, params)
+}
+
+func TestWithDelete(t *testing.T) {
+	q := sqlc.Delete("sessions").
+		With("inactive", sqlc.From("users").Columns("id").Where("last_login < ?", "2020-01-01")).
+		Where("user_id IN (SELECT id FROM inactive)")
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "WITH `inactive` AS (SELECT `id` FROM `users` WHERE last_login < ?) DELETE FROM `sessions` WHERE user_id IN (SELECT id FROM inactive)", sql)
+	assert.Equal(t, []any{"2020-01-01"}, params)
+}
+
+func TestWithInsertSelect(t *testing.T) {
+	q := sqlc.With("paid", sqlc.From("orders").Where("status = ?", "paid")).
+		Into("daily_totals").
+		Columns("day", "total").
+		FromSelect(sqlc.From("paid").Columns("day", sqlc.Col("total").Sum()).Where("total > ?", 0).GroupBy("day"))
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "INSERT INTO `daily_totals` (`day`, `total`) WITH `paid` AS (SELECT * FROM `orders` WHERE status = ?) SELECT `day`, SUM(`total`) FROM `paid` WHERE total > ? GROUP BY `day`", sql)
+	assert.Equal(t, []any{"paid", 0}, params)
+}
+
+func TestInsertFromSelectWithoutColumns(t *testing.T) {
+	q := sqlc.Into("archived_orders").
+		FromSelect(sqlc.From("orders").Where("created_at < ?", "2020-01-01")).
+		OnDuplicateKeyUpdate(sqlc.AssignExpr("total", "VALUES(total)"))
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "INSERT INTO `archived_orders` SELECT * FROM `orders` WHERE created_at < ? ON DUPLICATE KEY UPDATE `total` = VALUES(total)", sql)
+	assert.Equal(t, []any{"2020-01-01"}, params)
+}
+
+func TestWithPostgreSQLPlaceholders(t *testing.T) {
+	config := &sqlc.QueryBuilderConfig{
+		StructTag:       "db",
+		Placeholder:     "$",
+		IdentifierQuote: `"`,
+	}
+
+	q := sqlc.With("active_users", sqlc.From("users").WithConfig(config).Where("status = ?", "active").Limit(10)).
+		WithConfig(config).
+		From("active_users").
+		Where("age > ?", 18).
+		Limit(5)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `WITH "active_users" AS (SELECT * FROM "users" WHERE status = $1 LIMIT $2) SELECT * FROM "active_users" WHERE age > $3 LIMIT $4`, sql)
+	assert.Equal(t, []any{"active", 10, 18, 5}, params)
+
+	update := sqlc.Update("users").
+		WithConfig(config).
+		With("inactive", sqlc.From("users").WithConfig(config).Columns("id").Where("last_login < ?", "2020-01-01")).
+		Set("status", "inactive").
+		Where("id IN (SELECT id FROM inactive)").
+		Where("role = ?", "user")
+
+	sql, params, err = update.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, `WITH "inactive" AS (SELECT "id" FROM "users" WHERE last_login < $1) UPDATE "users" SET "status" = $2 WHERE id IN (SELECT id FROM inactive) AND role = $3`, sql)
+	assert.Equal(t, []any{"2020-01-01", "inactive", "user"}, params)
+}
+
+func TestWithErrors(t *testing.T) {
+	_, _, err := sqlc.With("broken", sqlc.From("users").Columns(1)).From("broken").ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not build WITH clause broken")
+
+	_, _, err = sqlc.With("empty", nil).From("empty").ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "query must not be nil")
+
+	_, _, err = sqlc.Into("users").
+		With("paid", sqlc.From("orders")).
+		Columns("id").
+		Values(1).
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "WITH clause on INSERT requires FromSelect")
+
+	_, _, err = sqlc.Into("users").
+		FromSelect(sqlc.From("orders")).
+		Columns("id").
+		Values(1).
+		ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "FromSelect can not be combined")
+}
+
+func TestWithGeneric(t *testing.T) {
+	q := sqlc.FromG[TestUser]("active_users").
+		With("active_users", sqlc.From("users").Where("status = ?", "active")).
+		Where("age > ?", 18)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?) SELECT * FROM `active_users` WHERE age > ?", sql)
+	assert.Equal(t, []any{"active", 18}, params)
+}
+
+func TestWithRecursiveUnionAll(t *testing.T) {
+	anchor := sqlc.From("categories").Columns("id", "parent_id").Where("id = ?", 1)
+	children := sqlc.From("categories").As("c").
+		Columns("c.id", "c.parent_id").
+		Join("tree t", "c.parent_id = t.id").
+		Where("c.active = ?", true)
+
+	q := sqlc.WithRecursive("tree", anchor.UnionAll(children), "id", "parent_id").
+		From("tree").
+		Where("id != ?", 1)
+
+	sql, params, err := q.ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "WITH RECURSIVE `tree` (`id`, `parent_id`) AS ("+
+		"SELECT `id`, `parent_id` FROM `categories` WHERE id = ? "+
+		"UNION ALL SELECT `c`.`id`, `c`.`parent_id` FROM `categories` AS c INNER JOIN `tree` AS t ON c.parent_id = t.id WHERE c.active = ?"+
+		") SELECT * FROM `tree` WHERE id != ?", sql)
+	assert.Equal(t, []any{1, true, 1}, params)
+}
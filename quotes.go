@@ -48,6 +48,22 @@ func quoteIdentifier(name string, quote string) string {
 	return quote + name + quote
 }
 
+// quoteTableWithAlias quotes a table name which may be followed by an optional alias.
+// Both "orders o" and "orders AS o" are rendered as "`orders` AS o".
+// The alias is left unquoted, matching the rendering of SelectQueryBuilder.As().
+func quoteTableWithAlias(table string, quote string) string {
+	parts := strings.Fields(table)
+
+	switch {
+	case len(parts) == 2:
+		return quoteIdentifier(parts[0], quote) + " AS " + parts[1]
+	case len(parts) == 3 && strings.EqualFold(parts[1], "AS"):
+		return quoteIdentifier(parts[0], quote) + " AS " + parts[2]
+	default:
+		return quoteIdentifier(strings.TrimSpace(table), quote)
+	}
+}
+
 // quoteOrderByClause handles ORDER BY clauses which may contain "column DESC" or "column ASC"
 func quoteOrderByClause(clause string, quote string) string {
 	// Use default quote if not specified
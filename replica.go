@@ -0,0 +1,161 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/exec"
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+type primaryCtxKey struct{}
+
+// ContextWithPrimary returns a copy of ctx which sends all reads to the primary instead of a replica.
+// Use it to read your own writes, which might not have been replicated yet, and for raw statements
+// writing rows which do not start with INSERT, REPLACE, UPDATE or DELETE, e.g. WITH ... UPDATE ... RETURNING.
+//
+// Example:
+//
+//	if _, err := client.Q().Into("orders").Records(order).Exec(ctx); err != nil {
+//		return err
+//	}
+//	err := client.Q().From("orders").Where("id = ?", order.ID).Get(sqlc.ContextWithPrimary(ctx), &order)
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+func readsFromPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryCtxKey{}).(bool)
+
+	return primary
+}
+
+// replicaPool distributes reads round-robin over the healthy replicas.
+// Replicas failing with a connection error are evicted for the eviction duration.
+type replicaPool struct {
+	logger           log.Logger
+	replicas         []*replica
+	next             atomic.Uint64
+	evictionDuration time.Duration
+}
+
+type replica struct {
+	*baseQuerier
+	db           *sqlx.DB
+	index        int
+	evictedUntil *atomic.Int64 // unix nanos, 0 if healthy, shared with the copies of the pool
+}
+
+func newReplicaPool(primary *baseQuerier, connections []*sqlx.DB, evictionDuration time.Duration) *replicaPool {
+	if len(connections) == 0 {
+		return nil
+	}
+
+	pool := &replicaPool{
+		logger:           primary.logger.WithChannel("sqlc-replicas"),
+		replicas:         make([]*replica, 0, len(connections)),
+		evictionDuration: evictionDuration,
+	}
+
+	for i, connection := range connections {
+		pool.replicas = append(pool.replicas, &replica{
+			baseQuerier:  primary.withDb(primary.executor, connection),
+			db:           connection,
+			index:        i,
+			evictedUntil: &atomic.Int64{},
+		})
+	}
+
+	return pool
+}
+
+// withInterceptors returns a copy of the pool whose replicas pass every call through the interceptors.
+// The copy shares the evictions with the pool.
+func (p *replicaPool) withInterceptors(interceptors []Interceptor) *replicaPool {
+	if p == nil {
+		return nil
+	}
+
+	pool := &replicaPool{
+		logger:           p.logger,
+		replicas:         make([]*replica, 0, len(p.replicas)),
+		evictionDuration: p.evictionDuration,
+	}
+
+	for _, r := range p.replicas {
+		pool.replicas = append(pool.replicas, &replica{
+			baseQuerier:  r.baseQuerier.withInterceptors(interceptors),
+			db:           r.db,
+			index:        r.index,
+			evictedUntil: r.evictedUntil,
+		})
+	}
+
+	return pool
+}
+
+// pick returns the next healthy replica, or nil if reads have to go to the primary.
+func (p *replicaPool) pick(ctx context.Context) *replica {
+	if p == nil || readsFromPrimary(ctx) {
+		return nil
+	}
+
+	now := time.Now().UnixNano()
+	start := p.next.Add(1)
+
+	for i := range p.replicas {
+		r := p.replicas[(start+uint64(i))%uint64(len(p.replicas))]
+
+		if r.evictedUntil.Load() <= now {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// evictOnError evicts the replica if err indicates that it is not reachable
+// and reports whether it was evicted, in which case the read should be repeated on the primary.
+func (p *replicaPool) evictOnError(ctx context.Context, r *replica, err error) bool {
+	if !isReplicaFailure(err) {
+		return false
+	}
+
+	r.evictedUntil.Store(time.Now().Add(p.evictionDuration).UnixNano())
+	p.logger.Warn(ctx, "evicting replica %d for %s after error: %s", r.index, p.evictionDuration, err.Error())
+
+	return true
+}
+
+func (p *replicaPool) close() error {
+	if p == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, r := range p.replicas {
+		r.stmts.close()
+		errs = append(errs, r.db.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// isReplicaFailure reports whether err is caused by the connection to the database rather than by the query.
+func isReplicaFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		exec.IsConnectionError(err) ||
+		exec.IsTimeoutError(err) ||
+		exec.IsIoTimeoutError(err) ||
+		exec.IsUsedClosedConnectionError(err)
+}
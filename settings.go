@@ -39,9 +39,38 @@ import (
 //	    parameters:
 //	      sslmode: disable
 //	      connect_timeout: "10"
+//
+// Reads can be sent to read replicas, empty fields of a replica uri are taken from the primary uri:
+//
+//	sqlc:
+//	  main:
+//	    driver: mysql
+//	    uri:
+//	      host: primary.db.local
+//	      ...
+//	    replicas:
+//	      uris:
+//	        - host: replica-1.db.local
+//	        - host: replica-2.db.local
+//
+// Statements taking longer than the slow query threshold are logged at Warn:
+//
+//	sqlc:
+//	  main:
+//	    ...
+//	    slow_query_threshold: 500ms
+//	    slow_query_explain: true
+//
+// The statements run most frequently can be prepared once and reused, keeping the given number of statements:
+//
+//	sqlc:
+//	  main:
+//	    ...
+//	    statement_cache_size: 64
 type Settings struct {
 	Charset               string            `cfg:"charset" default:"utf8mb4"`
 	Collation             string            `cfg:"collation" default:"utf8mb4_general_ci"`
+	CursorKey             string            `cfg:"cursor_key"` // secret key the cursors of Paginate are signed with, Paginate is not available without it
 	ConnectionMaxIdleTime time.Duration     `cfg:"connection_max_idletime" default:"120s"`
 	ConnectionMaxLifetime time.Duration     `cfg:"connection_max_lifetime" default:"120s"`
 	Driver                string            `cfg:"driver"`
@@ -51,7 +80,11 @@ type Settings struct {
 	MultiStatements       bool              `cfg:"multi_statements" default:"true"`
 	Parameters            map[string]string `cfg:"parameters"`
 	ParseTime             bool              `cfg:"parse_time" default:"true"`
+	Replicas              SettingsReplicas  `cfg:"replicas"`
 	Retry                 SettingsRetry     `cfg:"retry"`
+	SlowQueryExplain      bool              `cfg:"slow_query_explain" default:"false"` // attach the plan of slow SELECT queries made with Get or Select to the slow query log
+	SlowQueryThreshold    time.Duration     `cfg:"slow_query_threshold" default:"0"`   // log statements taking longer at Warn, 0 disables the slow query log
+	StatementCacheSize    int               `cfg:"statement_cache_size" default:"0"`   // number of prepared statements reused per connection pool and transaction, 0 disables the statement cache
 	Timeouts              SettingsTimeout   `cfg:"timeouts"`
 	Uri                   SettingsUri       `cfg:"uri"`
 }
@@ -66,10 +99,21 @@ type SettingsUri struct {
 	Database string `cfg:"database" validation:"required"`
 }
 
+// SettingsReplicas describes the read replicas of a database.
+// A replica failing with a connection error is evicted from the pool for the EvictionDuration,
+// reads fall back to the primary while no replica is available.
+type SettingsReplicas struct {
+	Uris             []SettingsUri `cfg:"uris"`
+	EvictionDuration time.Duration `cfg:"eviction_duration" default:"30s"`
+}
+
 // SettingsRetry controls automatic retry behavior for database operations.
 // When enabled, failed operations will be retried according to the retry policy.
+// With transactions enabled, Client.WithTx re-runs the whole callback with a fresh transaction
+// after a deadlock or serialization failure, so the callback must be safe to run more than once.
 type SettingsRetry struct {
-	Enabled bool `cfg:"enabled" default:"false"`
+	Enabled      bool `cfg:"enabled" default:"false"`
+	Transactions bool `cfg:"transactions" default:"false"`
 }
 
 // SettingsTimeout contains various timeout settings for database operations.
@@ -84,6 +128,34 @@ type SettingsTimeout struct {
 	Timeout      time.Duration `cfg:"timeout" default:"0"`      // Timeout for establishing connections, aka dial timeout. The value must be a decimal number with a unit suffix ("ms", "s", "m", "h"), such as "30s", "0.5m" or "1m30s".
 }
 
+// ReplicaSettings returns the settings for connecting to the replica with the given index.
+// Empty fields of the replica uri are taken from the primary uri.
+func (s *Settings) ReplicaSettings(index int) *Settings {
+	replica := *s
+	uri := s.Replicas.Uris[index]
+
+	if uri.Host == "" {
+		uri.Host = s.Uri.Host
+	}
+	if uri.Port == 0 {
+		uri.Port = s.Uri.Port
+	}
+	if uri.User == "" {
+		uri.User = s.Uri.User
+	}
+	if uri.Password == "" {
+		uri.Password = s.Uri.Password
+	}
+	if uri.Database == "" {
+		uri.Database = s.Uri.Database
+	}
+
+	replica.Uri = uri
+	replica.Replicas = SettingsReplicas{}
+
+	return &replica
+}
+
 // ReadSettings reads database connection settings from the application configuration.
 // It looks for settings under the key "sqlg.<name>" in the configuration.
 // Returns an error if the configuration key doesn't exist or if unmarshalling fails.
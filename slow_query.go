@@ -0,0 +1,145 @@
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/justtrackio/gosoline/pkg/log"
+)
+
+// packagePrefix is the prefix of the functions of this package, which are skipped when looking for the caller of a query.
+var packagePrefix = reflect.TypeFor[client]().PkgPath() + "."
+
+type slowQueryLog struct {
+	logger    log.Logger
+	threshold time.Duration
+	explain   string // the EXPLAIN statement of the dialect, empty if no plan should be attached
+}
+
+// NewSlowQueryInterceptor returns an interceptor logging every call taking longer than the slow query threshold
+// of the settings at Warn with the SQL, the number of arguments, the duration and the location of the caller.
+// With slow query explain enabled, the plan of slow SELECT queries made with Get or Select is attached to the log.
+// Clients created from settings with a threshold install it already.
+//
+// Example:
+//
+//	client := sqlc.NewClientWithInterfaces(logger, connection, executor, qbConfig).
+//		WithInterceptors(sqlc.NewSlowQueryInterceptor(logger, &sqlc.Settings{Driver: sqlc.DriverMysql, SlowQueryThreshold: time.Second}))
+func NewSlowQueryInterceptor(logger log.Logger, settings *Settings) Interceptor {
+	slowQueries := &slowQueryLog{
+		logger:    logger.WithChannel("sqlc-slow-queries"),
+		threshold: settings.SlowQueryThreshold,
+	}
+
+	if settings.SlowQueryExplain {
+		slowQueries.explain = "EXPLAIN"
+		if settings.Driver == DriverSqlite {
+			slowQueries.explain = "EXPLAIN QUERY PLAN"
+		}
+	}
+
+	return slowQueries.intercept
+}
+
+func (l *slowQueryLog) intercept(ctx context.Context, call *Call, next Invoker) *CallResult {
+	result := next(ctx, call)
+	if result == nil || result.Duration < l.threshold {
+		return result
+	}
+
+	fields := log.Fields{
+		"sql":         call.Query,
+		"args_count":  len(call.Args),
+		"duration_ms": result.Duration.Milliseconds(),
+		"operation":   call.Operation,
+	}
+
+	if caller, ok := queryCaller(); ok {
+		fields["caller"] = fmt.Sprintf("%s:%d", caller.File, caller.Line)
+	}
+
+	if l.explainable(call) {
+		if plan, err := l.plan(ctx, call); err != nil {
+			fields["plan_error"] = err.Error()
+		} else {
+			fields["plan"] = plan
+		}
+	}
+
+	l.logger.WithFields(fields).Warn(ctx, "slow %s took %s: %s", call.Operation, result.Duration, call.Query)
+
+	return result
+}
+
+// explainable reports whether the plan of the call can be attached. Only finished SELECT queries are explained,
+// as the connection of a transaction is still busy while the rows of Query or QueryRow are read.
+func (l *slowQueryLog) explainable(call *Call) bool {
+	if l.explain == "" || call.querier == nil {
+		return false
+	}
+
+	if call.Operation != OperationGet && call.Operation != OperationSelect {
+		return false
+	}
+
+	return call.statementKind() == statementSelect
+}
+
+// plan explains the query on the querier it was made on, bypassing the interceptors and the executor.
+func (l *slowQueryLog) plan(ctx context.Context, call *Call) ([]string, error) {
+	rows, err := call.querier.db.QueryxContext(ctx, l.explain+" "+call.Query, call.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not explain query: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var plan []string
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return nil, fmt.Errorf("could not scan query plan: %w", err)
+		}
+
+		columns := make([]string, 0, len(values))
+		for _, value := range values {
+			if bytes, ok := value.([]byte); ok {
+				value = string(bytes)
+			}
+
+			columns = append(columns, fmt.Sprint(value))
+		}
+
+		plan = append(plan, strings.Join(columns, " | "))
+	}
+
+	return plan, rows.Err()
+}
+
+// queryCaller returns the first frame of the stack outside of this package which is calling into it, i.e. the code issuing the query.
+// Frames inside of baseQuerier.intercept, which include the interceptors of other packages, are skipped.
+func queryCaller() (runtime.Frame, bool) {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	intercepted := false
+
+	for {
+		frame, more := frames.Next()
+
+		switch {
+		case frame.Function == packagePrefix+"(*baseQuerier).intercept":
+			intercepted = true
+		case intercepted && !strings.HasPrefix(frame.Function, packagePrefix):
+			return frame, true
+		}
+
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
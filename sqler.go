@@ -1,11 +1,13 @@
 package sqlc
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/justtrackio/gosoline/pkg/funk"
+	"github.com/justtrackio/gosoline/pkg/refl"
 )
 
 type (
@@ -80,7 +82,17 @@ func (s *SqlerWhere) Where(condition any, params ...any) *SqlerWhere {
 		if v == nil {
 			return s
 		}
-		s.clauses = append(s.clauses, v.toConditionSQL(s.config.IdentifierQuote))
+		if err := v.validate(); err != nil {
+			s.err = fmt.Errorf("invalid Where condition: %w", err)
+
+			return s
+		}
+		if err := v.checkDialect(s.config); err != nil {
+			s.err = fmt.Errorf("invalid Where condition: %w", err)
+
+			return s
+		}
+		s.clauses = append(s.clauses, v.toConditionSQL(s.config))
 		s.params = append(s.params, v.collectParameters()...)
 	case Eq:
 		// Handle Eq map type - convert to expressions
@@ -105,7 +117,18 @@ func (s *SqlerWhere) Where(condition any, params ...any) *SqlerWhere {
 			expr = And(expressions...)
 		}
 
-		s.clauses = append(s.clauses, expr.toConditionSQL(s.config.IdentifierQuote))
+		if err := expr.validate(); err != nil {
+			s.err = fmt.Errorf("invalid Where condition: %w", err)
+
+			return s
+		}
+		if err := expr.checkDialect(s.config); err != nil {
+			s.err = fmt.Errorf("invalid Where condition: %w", err)
+
+			return s
+		}
+
+		s.clauses = append(s.clauses, expr.toConditionSQL(s.config))
 		s.params = append(s.params, expr.collectParameters()...)
 	default:
 		s.err = fmt.Errorf("invalid type for Where condition: expected string or *Expression, got %T", condition)
@@ -153,20 +176,7 @@ func (s *SqlerWhere) toSqlWithStartIndex(startIndex int) (query string, params [
 	}
 
 	sql := strings.Join(s.clauses, " AND ")
-
-	// If using default "?" placeholder, no need to replace
-	if s.config.Placeholder == "?" {
-		return sql, s.params, nil
-	}
-
-	// Replace all "?" placeholders with numbered placeholders
-	paramIndex := startIndex
-	for i := 0; i < len(s.params); i++ {
-		placeholder := s.config.PlaceholderFormat(paramIndex)
-		// Replace first occurrence of "?"
-		sql = strings.Replace(sql, "?", placeholder, 1)
-		paramIndex++
-	}
+	sql = numberPlaceholders(sql, len(s.params), startIndex, s.config)
 
 	return sql, s.params, nil
 }
@@ -225,7 +235,12 @@ func (s *SqlerGroupBy) GroupBy(cols ...any) *SqlerGroupBy {
 		case string:
 			s.clauses = append(s.clauses, quoteIdentifier(v, s.config.IdentifierQuote))
 		case *Expression:
-			s.clauses = append(s.clauses, v.toSQL(s.config.IdentifierQuote))
+			if err := v.checkDialect(s.config); err != nil {
+				s.err = fmt.Errorf("invalid GroupBy argument %d: %w", i, err)
+
+				return s
+			}
+			s.clauses = append(s.clauses, v.toSQL(s.config))
 		default:
 			s.err = fmt.Errorf("invalid type for GroupBy argument %d: expected string or *Expression, got %T", i, col)
 
@@ -322,7 +337,17 @@ func (s *SqlerHaving) Having(condition any, params ...any) *SqlerHaving {
 		if v == nil {
 			return s
 		}
-		s.clauses = append(s.clauses, v.toConditionSQL(s.config.IdentifierQuote))
+		if err := v.validate(); err != nil {
+			s.err = fmt.Errorf("invalid Having condition: %w", err)
+
+			return s
+		}
+		if err := v.checkDialect(s.config); err != nil {
+			s.err = fmt.Errorf("invalid Having condition: %w", err)
+
+			return s
+		}
+		s.clauses = append(s.clauses, v.toConditionSQL(s.config))
 		s.params = append(s.params, v.collectParameters()...)
 	default:
 		s.err = fmt.Errorf("invalid type for Having condition: expected string or *Expression, got %T", condition)
@@ -370,20 +395,7 @@ func (s *SqlerHaving) toSqlWithStartIndex(startIndex int) (query string, params
 	}
 
 	sql := strings.Join(s.clauses, " AND ")
-
-	// If using default "?" placeholder, no need to replace
-	if s.config.Placeholder == "?" {
-		return sql, s.params, nil
-	}
-
-	// Replace all "?" placeholders with numbered placeholders
-	paramIndex := startIndex
-	for i := 0; i < len(s.params); i++ {
-		placeholder := s.config.PlaceholderFormat(paramIndex)
-		// Replace first occurrence of "?"
-		sql = strings.Replace(sql, "?", placeholder, 1)
-		paramIndex++
-	}
+	sql = numberPlaceholders(sql, len(s.params), startIndex, s.config)
 
 	return sql, s.params, nil
 }
@@ -443,7 +455,12 @@ func (s *SqlerOrderBy) OrderBy(cols ...any) *SqlerOrderBy {
 		case string:
 			s.clauses = append(s.clauses, quoteOrderByClause(v, s.config.IdentifierQuote))
 		case *Expression:
-			s.clauses = append(s.clauses, v.toSQL(s.config.IdentifierQuote))
+			if err := v.checkDialect(s.config); err != nil {
+				s.err = fmt.Errorf("invalid OrderBy argument %d: %w", i, err)
+
+				return s
+			}
+			s.clauses = append(s.clauses, v.toSQL(s.config))
 		default:
 			s.err = fmt.Errorf("invalid type for OrderBy argument %d: expected string or *Expression, got %T", i, col)
 
@@ -475,3 +492,257 @@ func (s *SqlerOrderBy) ToSql() (query string, err error) {
 
 	return strings.Join(s.clauses, ", "), nil
 }
+
+// numberPlaceholders replaces the first count "?" placeholders in sql with the placeholder
+// format of the given config, starting at startIndex (0-based).
+// If the config uses the default "?" placeholder, the sql is returned unchanged.
+// This is used by all clauses which accept raw SQL fragments with "?" placeholders
+// to maintain parameter index continuity across the whole query.
+func numberPlaceholders(sql string, count int, startIndex int, config *QueryBuilderConfig) string {
+	if config == nil || config.Placeholder == "?" {
+		return sql
+	}
+
+	paramIndex := startIndex
+	for i := 0; i < count; i++ {
+		placeholder := config.PlaceholderFormat(paramIndex)
+		// Replace first occurrence of "?"
+		sql = strings.Replace(sql, "?", placeholder, 1)
+		paramIndex++
+	}
+
+	return sql
+}
+
+// SqlerWith handles WITH clause construction (common table expressions) for SQL queries.
+// It extracts the CTE logic to be reusable across different query builders.
+// If any of the CTEs is recursive, the clause is rendered as WITH RECURSIVE.
+type SqlerWith struct {
+	ctes      []sqlerWithCte
+	recursive bool
+	config    *QueryBuilderConfig
+	err       error
+}
+
+// sqlerWithCte is a single named common table expression.
+type sqlerWithCte struct {
+	name    string
+	columns []string
+	query   *SelectQueryBuilder
+}
+
+// NewSqlerWith creates a new SqlerWith instance.
+func NewSqlerWith() *SqlerWith {
+	return &SqlerWith{
+		ctes:   []sqlerWithCte{},
+		config: DefaultConfig(),
+	}
+}
+
+// IsEmpty returns true if no CTEs have been added.
+func (s *SqlerWith) IsEmpty() bool {
+	return len(s.ctes) == 0
+}
+
+// WithConfig sets the config for placeholder formatting and identifier quoting.
+// Returns the same SqlerWith instance for method chaining.
+func (s *SqlerWith) WithConfig(config *QueryBuilderConfig) *SqlerWith {
+	s.config = config
+	return s
+}
+
+// copy creates a copy of the SqlerWith with its own CTE slice.
+func (s *SqlerWith) copy() *SqlerWith {
+	return &SqlerWith{
+		ctes:      append([]sqlerWithCte{}, s.ctes...),
+		recursive: s.recursive,
+		config:    s.config,
+		err:       s.err,
+	}
+}
+
+// With adds a named common table expression.
+// The optional columns are rendered as the column list of the CTE.
+// Returns the same SqlerWith instance for method chaining.
+//
+// Example:
+//
+//	With("active_users", From("users").Where("status = ?", "active"))
+//	// WITH `active_users` AS (SELECT * FROM `users` WHERE status = ?)
+//	With("totals", From("orders").Columns("user_id", Col("total").Sum()).GroupBy("user_id"), "user_id", "total")
+//	// WITH `totals` (`user_id`, `total`) AS (SELECT `user_id`, SUM(`total`) FROM `orders` GROUP BY `user_id`)
+func (s *SqlerWith) With(name string, query *SelectQueryBuilder, columns ...string) *SqlerWith {
+	if name == "" {
+		s.err = errors.New("invalid WITH clause: name is required")
+
+		return s
+	}
+
+	if query == nil {
+		s.err = fmt.Errorf("invalid WITH clause %s: query must not be nil", name)
+
+		return s
+	}
+
+	s.ctes = append(s.ctes, sqlerWithCte{
+		name:    name,
+		columns: append([]string{}, columns...),
+		query:   query,
+	})
+
+	return s
+}
+
+// WithRecursive adds a named common table expression and marks the clause as recursive.
+// The query usually combines an anchor and a recursive part, referencing the CTE by name.
+// Returns the same SqlerWith instance for method chaining.
+//
+// Example:
+//
+//	anchor := From("categories").Columns("id", "parent_id").Where("id = ?", 1)
+//	children := From("categories").As("c").Columns("c.id", "c.parent_id").Join("tree t", "c.parent_id = t.id")
+//	WithRecursive("tree", anchor.UnionAll(children), "id", "parent_id")
+//	// WITH RECURSIVE `tree` (`id`, `parent_id`) AS (SELECT `id`, `parent_id` FROM `categories` WHERE id = ?
+//	//   UNION ALL SELECT `c`.`id`, `c`.`parent_id` FROM `categories` AS c INNER JOIN `tree` AS t ON c.parent_id = t.id)
+func (s *SqlerWith) WithRecursive(name string, query *SelectQueryBuilder, columns ...string) *SqlerWith {
+	s.recursive = true
+
+	return s.With(name, query, columns...)
+}
+
+// ToSql generates the WITH clause SQL fragment and parameter list.
+// Returns the WITH clause (including the "WITH" keyword), parameters, and any error encountered.
+// If there are no CTEs, it returns an empty string for the query.
+func (s *SqlerWith) ToSql() (query string, params []any, err error) {
+	return s.toSqlWithStartIndex(0)
+}
+
+// toSqlWithStartIndex generates the WITH clause SQL fragment with a custom starting parameter index.
+// This is used internally by query builders to maintain parameter index continuity across multiple clauses.
+func (s *SqlerWith) toSqlWithStartIndex(startIndex int) (query string, params []any, err error) {
+	if s.err != nil {
+		return "", nil, s.err
+	}
+
+	if len(s.ctes) == 0 {
+		return "", []any{}, nil
+	}
+
+	params = []any{}
+	parts := make([]string, len(s.ctes))
+
+	for i, cte := range s.ctes {
+		var cteSql string
+		var cteParams []any
+
+		if cteSql, cteParams, err = SubQuery(cte.query).subqueryToSql(s.config); err != nil {
+			return "", nil, fmt.Errorf("could not build WITH clause %s: %w", cte.name, err)
+		}
+
+		name := quoteIdentifier(cte.name, s.config.IdentifierQuote)
+		if len(cte.columns) > 0 {
+			quotedColumns := funk.Map(cte.columns, func(col string) string {
+				return quoteIdentifier(col, s.config.IdentifierQuote)
+			})
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(quotedColumns, ", "))
+		}
+
+		parts[i] = fmt.Sprintf("%s AS (%s)", name, cteSql)
+		params = append(params, cteParams...)
+	}
+
+	keyword := "WITH "
+	if s.recursive {
+		keyword = "WITH RECURSIVE "
+	}
+
+	sql := keyword + strings.Join(parts, ", ")
+	sql = numberPlaceholders(sql, len(params), startIndex, s.config)
+
+	return sql, params, nil
+}
+
+// ErrReturningNotSupported is returned when a RETURNING clause is built for a dialect
+// which does not support it (e.g. MySQL). It wraps ErrNotSupportedByDialect.
+var ErrReturningNotSupported = fmt.Errorf("RETURNING clause is %w", ErrNotSupportedByDialect)
+
+// SqlerReturning handles RETURNING clause construction for INSERT, UPDATE and DELETE queries.
+// It extracts the returning logic to be reusable across different query builders.
+// If the config has a dialect, the clause is rejected for dialects without RETURNING support.
+type SqlerReturning struct {
+	columns []string
+	config  *QueryBuilderConfig
+}
+
+// NewSqlerReturning creates a new SqlerReturning instance.
+func NewSqlerReturning() *SqlerReturning {
+	return &SqlerReturning{
+		columns: []string{},
+		config:  DefaultConfig(),
+	}
+}
+
+// IsEmpty returns true if no RETURNING columns have been added.
+func (s *SqlerReturning) IsEmpty() bool {
+	return len(s.columns) == 0
+}
+
+// WithConfig sets the config for identifier quoting and the dialect check.
+// Returns the same SqlerReturning instance for method chaining.
+func (s *SqlerReturning) WithConfig(config *QueryBuilderConfig) *SqlerReturning {
+	s.config = config
+	return s
+}
+
+// copy creates a copy of the SqlerReturning with its own column slice.
+func (s *SqlerReturning) copy() *SqlerReturning {
+	return &SqlerReturning{
+		columns: append([]string{}, s.columns...),
+		config:  s.config,
+	}
+}
+
+// Returning sets the columns of the RETURNING clause.
+// Replaces any previously set RETURNING columns.
+// Returns the same SqlerReturning instance for method chaining.
+//
+// Example:
+//
+//	Returning("id")                 // RETURNING `id`
+//	Returning("id", "created_at")   // RETURNING `id`, `created_at`
+//	Returning("*")                  // RETURNING *
+func (s *SqlerReturning) Returning(cols ...string) *SqlerReturning {
+	s.columns = append([]string{}, cols...)
+
+	return s
+}
+
+// ToSql generates the RETURNING clause SQL fragment.
+// Returns the RETURNING clause (without the "RETURNING" keyword), and any error encountered.
+// If there are no columns, it returns an empty string for the query.
+func (s *SqlerReturning) ToSql() (query string, err error) {
+	if len(s.columns) == 0 {
+		return "", nil
+	}
+
+	if dialect := dialectOf(s.config); dialect != nil && !dialect.SupportsReturning() {
+		return "", ErrReturningNotSupported
+	}
+
+	quotedColumns := funk.Map(s.columns, func(col string) string {
+		return quoteIdentifier(col, s.config.IdentifierQuote)
+	})
+
+	return strings.Join(quotedColumns, ", "), nil
+}
+
+// returningColumnsForType returns the RETURNING columns for the struct (or slice of structs) t,
+// derived from its struct tags. This is the RETURNING counterpart of SelectQueryBuilder.ForType().
+func returningColumnsForType(t any, config *QueryBuilderConfig) []string {
+	structTag := dbStructTag
+	if config != nil && config.StructTag != "" {
+		structTag = config.StructTag
+	}
+
+	return refl.GetTags(t, structTag)
+}
@@ -0,0 +1,295 @@
+package sqlc
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/metric"
+)
+
+type noStmtCacheCtxKey struct{}
+
+// ContextWithoutStmtCache returns a copy of ctx whose statements are executed without the statement cache
+// of the client. Use it for statements which are executed only once, so they do not evict the statements
+// of the hot paths, or which can not be prepared, so the cache does not try to prepare them on every call.
+// Control statements like SAVEPOINT, SET or LOCK TABLES are never cached.
+//
+// Example:
+//
+//	_, err := client.Exec(sqlc.ContextWithoutStmtCache(ctx), "ALTER TABLE `users` ADD COLUMN `age` INT")
+func ContextWithoutStmtCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noStmtCacheCtxKey{}, true)
+}
+
+func usesStmtCache(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noStmtCacheCtxKey{}).(bool)
+
+	return !disabled
+}
+
+// stmtCache is a size-bounded LRU cache of the prepared statements of a database or transaction, keyed by their SQL.
+// A statement evicted while in use is closed once the last call using it has finished.
+type stmtCache struct {
+	lck          sync.Mutex
+	size         int
+	entries      map[string]*list.Element
+	recency      *list.List // of *cachedStmt, the most recently used statement first
+	metricWriter metric.Writer
+	name         string
+}
+
+type cachedStmt struct {
+	query   string
+	stmt    *sqlx.Stmt
+	refs    int
+	evicted bool
+}
+
+func newStmtCache(size int, metricWriter metric.Writer, name string) *stmtCache {
+	return &stmtCache{
+		size:         size,
+		entries:      make(map[string]*list.Element, size),
+		recency:      list.New(),
+		metricWriter: metricWriter,
+		name:         name,
+	}
+}
+
+// fresh returns an empty cache with the settings of this cache, e.g. for a transaction or replica
+// with statements of their own. A nil cache stays disabled.
+func (c *stmtCache) fresh() *stmtCache {
+	if c == nil {
+		return nil
+	}
+
+	return newStmtCache(c.size, c.metricWriter, c.name)
+}
+
+// acquire returns the cached statement of the query, preparing it on db if it is not cached yet.
+// The statement has to be given back with release after use.
+func (c *stmtCache) acquire(ctx context.Context, db sqlxQuerier, query string) (*cachedStmt, error) {
+	c.lck.Lock()
+	if element, ok := c.entries[query]; ok {
+		entry := element.Value.(*cachedStmt)
+		entry.refs++
+		c.recency.MoveToFront(element)
+		c.lck.Unlock()
+
+		c.publish(ctx, stmtCacheHit)
+
+		return entry, nil
+	}
+	c.lck.Unlock()
+
+	c.publish(ctx, stmtCacheMiss)
+
+	// the statement is prepared without holding the lock, so other queries are not blocked by it
+	stmt, err := db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare statement: %w", err)
+	}
+
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	// another call prepared the same statement in the meantime
+	if element, ok := c.entries[query]; ok {
+		_ = stmt.Close()
+
+		entry := element.Value.(*cachedStmt)
+		entry.refs++
+		c.recency.MoveToFront(element)
+
+		return entry, nil
+	}
+
+	entry := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.entries[query] = c.recency.PushFront(entry)
+
+	for c.recency.Len() > c.size {
+		c.evict(ctx, c.recency.Back())
+	}
+
+	return entry, nil
+}
+
+// release gives back a statement returned by acquire, closing it if it has been evicted in the meantime.
+func (c *stmtCache) release(entry *cachedStmt) {
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		_ = entry.stmt.Close()
+	}
+}
+
+// evict removes the entry from the cache, closing its statement unless it is still in use. The lock has to be held.
+func (c *stmtCache) evict(ctx context.Context, element *list.Element) {
+	entry := c.recency.Remove(element).(*cachedStmt)
+	delete(c.entries, entry.query)
+
+	entry.evicted = true
+	if entry.refs == 0 {
+		_ = entry.stmt.Close()
+	}
+
+	c.publish(ctx, stmtCacheEviction)
+}
+
+// close closes all cached statements not in use, the others are closed when they are released.
+func (c *stmtCache) close() {
+	if c == nil {
+		return
+	}
+
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	for c.recency.Len() > 0 {
+		entry := c.recency.Remove(c.recency.Back()).(*cachedStmt)
+		delete(c.entries, entry.query)
+
+		entry.evicted = true
+		if entry.refs == 0 {
+			_ = entry.stmt.Close()
+		}
+	}
+}
+
+func (c *stmtCache) publish(ctx context.Context, typ string) {
+	c.metricWriter.WriteOne(ctx, &metric.Datum{
+		Priority:   metric.PriorityHigh,
+		MetricName: metricNameDbStmtCacheCount,
+		Dimensions: metric.Dimensions{
+			"Client": c.name,
+			"Type":   typ,
+		},
+		Unit:  metric.UnitCount,
+		Value: 1.0,
+	})
+}
+
+// uncachedKeywords are the leading keywords of control statements, e.g. the savepoints of nested transactions.
+// They are executed once per transaction or session and would only evict the statements of the hot paths.
+var uncachedKeywords = map[string]bool{
+	"begin":     true,
+	"commit":    true,
+	"load":      true,
+	"lock":      true,
+	"release":   true,
+	"rollback":  true,
+	"savepoint": true,
+	"set":       true,
+	"start":     true,
+	"unlock":    true,
+}
+
+// isCacheable returns false for control statements, which are executed without the statement cache.
+func isCacheable(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	keyword, _, _ := strings.Cut(query, " ")
+
+	return !uncachedKeywords[strings.ToLower(keyword)]
+}
+
+// cachedQuerier is a sqlxQuerier executing queries with the prepared statements of its cache.
+// Named queries, explicitly prepared statements and control statements are passed to the underlying querier,
+// as are queries which can not be prepared, e.g. multiple statements on MySQL or some DDL statements.
+// Inside a transaction, queries returning rows are passed to it as well: the statement of a transaction
+// is closed immediately when it is evicted, which would break rows still being read.
+type cachedQuerier struct {
+	sqlxQuerier
+	cache *stmtCache
+}
+
+func (q *cachedQuerier) ExecContext(ctx context.Context, query string, args ...any) (result sql.Result, err error) {
+	err = q.withStmt(ctx, isCacheable(query), query, func(stmt *sqlx.Stmt) error {
+		result, err = stmt.ExecContext(ctx, args...)
+
+		return err
+	}, func() error {
+		result, err = q.sqlxQuerier.ExecContext(ctx, query, args...)
+
+		return err
+	})
+
+	return result, err
+}
+
+func (q *cachedQuerier) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return q.withStmt(ctx, isCacheable(query), query, func(stmt *sqlx.Stmt) error {
+		return stmt.GetContext(ctx, dest, args...)
+	}, func() error {
+		return q.sqlxQuerier.GetContext(ctx, dest, query, args...)
+	})
+}
+
+func (q *cachedQuerier) QueryxContext(ctx context.Context, query string, args ...any) (rows *sqlx.Rows, err error) {
+	err = q.withStmt(ctx, q.cachesRows(query), query, func(stmt *sqlx.Stmt) error {
+		rows, err = stmt.QueryxContext(ctx, args...)
+
+		return err
+	}, func() error {
+		rows, err = q.sqlxQuerier.QueryxContext(ctx, query, args...)
+
+		return err
+	})
+
+	return rows, err
+}
+
+func (q *cachedQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	var row *sql.Row
+
+	_ = q.withStmt(ctx, q.cachesRows(query), query, func(stmt *sqlx.Stmt) error {
+		row = stmt.QueryRowContext(ctx, args...)
+
+		return nil
+	}, func() error {
+		row = q.sqlxQuerier.QueryRowContext(ctx, query, args...)
+
+		return nil
+	})
+
+	return row
+}
+
+func (q *cachedQuerier) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return q.withStmt(ctx, isCacheable(query), query, func(stmt *sqlx.Stmt) error {
+		return stmt.SelectContext(ctx, dest, args...)
+	}, func() error {
+		return q.sqlxQuerier.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// cachesRows returns true if the rows of the query may be read after its statement has been given back.
+// Closing a statement of the database is deferred by database/sql until its rows are closed, the one of a
+// transaction is not.
+func (q *cachedQuerier) cachesRows(query string) bool {
+	_, onDb := q.sqlxQuerier.(*sqlx.DB)
+
+	return onDb && isCacheable(query)
+}
+
+// withStmt runs cached with the cached statement of the query. The statement is given back as soon as
+// cached returns. If the query is not cacheable or can not be prepared, direct is run instead, which
+// executes the query with the underlying querier. Errors of the query itself are reported by it then.
+func (q *cachedQuerier) withStmt(ctx context.Context, cacheable bool, query string, cached func(stmt *sqlx.Stmt) error, direct func() error) error {
+	if !cacheable {
+		return direct()
+	}
+
+	entry, err := q.cache.acquire(ctx, q.sqlxQuerier, query)
+	if err != nil {
+		return direct()
+	}
+	defer q.cache.release(entry)
+
+	return cached(entry.stmt)
+}
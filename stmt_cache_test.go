@@ -0,0 +1,220 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gosoline-project/sqlc"
+	"github.com/jmoiron/sqlx"
+	"github.com/justtrackio/gosoline/pkg/exec"
+	logmocks "github.com/justtrackio/gosoline/pkg/log/mocks"
+	"github.com/justtrackio/gosoline/pkg/metric"
+	metricMocks "github.com/justtrackio/gosoline/pkg/metric/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newStmtCacheClient returns a client caching size statements and the counts of the cache metrics written by it.
+func newStmtCacheClient(t *testing.T, size int) (sqlc.Client, sqlmock.Sqlmock, map[string]int) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	counts := map[string]int{}
+	writer := metricMocks.NewWriter(t)
+	writer.EXPECT().WriteOne(mock.Anything, mock.Anything).Run(func(_ context.Context, datum *metric.Datum) {
+		assert.Equal(t, "DbStmtCacheCount", datum.MetricName)
+		assert.Equal(t, "main", datum.Dimensions["Client"])
+
+		counts[datum.Dimensions["Type"]] += int(datum.Value)
+	}).Maybe()
+
+	logger := logmocks.NewLoggerMock(logmocks.WithMockAll, logmocks.WithTestingT(t))
+	client := sqlc.NewClientWithStatementCache(logger, sqlx.NewDb(mockDB, "sqlmock"), exec.NewDefaultExecutor(), writer, "main", size, sqlc.DefaultConfig())
+
+	t.Cleanup(func() {
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	return client, sqlMock, counts
+}
+
+func TestStmtCache_ReusesStatements(t *testing.T) {
+	client, sqlMock, counts := newStmtCacheClient(t, 8)
+	ctx := context.Background()
+
+	get := sqlMock.ExpectPrepare(regexp.QuoteMeta("SELECT `id`, `name`, `email` FROM `users` WHERE id = ?"))
+	get.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John", "john@example.com"))
+	get.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(2, "Jane", "jane@example.com"))
+
+	update := sqlMock.ExpectPrepare(regexp.QuoteMeta("UPDATE users SET name = ? WHERE id = ?"))
+	update.ExpectExec().WithArgs("Jim", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	update.ExpectExec().WithArgs("Kim", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	for _, id := range []int{1, 2} {
+		var user TestUser
+		require.NoError(t, client.Q().From("users").Where("id = ?", id).Get(ctx, &user))
+		assert.Equal(t, id, user.ID)
+	}
+
+	for id, name := range []string{"Jim", "Kim"} {
+		_, err := client.Exec(ctx, "UPDATE users SET name = ? WHERE id = ?", name, id+1)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]int{"hit": 2, "miss": 2}, counts)
+}
+
+func TestStmtCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	client, sqlMock, counts := newStmtCacheClient(t, 2)
+	ctx := context.Background()
+
+	first := sqlMock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM sessions")).WillBeClosed()
+	first.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	second := sqlMock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM tokens"))
+	second.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	second.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	third := sqlMock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM events"))
+	third.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+
+	for _, query := range []string{"DELETE FROM sessions", "DELETE FROM tokens", "DELETE FROM tokens", "DELETE FROM events"} {
+		_, err := client.Exec(ctx, query)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]int{"hit": 1, "miss": 3, "eviction": 1}, counts)
+}
+
+func TestStmtCache_Tx(t *testing.T) {
+	client, sqlMock, counts := newStmtCacheClient(t, 8)
+	ctx := context.Background()
+
+	// the statements of the client can not be used by a transaction, so it prepares its own ones
+	sqlMock.ExpectPrepare(regexp.QuoteMeta("UPDATE counters SET hits = hits + 1")).
+		ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectBegin()
+	inTx := sqlMock.ExpectPrepare(regexp.QuoteMeta("UPDATE counters SET hits = hits + 1"))
+	inTx.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	inTx.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	_, err := client.Exec(ctx, "UPDATE counters SET hits = hits + 1")
+	require.NoError(t, err)
+
+	err = client.WithTx(ctx, func(cttx sqlc.Tx) error {
+		for range 2 {
+			if _, err := cttx.Exec(ctx, "UPDATE counters SET hits = hits + 1"); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"hit": 1, "miss": 2}, counts)
+}
+
+func TestStmtCache_PrepareError(t *testing.T) {
+	client, sqlMock, counts := newStmtCacheClient(t, 8)
+	ctx := context.Background()
+	prepareErr := errors.New("This command is not supported in the prepared statement protocol yet")
+
+	// statements which can not be prepared are executed without the cache
+	sqlMock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM sessions; DELETE FROM tokens")).WillReturnError(prepareErr)
+	sqlMock.ExpectExec(regexp.QuoteMeta("DELETE FROM sessions; DELETE FROM tokens")).WillReturnResult(sqlmock.NewResult(0, 2))
+	sqlMock.ExpectPrepare(regexp.QuoteMeta("SHOW TABLES")).WillReturnError(prepareErr)
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SHOW TABLES")).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("users"))
+
+	result, err := client.Exec(ctx, "DELETE FROM sessions; DELETE FROM tokens")
+	require.NoError(t, err)
+
+	rowsAffected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), rowsAffected)
+
+	var tables []string
+	require.NoError(t, client.Select(ctx, &tables, "SHOW TABLES"))
+	assert.Equal(t, []string{"users"}, tables)
+
+	assert.Equal(t, map[string]int{"miss": 2}, counts)
+}
+
+func TestStmtCache_ContextWithoutStmtCache(t *testing.T) {
+	client, sqlMock, counts := newStmtCacheClient(t, 8)
+
+	sqlMock.ExpectExec(regexp.QuoteMeta("ALTER TABLE `users` ADD COLUMN `age` INT")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := client.Exec(sqlc.ContextWithoutStmtCache(context.Background()), "ALTER TABLE `users` ADD COLUMN `age` INT")
+	require.NoError(t, err)
+
+	assert.Empty(t, counts)
+}
+
+func TestStmtCache_ControlStatements(t *testing.T) {
+	client, sqlMock, counts := newStmtCacheClient(t, 8)
+	ctx := context.Background()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM sessions")).
+		ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT `sqlc_savepoint_1`")).WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectCommit()
+
+	err := client.WithTx(ctx, func(cttx sqlc.Tx) error {
+		return cttx.WithTx(func(cttx sqlc.Tx) error {
+			_, err := cttx.Exec(ctx, "DELETE FROM sessions")
+
+			return err
+		})
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"miss": 1}, counts)
+}
+
+func TestStmtCache_TxRows(t *testing.T) {
+	client, sqlMock, counts := newStmtCacheClient(t, 1)
+	ctx := context.Background()
+
+	// the rows of a transaction are read from statements of their own, which can not be evicted while reading them
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM sessions")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	sqlMock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM sessions WHERE id = ?")).
+		ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	err := client.WithTx(ctx, func(cttx sqlc.Tx) error {
+		rows, err := cttx.Query(ctx, "SELECT id FROM sessions")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var ids []int
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+
+			if id == 1 {
+				if _, err := cttx.Exec(ctx, "DELETE FROM sessions WHERE id = ?", id); err != nil {
+					return err
+				}
+			}
+		}
+		assert.Equal(t, []int{1, 2}, ids)
+
+		return rows.Err()
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"miss": 1}, counts)
+}
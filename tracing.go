@@ -0,0 +1,126 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/justtrackio/gosoline/pkg/tracing"
+)
+
+const (
+	spanNameBeginTx     = "sqlc.BeginTx"
+	spanNameCommit      = "sqlc.Commit"
+	spanNameRollback    = "sqlc.Rollback"
+	spanNameTransaction = "sqlc.Transaction"
+)
+
+var noopTracer = tracing.NewNoopTracer()
+
+// queryTracer starts the spans of the database calls of a client, its transactions and its replicas.
+// A nil queryTracer starts disabled spans.
+type queryTracer struct {
+	tracer   tracing.Tracer
+	system   string
+	database string
+}
+
+func newQueryTracer(tracer tracing.Tracer, settings *Settings) *queryTracer {
+	system := settings.Driver
+	if system == DriverPostgres {
+		system = "postgresql"
+	}
+
+	return &queryTracer{
+		tracer:   tracer,
+		system:   system,
+		database: settings.Uri.Database,
+	}
+}
+
+// startSpan starts a child span of the span in ctx, annotated with the database system and name.
+func (t *queryTracer) startSpan(ctx context.Context, name string) (context.Context, tracing.Span) {
+	if t == nil {
+		return noopTracer.StartSubSpan(ctx, name)
+	}
+
+	ctx, span := t.tracer.StartSubSpan(ctx, name)
+	span.AddAnnotation("db.system", t.system)
+
+	if t.database != "" {
+		span.AddAnnotation("db.name", t.database)
+	}
+
+	return ctx, span
+}
+
+// startStatementSpan starts the span of a call, which is finished with the error of the call by finishSpan.
+func (t *queryTracer) startStatementSpan(ctx context.Context, call *Call) (context.Context, tracing.Span) {
+	ctx, span := t.startSpan(ctx, "sqlc."+string(call.Operation))
+	span.AddAnnotation("db.operation", call.statementKind())
+
+	if call.Table != "" {
+		span.AddAnnotation("db.sql.table", call.Table)
+	}
+
+	return ctx, span
+}
+
+// finishSpan records the error, if any, and finishes the span. sql.ErrNoRows is not a failure of the call.
+func finishSpan(span tracing.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.AddError(err)
+	}
+
+	span.Finish()
+}
+
+// normalizeStatement collapses whitespace and replaces string and numeric literals with ?, so statements
+// differing only in inlined values look the same and no values end up in the traces.
+func normalizeStatement(query string) string {
+	var builder strings.Builder
+	builder.Grow(len(query))
+
+	runes := []rune(strings.TrimSpace(query))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			for i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
+				i++
+			}
+			builder.WriteRune(' ')
+		case r == '\'':
+			// skip to the closing quote, '' is an escaped quote inside of the literal
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' {
+					i++
+				} else if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i++
+					} else {
+						break
+					}
+				}
+			}
+			builder.WriteRune('?')
+		case unicode.IsDigit(r) && (i == 0 || !isIdentifierRune(runes[i-1])):
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			builder.WriteRune('?')
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// isIdentifierRune reports whether r can be part of an identifier or a placeholder like $1.
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '`' || r == '"'
+}
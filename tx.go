@@ -2,14 +2,18 @@ package sqlc
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/justtrackio/gosoline/pkg/exec"
-	"github.com/justtrackio/gosoline/pkg/log"
 )
 
 type (
+	txCtxKey struct{}
+
 	Tx interface {
 		context.Context
 		Querier
@@ -18,25 +22,100 @@ type (
 		Rollback() error
 		SqlTx() *sqlx.Tx
 		WithContext(ctx context.Context) Tx
+		// WithTx executes the given function within a savepoint of the transaction.
+		// If the function returns an error, the transaction is rolled back to the savepoint
+		// and all changes made before the savepoint are kept. Otherwise, the savepoint is released.
+		WithTx(fn func(cttx Tx) error) error
+		// Savepoint creates a savepoint with the given name inside the transaction.
+		Savepoint(name string) error
+		// RollbackTo rolls the transaction back to the savepoint with the given name.
+		// The savepoint itself is kept and can be rolled back to again.
+		RollbackTo(name string) error
+		// Release removes the savepoint with the given name while keeping all changes made after it.
+		Release(name string) error
+		// OnCommit registers a function which is called after the transaction has been committed successfully.
+		OnCommit(fn func(ctx context.Context))
+		// OnRollback registers a function which is called after the transaction has been rolled back successfully.
+		// err is the error which caused the rollback, it is nil if Rollback was called directly.
+		OnRollback(fn func(ctx context.Context, err error))
 	}
 )
 
+// ContextWithTx returns a copy of ctx which carries tx as the ambient transaction.
+// The query methods of a client and the builders of its Q() use the ambient transaction of
+// the context they are called with, so repositories only need to pass the context along.
+// A Tx itself is a context carrying itself, so the ctx handed to WithTx callbacks works the same way.
+// Clients only use an ambient transaction started on their own connection, the statements of a client
+// of another database are executed outside of it.
+//
+// Example:
+//
+//	err := client.WithTx(ctx, func(tx sqlc.Tx) error {
+//		ctx := sqlc.ContextWithTx(ctx, tx)
+//
+//		return repo.CreateOrder(ctx, order) // uses client.Q() and runs inside tx
+//	})
+func ContextWithTx(ctx context.Context, tx Tx) context.Context {
+	return context.WithValue(ctx, txCtxKey{}, tx)
+}
+
+// ContextWithoutTx returns a copy of ctx without an ambient transaction. Queries using the
+// returned context run outside of any transaction found in ctx, e.g. to write audit logs
+// which should persist even if the transaction is rolled back.
+func ContextWithoutTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, txCtxKey{}, Tx(nil))
+}
+
+// TxFromContext returns the ambient transaction of ctx, if there is one.
+func TxFromContext(ctx context.Context) (Tx, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	tx, ok := ctx.Value(txCtxKey{}).(Tx)
+
+	return tx, ok && tx != nil
+}
+
 type tx struct {
 	*baseQuerier
-	ctx context.Context
-	tx  *sqlx.Tx
+	ctx        context.Context
+	db         *sqlx.DB // connection pool the transaction was started on
+	tx         *sqlx.Tx
+	qbConfig   *QueryBuilderConfig
+	savepoints *atomic.Int64 // counter for the names of the savepoints created by WithTx, shared by all copies of the tx
+	hooks      *txHooks      // shared by all copies of the tx
 }
 
-func newTx(ctx context.Context, logger log.Logger, executor exec.Executor, txx *sqlx.Tx) Tx {
+// txHooks are the functions registered with OnCommit and OnRollback.
+type txHooks struct {
+	lck        sync.Mutex
+	onCommit   []func(ctx context.Context)
+	onRollback []func(ctx context.Context, err error)
+}
+
+func newTx(ctx context.Context, querier *baseQuerier, db *sqlx.DB, txx *sqlx.Tx, qbConfig *QueryBuilderConfig) *tx {
 	return &tx{
-		baseQuerier: newBaseQuerier(logger, executor, txx),
+		baseQuerier: querier,
 		ctx:         ctx,
+		db:          db,
 		tx:          txx,
+		qbConfig:    qbConfig,
+		savepoints:  &atomic.Int64{},
+		hooks:       &txHooks{},
 	}
 }
 
 func (t *tx) WithContext(ctx context.Context) Tx {
-	return newTx(ctx, t.logger, t.executor, t.tx)
+	return &tx{
+		baseQuerier: t.baseQuerier,
+		ctx:         ctx,
+		db:          t.db,
+		tx:          t.tx,
+		qbConfig:    t.qbConfig,
+		savepoints:  t.savepoints,
+		hooks:       t.hooks,
+	}
 }
 
 func (t *tx) Deadline() (deadline time.Time, ok bool) {
@@ -52,21 +131,190 @@ func (t *tx) Err() error {
 }
 
 func (t *tx) Value(key any) any {
+	// a tx used as context carries itself as the ambient transaction
+	if _, ok := key.(txCtxKey); ok {
+		return Tx(t)
+	}
+
 	return t.ctx.Value(key)
 }
 
 func (t *tx) Q() *QueryBuilder {
-	return &QueryBuilder{client: t}
+	return NewQueryBuilder(t, t.qbConfig)
 }
 
+// Commit commits the transaction and calls the functions registered with OnCommit afterwards.
 func (t *tx) Commit() error {
-	return t.tx.Commit()
+	_, span := t.tracer.startSpan(t.ctx, spanNameCommit)
+	err := t.tx.Commit()
+	finishSpan(span, err)
+
+	if err != nil {
+		return err
+	}
+
+	onCommit, _ := t.hooks.take(0, 0)
+	for _, fn := range onCommit {
+		fn(t.ctx)
+	}
+
+	return nil
 }
 
+// Rollback rolls the transaction back and calls the functions registered with OnRollback afterwards.
 func (t *tx) Rollback() error {
-	return t.tx.Rollback()
+	return t.rollback(nil)
+}
+
+// rollback rolls the transaction back and passes the cause to the functions registered with OnRollback.
+func (t *tx) rollback(cause error) error {
+	_, span := t.tracer.startSpan(t.ctx, spanNameRollback)
+	err := t.tx.Rollback()
+	finishSpan(span, err)
+
+	if err != nil {
+		return err
+	}
+
+	_, onRollback := t.hooks.take(0, 0)
+	for _, fn := range onRollback {
+		fn(t.ctx, cause)
+	}
+
+	return nil
+}
+
+// OnCommit registers a function which is called after the transaction has been committed successfully,
+// e.g. to publish cache invalidations or messages only once the data is durable.
+// Functions registered within Tx.WithTx are dropped if the savepoint is rolled back.
+func (t *tx) OnCommit(fn func(ctx context.Context)) {
+	t.hooks.lck.Lock()
+	defer t.hooks.lck.Unlock()
+
+	t.hooks.onCommit = append(t.hooks.onCommit, fn)
+}
+
+// OnRollback registers a function which is called after the transaction has been rolled back successfully.
+// Functions registered within Tx.WithTx are already called if the savepoint is rolled back.
+func (t *tx) OnRollback(fn func(ctx context.Context, err error)) {
+	t.hooks.lck.Lock()
+	defer t.hooks.lck.Unlock()
+
+	t.hooks.onRollback = append(t.hooks.onRollback, fn)
+}
+
+// mark returns the number of registered functions, which is used to take the ones registered later on.
+func (h *txHooks) mark() (commits int, rollbacks int) {
+	h.lck.Lock()
+	defer h.lck.Unlock()
+
+	return len(h.onCommit), len(h.onRollback)
+}
+
+// take removes and returns the functions registered after the given mark.
+func (h *txHooks) take(commits int, rollbacks int) (onCommit []func(ctx context.Context), onRollback []func(ctx context.Context, err error)) {
+	h.lck.Lock()
+	defer h.lck.Unlock()
+
+	onCommit, h.onCommit = slices.Clone(h.onCommit[commits:]), h.onCommit[:commits]
+	onRollback, h.onRollback = slices.Clone(h.onRollback[rollbacks:]), h.onRollback[:rollbacks]
+
+	return onCommit, onRollback
 }
 
 func (t *tx) SqlTx() *sqlx.Tx {
 	return t.tx
 }
+
+// WithTx executes the given function within a savepoint of the transaction, which allows
+// code using WithTx to be composed with code which is already running inside a transaction.
+// If the function returns an error, the transaction is rolled back to the savepoint only.
+// If the function completes successfully, the savepoint is released and its changes are
+// committed together with the surrounding transaction.
+func (t *tx) WithTx(fn func(cttx Tx) error) error {
+	var err error
+	name := fmt.Sprintf("sqlc_savepoint_%d", t.savepoints.Add(1))
+
+	if err = t.Savepoint(name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	commits, rollbacks := t.hooks.mark()
+
+	if err = fn(t); err != nil {
+		if rbErr := t.RollbackTo(name); rbErr != nil {
+			return fmt.Errorf("savepoint rollback failed: %w (original error: %w)", rbErr, err)
+		}
+
+		// the changes of fn are gone, so its commit hooks must not run and its rollback hooks run now
+		_, onRollback := t.hooks.take(commits, rollbacks)
+		for _, hook := range onRollback {
+			hook(t.ctx, err)
+		}
+
+		if relErr := t.Release(name); relErr != nil {
+			return fmt.Errorf("savepoint release failed: %w (original error: %w)", relErr, err)
+		}
+
+		return err
+	}
+
+	if err = t.Release(name); err != nil {
+		return fmt.Errorf("savepoint release failed: %w", err)
+	}
+
+	return nil
+}
+
+// Savepoint creates a savepoint with the given name inside the transaction.
+// The name has to be a plain identifier consisting of letters, digits and underscores.
+func (t *tx) Savepoint(name string) error {
+	return t.execSavepoint("SAVEPOINT", name)
+}
+
+// RollbackTo rolls the transaction back to the savepoint with the given name,
+// discarding all changes made after the savepoint was created.
+func (t *tx) RollbackTo(name string) error {
+	return t.execSavepoint("ROLLBACK TO SAVEPOINT", name)
+}
+
+// Release removes the savepoint with the given name. Changes made after the savepoint
+// was created are kept and become part of the surrounding transaction.
+func (t *tx) Release(name string) error {
+	return t.execSavepoint("RELEASE SAVEPOINT", name)
+}
+
+// execSavepoint executes a savepoint statement, quoting the name with the identifier quote of the dialect.
+func (t *tx) execSavepoint(statement string, name string) error {
+	if !isPlainIdentifier(name) {
+		return fmt.Errorf("invalid savepoint name %q: only letters, digits and underscores are allowed", name)
+	}
+
+	quote := ""
+	if t.qbConfig != nil {
+		quote = t.qbConfig.IdentifierQuote
+	}
+
+	_, err := t.Exec(t.ctx, fmt.Sprintf("%s %s", statement, quoteIdentifier(name, quote)))
+
+	return err
+}
+
+// isPlainIdentifier reports whether name is a non-empty identifier consisting of
+// ASCII letters, digits and underscores which does not start with a digit.
+func isPlainIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}